@@ -2,18 +2,30 @@ package config_cmd
 
 import (
 	"fmt"
-	"os"
 
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 
 	"github.com/HideyoshiNakazone/tracko/external/cmd/config_cmd/repo_cmd"
+	"github.com/HideyoshiNakazone/tracko/external/flags"
 	"github.com/HideyoshiNakazone/tracko/lib/config_handler"
 )
 
 var ConfigCmd = &cobra.Command{
-	Use:  "config",
-	Long: `Manage the configuration of the Tracko CLI.`,
+	Use: "config",
+	Long: `Manage the configuration of the Tracko CLI.
+
+Every field of the config file can be overridden by a TRACKO_* env var named
+after its key, like TRACKO_DB_PATH or TRACKO_AUTHOR_EMAILS. Lists of values
+are comma separated, lists of objects are JSON arrays and the fields of their
+elements are overridden by index, like TRACKO_TARGETS_0_PATH. Flags take
+precedence over env vars, which take precedence over the config file, which
+takes precedence over the defaults. TRACKO_CONFIG_PATH chooses the config
+file when --config is not set.
+
+Without a subcommand the effective config is shown along with where every
+value comes from.`,
 	RunE: runConfig,
 }
 
@@ -25,16 +37,29 @@ func runConfig(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("no valid config found: %w", err)
 	}
 
-	// Create table
-	table := tablewriter.NewWriter(os.Stdout)
-	table.Header([]string{"Field", "Value"})
+	sources, err := config_handler.ReadSources()
+	if err != nil {
+		return fmt.Errorf("no valid config found: %w", err)
+	}
+
+	// Create table, with where every effective value comes from
+	table := tablewriter.NewWriter(cmd.OutOrStdout())
+	table.Header([]string{"Field", "Value", "Source"})
 
-	table.Append([]string{"Version", cfg.Version()})
-	table.Append([]string{"DBPath", cfg.DBPath()})
-	table.Append([]string{"Author Name", cfg.TrackedAuthor().Name()})
-	table.Append([]string{"Author Emails", fmt.Sprintf("%v", cfg.TrackedAuthor().Emails())})
-	table.Append([]string{"Target Repo", cfg.TargetRepo()})
-	table.Append([]string{"Tracked Repos", fmt.Sprintf("%v", cfg.TrackedRepos())})
+	table.Append([]string{"Config File", viper.ConfigFileUsed(), flags.GetConfigPathSource()})
+	table.Append([]string{"Version", cfg.Version(), sources.Of("version")})
+	table.Append([]string{"DBPath", cfg.DBPath(), sources.Of("db_path")})
+	table.Append([]string{"Author Name", cfg.TrackedAuthor().Name(), sources.Of("author.name")})
+	table.Append([]string{"Author Emails", fmt.Sprintf("%v", cfg.TrackedAuthor().Emails()), sources.Of("author.emails")})
+	table.Append([]string{"Tracked Repos", fmt.Sprintf("%v", cfg.TrackedRepos()), sources.Of("tracked_repos")})
+	table.Append([]string{"Sensitive Terms", fmt.Sprintf("%v", cfg.SensitiveTerms()), sources.Of("sensitive_terms")})
+	for i, target := range cfg.Targets() {
+		location, key := target.Path(), fmt.Sprintf("targets[%d].path", i)
+		if location == "" {
+			location, key = target.URL(), fmt.Sprintf("targets[%d].url", i)
+		}
+		table.Append([]string{fmt.Sprintf("Target %q", target.Name()), location, sources.Of(key)})
+	}
 
 	table.Render()
 
@@ -45,5 +70,7 @@ func init() {
 	ConfigCmd.AddCommand(ConfigInitCmd)
 	ConfigCmd.AddCommand(ConfigSetCmd)
 	ConfigCmd.AddCommand(ConfigGetCmd)
+	ConfigCmd.AddCommand(ConfigMigrateCmd)
+	ConfigCmd.AddCommand(ConfigValidateCmd)
 	ConfigCmd.AddCommand(repo_cmd.RepoCmd)
 }
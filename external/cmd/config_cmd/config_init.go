@@ -1,6 +1,8 @@
 package config_cmd
 
 import (
+	"os"
+
 	"github.com/HideyoshiNakazone/tracko/external/flags"
 	"github.com/HideyoshiNakazone/tracko/lib/config_handler"
 	"github.com/HideyoshiNakazone/tracko/lib/config_model"
@@ -13,6 +15,8 @@ var (
 	trackedAuthorName   string
 	trackedAuthorEmails []string
 	targetRepo          string
+	targetAuthorName    string
+	targetAuthorEmail   string
 )
 
 var ConfigInitCmd = &cobra.Command{
@@ -33,26 +37,54 @@ func runConfigInit(cmd *cobra.Command, args []string) error {
 	cmd.Println("Initializing configuration...")
 	var cfgBuilder = config_model.NewConfigBuilder()
 
+	// Flags take precedence over the TRACKO_* env vars, which take
+	// precedence over the prompts and defaults
+	fromEnv(&dbPath, "db_path")
 	if dbPath == "" {
 		cmd.Println("Using default database path: ", config_model.DefaultDBPath)
 		dbPath = config_model.DefaultDBPath
 	}
 	cfgBuilder.WithDBPath(dbPath)
 
+	fromEnv(&trackedAuthorName, "author.name")
 	if trackedAuthorName == "" {
 		utils.ReadStringInto("Git author name: ", &trackedAuthorName)
 	}
 	cfgBuilder.WithTrackedAuthorName(trackedAuthorName)
 
+	if value := os.Getenv(config_handler.EnvVar("author.emails")); len(trackedAuthorEmails) == 0 && value != "" {
+		trackedAuthorEmails = utils.SplitList(value)
+	}
 	if len(trackedAuthorEmails) == 0 {
 		utils.ReadStringSliceInto("Git author emails (comma-separated): ", &trackedAuthorEmails)
 	}
 	cfgBuilder.WithTrackedAuthorEmails(trackedAuthorEmails)
 
+	// The target is read from the env vars of the first target, like any
+	// other field of the config
+	fromEnv(&targetRepo, "targets[0].path")
+	fromEnv(&targetRepo, "targets[0].url")
 	if targetRepo == "" {
-		utils.ReadStringInto("Target repository (owner/repo): ", &targetRepo)
+		utils.ReadStringInto("Target repository (local path or clone URL): ", &targetRepo)
+	}
+	// Mirrored commits are authored as the identity of the public host, the
+	// tracked emails are usually corporate ones
+	fromEnv(&targetAuthorName, "targets[0].author.name")
+	if targetAuthorName == "" {
+		utils.ReadStringInto("Export author name: ", &targetAuthorName)
+	}
+	fromEnv(&targetAuthorEmail, "targets[0].author.email")
+	if targetAuthorEmail == "" {
+		utils.ReadStringInto("Export author email, as registered on the public host: ", &targetAuthorEmail)
 	}
-	cfgBuilder.WithTargetRepo(targetRepo)
+
+	// A path that does not exist yet is initialised by the first export, a
+	// URL is cloned into the target cache
+	target := config_model.NewTargetBuilder(config_model.DefaultTargetName, targetRepo).WithCreate(true)
+	if config_model.IsRepoURL(targetRepo) {
+		target = config_model.NewTargetBuilder(config_model.DefaultTargetName, "").WithURL(targetRepo)
+	}
+	cfgBuilder.WithTarget(target.WithAuthor(targetAuthorName, targetAuthorEmail))
 
 	if cfg, err := cfgBuilder.Build(); err == nil {
 		config_handler.SetConfig(cfg)
@@ -61,6 +93,14 @@ func runConfigInit(cmd *cobra.Command, args []string) error {
 	return err
 }
 
+// fromEnv sets value from the env var of the config field at key, unless a
+// flag already set it.
+func fromEnv(value *string, key string) {
+	if *value == "" {
+		*value = os.Getenv(config_handler.EnvVar(key))
+	}
+}
+
 func afterConfigInit(cmd *cobra.Command, args []string) error {
 	_, err := config_handler.GetConfig()
 	if err != nil {
@@ -76,5 +116,7 @@ func init() {
 	ConfigInitCmd.Flags().StringVar(&dbPath, "db-path", "", "Path to the database file")
 	ConfigInitCmd.Flags().StringVar(&trackedAuthorName, "author-name", "", "Name of the author to track")
 	ConfigInitCmd.Flags().StringSliceVar(&trackedAuthorEmails, "author-emails", []string{}, "Emails of the authors to track")
-	ConfigInitCmd.Flags().StringVar(&targetRepo, "target-repo", "", "Target repository (owner/repo)")
+	ConfigInitCmd.Flags().StringVar(&targetRepo, "target-repo", "", "Target repository, a local path or a clone URL")
+	ConfigInitCmd.Flags().StringVar(&targetAuthorName, "target-author-name", "", "Name mirrored commits are authored as")
+	ConfigInitCmd.Flags().StringVar(&targetAuthorEmail, "target-author-email", "", "Email mirrored commits are authored as, the one registered on the public host")
 }
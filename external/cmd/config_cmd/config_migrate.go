@@ -0,0 +1,70 @@
+package config_cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/HideyoshiNakazone/tracko/external/flags"
+	"github.com/HideyoshiNakazone/tracko/lib/config_handler"
+)
+
+var migrateDryRun bool
+
+var ConfigMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Rewrite the config file to the current schema",
+	Long: `Rewrite the config file to the current schema version, one migration at a
+time. The file is first copied next to itself as <file>.<timestamp>.bak.
+
+Outdated config files are also migrated when any other command loads them,
+use --dry-run to preview the migrated file without writing anything.`,
+	// The file is read as is, loading it would migrate it right away
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		config_handler.LocateConfig(flags.GetConfigPath())
+		return nil
+	},
+	RunE: runConfigMigrate,
+}
+
+func runConfigMigrate(cmd *cobra.Command, args []string) error {
+	var result *config_handler.MigrationResult
+	var err error
+	if migrateDryRun {
+		result, err = config_handler.PlanMigration()
+	} else {
+		result, err = config_handler.MigrateConfig()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to migrate config: %w", err)
+	}
+
+	if len(result.Applied) == 0 {
+		cmd.Printf("Config %s is up to date at %s.\n", result.Path, result.To)
+		return nil
+	}
+
+	if !migrateDryRun {
+		cmd.Printf("Migrated config %s from %s to %s, backup written to %s.\n", result.Path, result.From, result.To, result.Backup)
+		printMigrationNotes(cmd, result.Notes)
+		return nil
+	}
+
+	cmd.Printf("Would migrate config %s from %s to %s:\n", result.Path, result.From, result.To)
+	for _, migration := range result.Applied {
+		cmd.Printf("  %s -> %s: %s\n", migration.From, migration.To, migration.Description)
+	}
+	printMigrationNotes(cmd, result.Notes)
+	cmd.Printf("\n%s", result.Content)
+	return nil
+}
+
+func printMigrationNotes(cmd *cobra.Command, notes []string) {
+	for _, note := range notes {
+		cmd.Printf("Note: %s.\n", note)
+	}
+}
+
+func init() {
+	ConfigMigrateCmd.Flags().BoolVar(&migrateDryRun, "dry-run", false, "Print the migrated config without writing it")
+}
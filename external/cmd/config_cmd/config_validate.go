@@ -0,0 +1,53 @@
+package config_cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/HideyoshiNakazone/tracko/external/flags"
+	"github.com/HideyoshiNakazone/tracko/lib/config_handler"
+	"github.com/HideyoshiNakazone/tracko/lib/internal_errors"
+)
+
+var ConfigValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check the config file and list every problem",
+	Long: `Check the config file against every validation rule and list each violation
+with the path of its field, like targets[0].author.email.
+
+Missing fields, like the author of a target, and invalid emails are also
+rejected whenever the config is loaded. Tracked repos that are not git
+repositories, a database path that cannot be written and target repositories
+that do not exist and cannot be bootstrapped are only reported here, since
+they depend on the machine. Settings that are valid but likely wrong, like a
+target authored as a tracked email, are printed as warnings.`,
+	// The file is read as is, loading it would stop at the first problem
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		config_handler.LocateConfig(flags.GetConfigPath())
+		return nil
+	},
+	RunE: runConfigValidate,
+}
+
+func runConfigValidate(cmd *cobra.Command, args []string) error {
+	violations, warnings, err := config_handler.ValidateConfig()
+	if err != nil {
+		return fmt.Errorf("failed to validate config: %w", err)
+	}
+
+	for _, warning := range warnings {
+		cmd.PrintErrf("Warning: %s.\n", warning)
+	}
+	if len(violations) == 0 {
+		cmd.Printf("Config %s is valid.\n", viper.ConfigFileUsed())
+		return nil
+	}
+
+	cmd.Printf("Config %s has %d problems:\n", viper.ConfigFileUsed(), len(violations))
+	for _, violation := range violations {
+		cmd.Printf("  %s\n", violation)
+	}
+	return fmt.Errorf("%w: %d problems found", internal_errors.ErrInvalidConfig, len(violations))
+}
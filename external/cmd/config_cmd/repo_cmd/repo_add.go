@@ -18,7 +18,7 @@ var RepoAddCmd = &cobra.Command{
 
 
 func runRepoAdd(cmd *cobra.Command, args []string) error {
-	cfg, err := config_handler.GetConfig()
+	cfg, err := config_handler.GetFileConfig()
 	if err != nil {
 		return err
 	}
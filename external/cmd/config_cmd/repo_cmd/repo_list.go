@@ -12,10 +12,11 @@ var RepoListCmd = &cobra.Command{
 }
 
 func runRepoList(cmd *cobra.Command, args []string) error {
-	repos, err := config_handler.GetConfigAttr[[]any]("tracked_repos")
+	cfg, err := config_handler.GetConfig()
 	if err != nil {
 		return err
 	}
+	repos := cfg.TrackedRepos()
 
 	if len(repos) == 0 {
 		cmd.Println("No tracked repositories found.")
@@ -15,7 +15,7 @@ var RepoRemoveCmd = &cobra.Command{
 }
 
 func runRepoRemove(cmd *cobra.Command, args []string) error {
-	cfg, err := config_handler.GetConfig()
+	cfg, err := config_handler.GetFileConfig()
 	if err != nil {
 		return err
 	}
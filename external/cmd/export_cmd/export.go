@@ -0,0 +1,265 @@
+package export_cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/HideyoshiNakazone/tracko/lib/config_handler"
+	"github.com/HideyoshiNakazone/tracko/lib/config_model"
+	"github.com/HideyoshiNakazone/tracko/lib/data_export"
+	"github.com/HideyoshiNakazone/tracko/lib/export_handler"
+	"github.com/HideyoshiNakazone/tracko/lib/heatmap"
+	"github.com/HideyoshiNakazone/tracko/lib/internal_errors"
+	"github.com/HideyoshiNakazone/tracko/lib/push_handler"
+)
+
+var (
+	pushExport    bool
+	exportDryRun  bool
+	exportTarget  string
+	exportFormat  string
+	exportOut     string
+	exportRange   string
+	exportPalette string
+	exportRepos   []string
+)
+
+var ExportCmd = &cobra.Command{
+	Use: "export",
+	Long: `Export Git commit history to a repository.
+
+Mirrors the commits of the tracked author into every target, or only --target.
+--push audits each target afterwards, like export audit, and pushes the ones
+with nothing leaked. --dry-run lists the target commits without writing them. --format writes the tracked commits as data instead, to
+--out or to stdout, in csv, json, ndjson, parquet, ics, toggl, clockify,
+harvest, html, svg or openmetrics. --range and --palette shape the heatmaps
+and --repo keeps the commits of the given repos in the data export.`,
+	RunE: runExport,
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	cfg, err := config_handler.GetConfig()
+	if err != nil {
+		return fmt.Errorf("no valid config found: %w", err)
+	}
+
+	if exportFormat != "" {
+		if exportDryRun {
+			return fmt.Errorf("--dry-run cannot be combined with --format")
+		}
+		return runDataExport(cmd, cfg)
+	}
+	if len(exportRepos) > 0 {
+		return fmt.Errorf("--repo can only be combined with --format, targets choose their repos with targets[].repos")
+	}
+
+	targets, err := selectTargets(cfg)
+	if err != nil {
+		return err
+	}
+
+	for _, warning := range cfg.Warnings() {
+		cmd.PrintErrf("Warning: %s.\n", warning)
+	}
+
+	if exportDryRun {
+		return runDryRun(cmd, cfg, targets)
+	}
+
+	cmd.Println("Exporting Git commit history...")
+	for _, target := range targets {
+		result, err := export_handler.ExportTarget(cfg, target)
+		if err != nil {
+			return fmt.Errorf("failed to export target %q: %w", target.Name(), err)
+		}
+
+		cmd.Printf("[%s] Mirrored %d commits as %d target commits (%d already exported).\n", result.Target, result.Mirrored, result.Commits, result.Skipped)
+		if result.Readme {
+			cmd.Printf("[%s] Updated %s and %s.\n", result.Target, export_handler.ReadmePath, export_handler.BadgePath)
+		}
+
+		if !pushExport {
+			continue
+		}
+
+		audit, err := export_handler.Audit(cfg, target)
+		if err != nil {
+			return fmt.Errorf("failed to audit target %q: %w", target.Name(), err)
+		}
+		printAudit(cmd, audit)
+		if len(audit.Leaks) > 0 {
+			return fmt.Errorf("%w: %d findings, target %q was not pushed", internal_errors.ErrLeaksFound, len(audit.Leaks), target.Name())
+		}
+
+		pushResult, err := push_handler.Push(target)
+		if err != nil {
+			return fmt.Errorf("failed to push target %q: %w", target.Name(), err)
+		}
+
+		if pushResult.UpToDate {
+			cmd.Printf("[%s] Remote %s is already up to date.\n", result.Target, pushResult.Remote)
+			continue
+		}
+		cmd.Printf("[%s] Pushed %s to %s.\n", result.Target, pushResult.Branch, pushResult.Remote)
+	}
+
+	return nil
+}
+
+// runDataExport writes the tracked commits in --format to --out, or to
+// stdout when --out is not set. Site formats take --out as a directory.
+func runDataExport(cmd *cobra.Command, cfg *config_model.ConfigModel) error {
+	if exportOut == "" && (data_export.IsBinary(exportFormat) || data_export.IsSite(exportFormat)) {
+		return fmt.Errorf("--out is required for the %s format", exportFormat)
+	}
+
+	outPath := exportOut
+	if data_export.IsSite(exportFormat) {
+		if err := os.MkdirAll(exportOut, 0o755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", exportOut, err)
+		}
+		outPath = filepath.Join(exportOut, data_export.SiteIndex)
+	}
+
+	heatmapRange, err := heatmap.ParseRange(exportRange, time.Now())
+	if err != nil {
+		return err
+	}
+	palette, err := heatmap.ParsePalette(exportPalette)
+	if err != nil {
+		return err
+	}
+	options := data_export.Options{Range: heatmapRange, Palette: palette}
+
+	records, err := data_export.CollectCommits(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to read tracked commits: %w", err)
+	}
+	records = data_export.FilterRepos(records, exportRepos)
+
+	if exportOut == "" {
+		return data_export.Write(exportFormat, cmd.OutOrStdout(), cfg, records, options)
+	}
+
+	// The export is written next to --out and renamed over it, so that readers
+	// like node_exporter's textfile collector never see a partial file
+	file, err := os.CreateTemp(filepath.Dir(outPath), "."+filepath.Base(outPath)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", outPath, err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	if err := data_export.Write(exportFormat, file, cfg, records, options); err != nil {
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(file.Name(), 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(file.Name(), outPath); err != nil {
+		return fmt.Errorf("failed to write %s: %w", outPath, err)
+	}
+
+	cmd.Printf("Wrote %d commits to %s.\n", len(records), outPath)
+	return nil
+}
+
+// runDryRun prints the target commits every target would get and a calendar
+// of the target branch before and after, without writing to the targets.
+func runDryRun(cmd *cobra.Command, cfg *config_model.ConfigModel, targets []config_model.ConfigTargetModel) error {
+	heatmapRange, err := heatmap.ParseRange(exportRange, time.Now())
+	if err != nil {
+		return err
+	}
+
+	for _, target := range targets {
+		plan, err := export_handler.PlanTarget(cfg, target)
+		if err != nil {
+			return fmt.Errorf("failed to plan target %q: %w", target.Name(), err)
+		}
+
+		cmd.Printf("[%s] Would mirror %d commits as %d target commits (%d already exported).\n", plan.Target, plan.Mirrored, plan.Commits, plan.Skipped)
+		for _, commit := range plan.Planned {
+			if commit.Sources == 0 {
+				cmd.Printf("  %s  %s\n", commit.When.Format("2006-01-02 15:04 -0700"), commit.Subject)
+				continue
+			}
+			sources := "1 source commit"
+			if commit.Sources != 1 {
+				sources = fmt.Sprintf("%d source commits", commit.Sources)
+			}
+			cmd.Printf("  %s  %s (%s)\n", commit.When.Format("2006-01-02 15:04 -0700"), commit.Subject, sources)
+		}
+		if plan.Readme {
+			cmd.Printf("[%s] Would update %s and %s.\n", plan.Target, export_handler.ReadmePath, export_handler.BadgePath)
+		}
+
+		before, after := plan.DailyCounts()
+		busiest := 0
+		for _, count := range after {
+			busiest = max(busiest, count)
+		}
+
+		for _, calendar := range []struct {
+			label  string
+			counts map[string]int
+		}{{"Before", before}, {"After", after}} {
+			cmd.Printf("\n%s:\n", calendar.label)
+			if err := heatmap.RenderText(cmd.OutOrStdout(), calendar.counts, heatmapRange.From, heatmapRange.To, busiest); err != nil {
+				return err
+			}
+		}
+		cmd.Println()
+	}
+
+	return nil
+}
+
+// selectTargets returns the target chosen with --target, or every configured
+// target when the flag is not set. Partitioned targets are replaced by their
+// partitions, which can also be chosen one by one with --target.
+func selectTargets(cfg *config_model.ConfigModel) ([]config_model.ConfigTargetModel, error) {
+	selected := []config_model.ConfigTargetModel{}
+	for _, target := range cfg.Targets() {
+		partitions, err := export_handler.Partitions(cfg, target)
+		if err != nil {
+			return nil, fmt.Errorf("failed to partition target %q: %w", target.Name(), err)
+		}
+
+		for _, partition := range partitions {
+			if exportTarget == "" || exportTarget == target.Name() || exportTarget == partition.Name() {
+				selected = append(selected, partition)
+			}
+		}
+	}
+
+	if len(selected) == 0 && exportTarget != "" {
+		if _, err := cfg.Target(exportTarget); err != nil {
+			return nil, err
+		}
+	}
+	return selected, nil
+}
+
+func init() {
+	ExportCmd.AddCommand(ExportRevertCmd)
+	ExportCmd.AddCommand(ExportRebuildCmd)
+	ExportCmd.AddCommand(ExportAuditCmd)
+
+	ExportCmd.Flags().BoolVar(&pushExport, "push", false, "Audit the target branch after exporting and push it to its remote when nothing leaked")
+	ExportCmd.Flags().BoolVar(&exportDryRun, "dry-run", false, "List the target commits each target would get without writing them")
+	ExportCmd.Flags().StringVar(&exportFormat, "format", "", "Write the tracked commits as data instead of mirroring them, one of "+strings.Join(data_export.Formats(), ", "))
+	ExportCmd.Flags().StringVar(&exportOut, "out", "", "File to write the data export to, stdout by default")
+	ExportCmd.Flags().StringVar(&exportRange, "range", heatmap.RangeRolling, "Days of the heatmap: rolling, year, a year or FROM..TO")
+	ExportCmd.Flags().StringVar(&exportPalette, "palette", "green", "Heatmap palette, one of "+strings.Join(heatmap.PaletteNames(), ", ")+" or five comma separated hex colours")
+	ExportCmd.Flags().StringSliceVar(&exportRepos, "repo", nil, "Only write the commits of these repos with --format, by path or alias")
+	ExportCmd.PersistentFlags().StringVar(&exportTarget, "target", "", "Name of the target to export to, all targets by default")
+}
@@ -0,0 +1,68 @@
+package export_cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/HideyoshiNakazone/tracko/lib/config_handler"
+	"github.com/HideyoshiNakazone/tracko/lib/export_handler"
+	"github.com/HideyoshiNakazone/tracko/lib/internal_errors"
+)
+
+var ExportAuditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Scan the target history for leaked tracked data",
+	Long: `Scan the whole history of the target branch for anything that points back at
+the tracked repositories: hashes of their commits, their paths on disk, the
+tracked emails and the sensitive_terms of the config. Commit messages,
+trailers, authors and the content of every text file are scanned.
+
+Every finding is listed and the command exits non-zero. export --push runs the
+same audit after exporting and refuses to push a target with findings, the
+audit can also gate a push on its own once the export is written:
+
+  tracko export && tracko export audit && tracko export --push`,
+	RunE: runExportAudit,
+}
+
+func runExportAudit(cmd *cobra.Command, args []string) error {
+	cfg, err := config_handler.GetConfig()
+	if err != nil {
+		return fmt.Errorf("no valid config found: %w", err)
+	}
+
+	targets, err := selectTargets(cfg)
+	if err != nil {
+		return err
+	}
+
+	leaks := 0
+	for _, target := range targets {
+		result, err := export_handler.Audit(cfg, target)
+		if err != nil {
+			return fmt.Errorf("failed to audit target %q: %w", target.Name(), err)
+		}
+
+		printAudit(cmd, result)
+		leaks += len(result.Leaks)
+	}
+
+	if leaks > 0 {
+		return fmt.Errorf("%w: %d findings", internal_errors.ErrLeaksFound, leaks)
+	}
+	return nil
+}
+
+// printAudit prints the summary of an audit and every finding in it.
+func printAudit(cmd *cobra.Command, result *export_handler.AuditResult) {
+	if len(result.Leaks) == 0 {
+		cmd.Printf("[%s] Audited %d commits and %d files, nothing leaked.\n", result.Target, result.Commits, result.Files)
+		return
+	}
+
+	cmd.Printf("[%s] Audited %d commits and %d files, found %d leaks:\n", result.Target, result.Commits, result.Files, len(result.Leaks))
+	for _, leak := range result.Leaks {
+		cmd.Printf("  %s  %-11s  %s: %s\n", leak.Commit[:7], leak.Kind, leak.Location, leak.Match)
+	}
+}
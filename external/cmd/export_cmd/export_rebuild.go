@@ -0,0 +1,42 @@
+package export_cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/HideyoshiNakazone/tracko/lib/config_handler"
+	"github.com/HideyoshiNakazone/tracko/lib/export_handler"
+)
+
+var ExportRebuildCmd = &cobra.Command{
+	Use:   "rebuild",
+	Short: "Regenerate every mirrored commit on the target branch",
+	Long: `Remove every commit mirrored by tracko from the target branch and export again with the current config.
+The previous head is kept under refs/tracko/backup/.`,
+	RunE: runExportRebuild,
+}
+
+func runExportRebuild(cmd *cobra.Command, args []string) error {
+	cfg, err := config_handler.GetConfig()
+	if err != nil {
+		return fmt.Errorf("no valid config found: %w", err)
+	}
+
+	targets, err := selectTargets(cfg)
+	if err != nil {
+		return err
+	}
+
+	for _, target := range targets {
+		result, err := export_handler.Rebuild(cfg, target)
+		if err != nil {
+			return fmt.Errorf("failed to rebuild target %q: %w", target.Name(), err)
+		}
+
+		printRewrite(cmd, result)
+		cmd.Printf("[%s] Mirrored %d commits as %d target commits.\n", result.Target, result.Export.Mirrored, result.Export.Commits)
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package export_cmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/HideyoshiNakazone/tracko/lib/config_handler"
+	"github.com/HideyoshiNakazone/tracko/lib/config_model"
+	"github.com/HideyoshiNakazone/tracko/lib/export_handler"
+)
+
+var revertSince string
+
+var ExportRevertCmd = &cobra.Command{
+	Use:   "revert",
+	Short: "Remove mirrored commits from the target branch",
+	Long: `Remove the commits mirrored on or after --since from the target branch.
+Only commits created by tracko are removed, the previous head is kept under refs/tracko/backup/.`,
+	RunE: runExportRevert,
+}
+
+func runExportRevert(cmd *cobra.Command, args []string) error {
+	if revertSince == "" {
+		return errors.New("--since is required")
+	}
+	since, err := time.Parse(config_model.DateFormat, revertSince)
+	if err != nil {
+		return fmt.Errorf("invalid --since date, expected %s: %w", config_model.DateFormat, err)
+	}
+
+	cfg, err := config_handler.GetConfig()
+	if err != nil {
+		return fmt.Errorf("no valid config found: %w", err)
+	}
+
+	targets, err := selectTargets(cfg)
+	if err != nil {
+		return err
+	}
+
+	for _, target := range targets {
+		result, err := export_handler.Revert(target, since)
+		if err != nil {
+			return fmt.Errorf("failed to revert target %q: %w", target.Name(), err)
+		}
+		printRewrite(cmd, result)
+	}
+
+	return nil
+}
+
+func printRewrite(cmd *cobra.Command, result *export_handler.RewriteResult) {
+	if result.Backup == "" {
+		cmd.Printf("[%s] Nothing to revert.\n", result.Target)
+		return
+	}
+	cmd.Printf("[%s] Removed %d mirrored commits, replayed %d (backup at %s).\n", result.Target, result.Removed, result.Kept, result.Backup)
+}
+
+func init() {
+	ExportRevertCmd.Flags().StringVar(&revertSince, "since", "", "Remove commits mirrored on or after this date (YYYY-MM-DD)")
+}
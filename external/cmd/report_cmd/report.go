@@ -0,0 +1,14 @@
+package report_cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var ReportCmd = &cobra.Command{
+	Use:  "report",
+	Long: `Write reports of the tracked Git commit history.`,
+}
+
+func init() {
+	ReportCmd.AddCommand(ReportBragCmd)
+}
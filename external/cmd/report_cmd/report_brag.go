@@ -0,0 +1,95 @@
+package report_cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/HideyoshiNakazone/tracko/lib/config_handler"
+	"github.com/HideyoshiNakazone/tracko/lib/repo"
+	"github.com/HideyoshiNakazone/tracko/lib/report"
+)
+
+var (
+	bragPeriod   string
+	bragTemplate string
+	bragOut      string
+)
+
+var ReportBragCmd = &cobra.Command{
+	Use:   "brag",
+	Short: "Write a Markdown brag document of a period",
+	Long: `Write a Markdown brag document of the commits made during --period, grouped
+by repo and month, with commit counts, size stats, the feat commits, the
+largest commits and the most repeated subjects.
+
+--period is a year (2026), a half (2026-H1), a quarter (2026-Q1), a month
+(2026-01) or a range of days (2026-01-15..2026-04-30).
+
+--template replaces the default document with a Go text/template file. It is
+executed with the report data and can quote commit text with md, which
+escapes Markdown, and count with plural, as in {{plural .Stats.Commits "commit"}}.`,
+	RunE: runReportBrag,
+}
+
+func runReportBrag(cmd *cobra.Command, args []string) error {
+	if bragPeriod == "" {
+		return errors.New("--period is required")
+	}
+	period, err := report.ParsePeriod(bragPeriod)
+	if err != nil {
+		return err
+	}
+
+	templateText := ""
+	if bragTemplate != "" {
+		data, err := os.ReadFile(bragTemplate)
+		if err != nil {
+			return fmt.Errorf("failed to read template: %w", err)
+		}
+		templateText = string(data)
+	}
+	tmpl, err := report.ParseBragTemplate(templateText)
+	if err != nil {
+		return fmt.Errorf("invalid template: %w", err)
+	}
+
+	cfg, err := config_handler.GetConfig()
+	if err != nil {
+		return fmt.Errorf("no valid config found: %w", err)
+	}
+
+	records, err := repo.ReadTrackedCommits(cfg.TrackedRepos(), cfg.TrackedAuthor().Emails())
+	if err != nil {
+		return fmt.Errorf("failed to read tracked commits: %w", err)
+	}
+	data := report.NewBragData(cfg, period, records)
+
+	if bragOut == "" {
+		return report.WriteBrag(cmd.OutOrStdout(), tmpl, data)
+	}
+
+	file, err := os.Create(bragOut)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", bragOut, err)
+	}
+	defer file.Close()
+
+	if err := report.WriteBrag(file, tmpl, data); err != nil {
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	cmd.Printf("Wrote the %s brag document to %s.\n", period.Label, bragOut)
+	return nil
+}
+
+func init() {
+	ReportBragCmd.Flags().StringVar(&bragPeriod, "period", "", "Period of the report, like 2026, 2026-H1, 2026-Q1, 2026-01 or FROM..TO")
+	ReportBragCmd.Flags().StringVar(&bragTemplate, "template", "", "Go text/template file replacing the default document")
+	ReportBragCmd.Flags().StringVar(&bragOut, "out", "", "File to write the document to, stdout by default")
+}
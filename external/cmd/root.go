@@ -8,6 +8,8 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/HideyoshiNakazone/tracko/external/cmd/config_cmd"
+	"github.com/HideyoshiNakazone/tracko/external/cmd/export_cmd"
+	"github.com/HideyoshiNakazone/tracko/external/cmd/report_cmd"
 	"github.com/HideyoshiNakazone/tracko/external/flags"
 	"github.com/HideyoshiNakazone/tracko/lib/config_handler"
 	"github.com/HideyoshiNakazone/tracko/lib/internal_errors"
@@ -44,8 +46,9 @@ func initConfig(cmd *cobra.Command, args []string) error {
 
 func init() {
 	RootCmd.AddCommand(ImportCmd)
-	RootCmd.AddCommand(ExportCmd)
+	RootCmd.AddCommand(export_cmd.ExportCmd)
 	RootCmd.AddCommand(config_cmd.ConfigCmd)
+	RootCmd.AddCommand(report_cmd.ReportCmd)
 
 	RootCmd.PersistentFlags().StringVar(&flags.ConfigPath, "config", "", "Path to the config file")
 
@@ -16,7 +16,7 @@ func Test_ExecuteConfigGet(t *testing.T) {
 	expectedConfig, err := config_model.NewConfigBuilder().
 		WithDBPath("/tmp/test.db").
 		WithTrackedAuthor("Test User", []string{"test@example.com"}).
-		WithTargetRepo("test/repo").
+		WithTargetRepo("test/repo", "Test User", "test@personal.example.com").
 		Build()
 
 	if err != nil {
@@ -60,9 +60,51 @@ func Test_ExecuteConfigGet(t *testing.T) {
 			wantErr:        false,
 		},
 		{
-			name: "Get Target Repo",
-			key:  "target_repo",
-			expectedValue: expectedConfig.TargetRepo(),
+			name: "Get Target Path",
+			key:  "targets",
+			expectedValue: []any{map[string]any{
+				"name":             "default",
+				"path":             "test/repo",
+				"url":              "",
+				"create":           false,
+				"branch":           "",
+				"orphan":           false,
+				"repos":            []any{},
+				"since":            "",
+				"until":            "",
+				"message_template": "Mirrored contribution",
+				"strategy":         "one-to-one",
+				"max_per_day":      0,
+				"timezone":         "",
+				"content":          "empty",
+				"repo_names":       false,
+				"author": map[string]any{
+					"name":  "Test User",
+					"email": "test@personal.example.com",
+				},
+				"signing": map[string]any{
+					"format":         "none",
+					"key_path":       "",
+					"passphrase_env": "",
+				},
+				"push": map[string]any{
+					"remote":         "origin",
+					"auth":           "auto",
+					"username":       "",
+					"ssh_key_path":   "",
+					"passphrase_env": "",
+					"token_env":      "TRACKO_GIT_TOKEN",
+				},
+				"layout": map[string]any{
+					"split":  "",
+					"into":   "branch",
+					"groups": []any{},
+				},
+				"readme": map[string]any{
+					"enabled":  false,
+					"template": "",
+				},
+			}},
 			wantErr:        false,
 		},
 		{
@@ -3,9 +3,14 @@ package cmd
 import (
 	"bytes"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/spf13/pflag"
+
 	"github.com/HideyoshiNakazone/tracko/external/cmd"
+	"github.com/HideyoshiNakazone/tracko/external/cmd/config_cmd"
 )
 
 func Test_ExecuteConfigInit(t *testing.T) {
@@ -27,6 +32,8 @@ func Test_ExecuteConfigInit(t *testing.T) {
 			"--author-name", "Test User",
 			"--author-emails", "test@example.com",
 			"--target-repo", "test/repo",
+			"--target-author-name", "Test User",
+			"--target-author-email", "test@personal.example.com",
 		},
 	)
 
@@ -46,3 +53,56 @@ func Test_ExecuteConfigInit(t *testing.T) {
 		}
 	}
 }
+
+func resetConfigInitFlags() {
+	for _, name := range []string{"db-path", "author-name", "target-repo", "target-author-name", "target-author-email"} {
+		config_cmd.ConfigInitCmd.Flags().Set(name, "")
+	}
+	config_cmd.ConfigInitCmd.Flags().Lookup("author-emails").Value.(pflag.SliceValue).Replace([]string{})
+}
+
+func Test_ExecuteConfigInit_Env(t *testing.T) {
+	resetConfigInitFlags()
+	t.Cleanup(resetConfigInitFlags)
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+
+	// The flag takes precedence over the env var, which takes precedence
+	// over the prompts
+	t.Setenv("TRACKO_DB_PATH", "/tmp/env.db")
+	t.Setenv("TRACKO_AUTHOR_NAME", "Env User")
+	t.Setenv("TRACKO_AUTHOR_EMAILS", "env@example.com, ci@example.com")
+	t.Setenv("TRACKO_TARGETS_0_PATH", "env/repo")
+	t.Setenv("TRACKO_TARGETS_0_AUTHOR_NAME", "Env Personal")
+	t.Setenv("TRACKO_TARGETS_0_AUTHOR_EMAIL", "env@personal.example.com")
+
+	cmd_output := new(bytes.Buffer)
+	cmd.RootCmd.SetOut(cmd_output)
+	cmd.RootCmd.SetErr(cmd_output)
+	cmd.RootCmd.SetArgs(
+		[]string{
+			"config", "init",
+			"--config", configPath,
+			"--db-path", "/tmp/flag.db",
+		},
+	)
+
+	if err := cmd.RootCmd.Execute(); err != nil {
+		t.Fatalf("Command execution failed: %v\n%s", err, cmd_output.String())
+	}
+
+	content, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+
+	expectedContent := []string{"/tmp/flag.db", "Env User", "env@example.com", "ci@example.com", "path: env/repo", "Env Personal", "env@personal.example.com"}
+	for _, expected := range expectedContent {
+		if !strings.Contains(string(content), expected) {
+			t.Errorf("Expected the config to contain %q, got %s", expected, content)
+		}
+	}
+	if strings.Contains(string(content), "/tmp/env.db") {
+		t.Errorf("Expected --db-path to take precedence over TRACKO_DB_PATH, got %s", content)
+	}
+}
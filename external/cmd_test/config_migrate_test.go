@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/HideyoshiNakazone/tracko/external/cmd"
+	"github.com/HideyoshiNakazone/tracko/external/cmd/config_cmd"
+)
+
+func Test_ExecuteConfigMigrate(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	v1Config := "version: v1\ndb_path: /tmp/test.db\nauthor:\n  name: Test User\n  emails: [test@example.com]\ntargets:\n  - name: default\n    path: test/repo\ntracked_repos:\n  - /tmp/repo1\n"
+	if err := os.WriteFile(configPath, []byte(v1Config), 0o600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	t.Cleanup(func() {
+		config_cmd.ConfigMigrateCmd.Flags().Set("dry-run", "false")
+	})
+
+	tests := []struct {
+		name           string
+		args           []string
+		expectedOutput []string
+		expectedFile   string
+	}{
+		{"Dry run", []string{"config", "migrate", "--dry-run"}, []string{
+			"from v1 to v2:",
+			"  v1 -> v2: tracked_repos become objects with a path, target_repo becomes the default target",
+			"- path: /tmp/repo1",
+		}, v1Config},
+		{"Migrate", []string{"config", "migrate", "--dry-run=false"}, []string{
+			"from v1 to v2, backup written to " + configPath + ".",
+		}, ""},
+		{"Up to date", []string{"config", "migrate"}, []string{
+			"is up to date at v2.",
+		}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd.RootCmd.SetArgs(append([]string{"--config", configPath}, tt.args...))
+
+			cmd_output := new(bytes.Buffer)
+			cmd.RootCmd.SetOut(cmd_output)
+			cmd.RootCmd.SetErr(cmd_output)
+
+			if err := cmd.RootCmd.Execute(); err != nil {
+				t.Fatalf("Command execution failed: %v", err)
+			}
+
+			for _, expected := range tt.expectedOutput {
+				if !bytes.Contains(cmd_output.Bytes(), []byte(expected)) {
+					t.Errorf("Expected output to contain %q, but got %q", expected, cmd_output.String())
+				}
+			}
+			if content, _ := os.ReadFile(configPath); tt.expectedFile != "" && string(content) != tt.expectedFile {
+				t.Errorf("Expected the config untouched, got %q", content)
+			}
+		})
+	}
+}
+
+func Test_ExecuteConfigMigrate_TargetRepo(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	v1Config := "version: v1\ndb_path: /tmp/test.db\nauthor:\n  name: Test User\n  emails: [test@example.com]\ntarget_repo: test/repo\ntracked_repos: []\n"
+	if err := os.WriteFile(configPath, []byte(v1Config), 0o600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	cmd.RootCmd.SetArgs([]string{"--config", configPath, "config", "migrate"})
+
+	cmd_output := new(bytes.Buffer)
+	cmd.RootCmd.SetOut(cmd_output)
+	cmd.RootCmd.SetErr(cmd_output)
+
+	if err := cmd.RootCmd.Execute(); err != nil {
+		t.Fatalf("Command execution failed: %v", err)
+	}
+
+	expected := "Note: target_repo test/repo moved to targets[0], add targets[0].author"
+	if !bytes.Contains(cmd_output.Bytes(), []byte(expected)) {
+		t.Errorf("Expected output to contain %q, but got %q", expected, cmd_output.String())
+	}
+
+	content, _ := os.ReadFile(configPath)
+	if bytes.Contains(content, []byte("target_repo")) || !bytes.Contains(content, []byte("path: test/repo")) {
+		t.Errorf("Expected target_repo moved to targets, got %s", content)
+	}
+}
@@ -15,7 +15,7 @@ func Test_ExecuteConfigRepoAdd(t *testing.T) {
 	expectedConfig, err := config_model.NewConfigBuilder().
 		WithDBPath("/tmp/test.db").
 		WithTrackedAuthor("Test User", []string{"test@example.com"}).
-		WithTargetRepo("test/repo").
+		WithTargetRepo("test/repo", "Test User", "test@personal.example.com").
 		Build()
 
 	if err != nil {
@@ -58,7 +58,7 @@ func Test_ExecuteConfigRepoAdd_IfAlreadyAdded(t *testing.T) {
 	expectedConfig, err := config_model.NewConfigBuilder().
 		WithDBPath("/tmp/test.db").
 		WithTrackedAuthor("Test User", []string{"test@example.com"}).
-		WithTargetRepo("test/repo").
+		WithTargetRepo("test/repo", "Test User", "test@personal.example.com").
 		Build()
 
 	if err != nil {
@@ -97,7 +97,7 @@ func Test_ExecuteConfigRepoAdd_InvalidPath(t *testing.T) {
 	expectedConfig, err := config_model.NewConfigBuilder().
 		WithDBPath("/tmp/test.db").
 		WithTrackedAuthor("Test User", []string{"test@example.com"}).
-		WithTargetRepo("test/repo").
+		WithTargetRepo("test/repo", "Test User", "test@personal.example.com").
 		Build()
 
 	if err != nil {
@@ -15,7 +15,7 @@ func Test_ExecuteConfigRepoList(t *testing.T) {
 	expectedConfig, err := config_model.NewConfigBuilder().
 		WithDBPath("/tmp/test.db").
 		WithTrackedAuthor("Test User", []string{"test@example.com"}).
-		WithTargetRepo("test/repo").
+		WithTargetRepo("test/repo", "Test User", "test@personal.example.com").
 		WithTrackedRepos([]string{
 			"/path/to/your/repo1",
 			"/path/to/your/repo2",
@@ -15,7 +15,7 @@ func Test_ExecuteConfigRepoRemove(t *testing.T) {
 	expectedConfig, err := config_model.NewConfigBuilder().
 		WithDBPath("/tmp/test.db").
 		WithTrackedAuthor("Test User", []string{"test@example.com"}).
-		WithTargetRepo("test/repo").
+		WithTargetRepo("test/repo", "Test User", "test@personal.example.com").
 		WithTrackedRepos([]string{
 			"/tmp/repo1",
 		}).
@@ -61,7 +61,7 @@ func Test_ExecuteConfigRepoRemove_IfNotExists(t *testing.T) {
 	expectedConfig, err := config_model.NewConfigBuilder().
 		WithDBPath("/tmp/test.db").
 		WithTrackedAuthor("Test User", []string{"test@example.com"}).
-		WithTargetRepo("test/repo").
+		WithTargetRepo("test/repo", "Test User", "test@personal.example.com").
 		Build()
 
 	if err != nil {
@@ -96,7 +96,7 @@ func Test_ExecuteConfigRepoRemove_InvalidPath(t *testing.T) {
 	expectedConfig, err := config_model.NewConfigBuilder().
 		WithDBPath("/tmp/test.db").
 		WithTrackedAuthor("Test User", []string{"test@example.com"}).
-		WithTargetRepo("test/repo").
+		WithTargetRepo("test/repo", "Test User", "test@personal.example.com").
 		Build()
 
 	if err != nil {
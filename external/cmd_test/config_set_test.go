@@ -14,7 +14,7 @@ func Test_RunConfigSet(t *testing.T) {
 	expectedConfig, err := config_model.NewConfigBuilder().
 		WithDBPath("/tmp/test.db").
 		WithTrackedAuthor("Test User", []string{"test@example.com"}).
-		WithTargetRepo("test/repo").
+		WithTargetRepo("test/repo", "Test User", "test@personal.example.com").
 		Build()
 
 	if err != nil {
@@ -55,7 +55,7 @@ func Test_RunConfigSet_InvalidKey(t *testing.T) {
 	expectedConfig, err := config_model.NewConfigBuilder().
 		WithDBPath("/tmp/test.db").
 		WithTrackedAuthor("Test User", []string{"test@example.com"}).
-		WithTargetRepo("test/repo").
+		WithTargetRepo("test/repo", "Test User", "test@personal.example.com").
 		Build()
 
 	if err != nil {
@@ -91,7 +91,7 @@ func Test_RunConfigSet_RestrictedKey(t *testing.T) {
 	expectedConfig, err := config_model.NewConfigBuilder().
 		WithDBPath("/tmp/test.db").
 		WithTrackedAuthor("Test User", []string{"test@example.com"}).
-		WithTargetRepo("test/repo").
+		WithTargetRepo("test/repo", "Test User", "test@personal.example.com").
 		Build()
 
 	if err != nil {
@@ -126,7 +126,7 @@ func Test_RunConfigSet_RestrictedKey(t *testing.T) {
 		t.Fatalf("Failed to get config attribute: %v", err)
 	}
 
-	if actualVersion != "v1" {
-		t.Errorf("Expected version to be %q, but got %q", "v1", actualVersion)
+	if actualVersion != config_model.CurrentVersion {
+		t.Errorf("Expected version to be %q, but got %q", config_model.CurrentVersion, actualVersion)
 	}
 }
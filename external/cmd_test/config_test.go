@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"bytes"
+	"strings"
 	"testing"
 
 	"github.com/HideyoshiNakazone/tracko/external/cmd"
@@ -15,7 +16,7 @@ func Test_ExecuteConfigCommand(t *testing.T) {
 	expectedConfig, err := config_model.NewConfigBuilder().
 		WithDBPath("/tmp/test.db").
 		WithTrackedAuthor("Test User", []string{"test@example.com"}).
-		WithTargetRepo("test/repo").
+		WithTargetRepo("test/repo", "Test User", "test@personal.example.com").
 		Build()
 
 	if err != nil {
@@ -62,3 +63,93 @@ func Test_ExecuteConfigCommand(t *testing.T) {
 		})
 	}
 }
+
+func Test_ExecuteConfigCommand_Sources(t *testing.T) {
+	// Prepare config
+	expectedConfig, err := config_model.NewConfigBuilder().
+		WithDBPath("/tmp/test.db").
+		WithTrackedAuthor("Test User", []string{"test@example.com"}).
+		WithTargetRepo("test/repo", "Test User", "test@personal.example.com").
+		Build()
+
+	if err != nil {
+		t.Fatalf("Failed to build expected config: %v", err)
+	}
+
+	tempFile, tempCleanup, err := config_handler.PrepareTestConfig(expectedConfig)
+	if err != nil {
+		t.Fatalf("Failed to prepare test config: %v", err)
+	}
+	defer (*tempCleanup)()
+
+	t.Cleanup(func() {
+		cmd.RootCmd.PersistentFlags().Set("config", "")
+	})
+	t.Setenv("TRACKO_AUTHOR_NAME", "CI User")
+
+	tests := []struct {
+		name        string
+		args        []string
+		configPath  string
+		wantSources map[string]string
+	}{
+		{
+			name:       "flag over env",
+			args:       []string{"--config", tempFile.Name(), "config"},
+			configPath: "invalid.yaml",
+			wantSources: map[string]string{
+				"Config File": "flag",
+				"Author Name": "env",
+				"DBPath":      "file",
+			},
+		},
+		{
+			name:       "env config path",
+			args:       []string{"config"},
+			configPath: tempFile.Name(),
+			wantSources: map[string]string{
+				"Config File":     "env",
+				"Author Name":     "env",
+				"Author Emails":   "file",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd.RootCmd.PersistentFlags().Set("config", "")
+			t.Setenv("TRACKO_CONFIG_PATH", tt.configPath)
+			cmd.RootCmd.SetArgs(tt.args)
+
+			cmd_output := new(bytes.Buffer)
+			cmd.RootCmd.SetOut(cmd_output)
+			cmd.RootCmd.SetErr(cmd_output)
+
+			if err := cmd.RootCmd.Execute(); err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			output := cmd_output.String()
+			if !strings.Contains(output, "CI User") {
+				t.Errorf("Expected the env author name, got:\n%s", output)
+			}
+			for field, source := range tt.wantSources {
+				if !hasTableRow(output, field, source) {
+					t.Errorf("Expected %s to come from %s, got:\n%s", field, source, output)
+				}
+			}
+		})
+	}
+}
+
+// hasTableRow reports whether a row of the rendered table starts with field
+// and ends with source.
+func hasTableRow(output string, field string, source string) bool {
+	for _, line := range strings.Split(output, "\n") {
+		cells := strings.FieldsFunc(line, func(r rune) bool { return r == '|' || r == '│' })
+		if len(cells) >= 3 && strings.TrimSpace(cells[0]) == field && strings.TrimSpace(cells[len(cells)-1]) == source {
+			return true
+		}
+	}
+	return false
+}
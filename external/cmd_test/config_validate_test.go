@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/HideyoshiNakazone/tracko/external/cmd"
+	"github.com/HideyoshiNakazone/tracko/lib/internal_errors"
+	"github.com/HideyoshiNakazone/tracko/lib/repo"
+)
+
+func Test_ExecuteConfigValidate(t *testing.T) {
+	repoPath, repoCleanup, err := repo.PrepareTestRepository(nil)
+	if err != nil {
+		t.Fatalf("Failed to prepare repository: %v", err)
+	}
+	defer (*repoCleanup)()
+
+	dir := t.TempDir()
+	config := "version: v2\ndb_path: %s/tracko.db\nauthor:\n  name: Test User\n  emails: [%s]\ntargets:\n  - name: default\n    path: %s\n    author:\n      name: Test User\n      email: %s\ntracked_repos:\n  - path: %s\n"
+
+	validPath := filepath.Join(dir, "valid.yaml")
+	os.WriteFile(validPath, []byte(fmt.Sprintf(config, dir, "test@example.com", repoPath, "test@personal.example.com", repoPath)), 0o600)
+	invalidPath := filepath.Join(dir, "invalid.yaml")
+	os.WriteFile(invalidPath, []byte(fmt.Sprintf(config, dir, "test.example.com", repoPath, "test@personal.example.com", dir)), 0o600)
+	warningPath := filepath.Join(dir, "warning.yaml")
+	os.WriteFile(warningPath, []byte(fmt.Sprintf(config, dir, "test@example.com", repoPath, "test@example.com", repoPath)), 0o600)
+	readmePath := filepath.Join(dir, "readme.yaml")
+	os.WriteFile(readmePath, []byte(fmt.Sprintf(config, dir, "test@example.com", repoPath, "test@personal.example.com\n    readme:\n      enabled: true\n      template: "+filepath.Join(dir, "missing.tmpl"), repoPath)), 0o600)
+	noAuthorPath := filepath.Join(dir, "no-author.yaml")
+	os.WriteFile(noAuthorPath, []byte(fmt.Sprintf("version: v2\ndb_path: %s/tracko.db\nauthor:\n  name: Test User\n  emails: [test@example.com]\ntargets:\n  - name: default\n    path: %s\ntracked_repos:\n  - path: %s\n", dir, repoPath, repoPath)), 0o600)
+
+	tests := []struct {
+		name           string
+		configPath     string
+		expectedErr    error
+		expectedOutput []string
+	}{
+		{"Valid", validPath, nil, []string{"Config " + validPath + " is valid."}},
+		{"Invalid", invalidPath, internal_errors.ErrInvalidConfig, []string{
+			"Config " + invalidPath + " has 2 problems:",
+			`  author.emails[0]: "test.example.com" is not a valid email`,
+			"  tracked_repos[0].path: " + dir + " is not a git repository",
+		}},
+		{"Tracked email as author", warningPath, nil, []string{
+			`Warning: target "default" exports as test@example.com, which is a tracked email.`,
+			"Config " + warningPath + " is valid.",
+		}},
+		{"Broken readme template", readmePath, internal_errors.ErrInvalidConfig, []string{
+			"Config " + readmePath + " has 1 problems:",
+			`target "default": invalid readme.template`,
+		}},
+		{"Target without author", noAuthorPath, internal_errors.ErrInvalidConfig, []string{
+			"Config " + noAuthorPath + " has 2 problems:",
+			"  targets[0].author.name: is required",
+			"  targets[0].author.email: is required",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd.RootCmd.SetArgs([]string{"--config", tt.configPath, "config", "validate"})
+
+			cmd_output := new(bytes.Buffer)
+			cmd.RootCmd.SetOut(cmd_output)
+			cmd.RootCmd.SetErr(cmd_output)
+
+			if err := cmd.RootCmd.Execute(); !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			for _, expected := range tt.expectedOutput {
+				if !bytes.Contains(cmd_output.Bytes(), []byte(expected)) {
+					t.Errorf("Expected output to contain %q, but got %q", expected, cmd_output.String())
+				}
+			}
+		})
+	}
+
+	// Other commands refuse the invalid email on load
+	cmd.RootCmd.SetArgs([]string{"--config", invalidPath, "config"})
+	if err := cmd.RootCmd.Execute(); !errors.Is(err, internal_errors.ErrInvalidConfig) {
+		t.Errorf("Expected the config to be refused on load, got %v", err)
+	}
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/HideyoshiNakazone/tracko/external/cmd"
+	"github.com/HideyoshiNakazone/tracko/lib/config_handler"
+	"github.com/HideyoshiNakazone/tracko/lib/config_model"
+	"github.com/HideyoshiNakazone/tracko/lib/internal_errors"
+	"github.com/HideyoshiNakazone/tracko/lib/repo"
+)
+
+func Test_ExecuteExportAudit(t *testing.T) {
+	base := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	sourcePath, sourceCleanup, err := repo.PrepareTestRepository([]repo.TestCommit{
+		{Author: "Test User", Email: "test@example.com", Message: "feat: first", When: base},
+		{Author: "Test User", Email: "test@example.com", Message: "fix: second", When: base.Add(time.Hour)},
+	})
+	if err != nil {
+		t.Fatalf("Failed to prepare source repository: %v", err)
+	}
+	defer (*sourceCleanup)()
+
+	targetPath, targetCleanup, err := repo.PrepareTestRepository(nil)
+	if err != nil {
+		t.Fatalf("Failed to prepare target repository: %v", err)
+	}
+	defer (*targetCleanup)()
+
+	// Both configs export the same way, the second one also forbids a word of
+	// the default message template
+	configs := map[string]string{}
+	for name, terms := range map[string][]string{"clean": {}, "leaky": {"Contribution"}} {
+		config, err := config_model.NewConfigBuilder().
+			WithDBPath("/tmp/test.db").
+			WithTrackedAuthor("Test User", []string{"test@example.com"}).
+			WithTarget(config_model.NewTargetBuilder(config_model.DefaultTargetName, targetPath).
+				WithAuthor("Personal", "me@personal.dev")).
+			WithTrackedRepos([]string{sourcePath}).
+			WithSensitiveTerms(terms).
+			Build()
+		if err != nil {
+			t.Fatalf("Failed to build expected config: %v", err)
+		}
+
+		tempFile, tempCleanup, err := config_handler.PrepareTestConfig(config)
+		if err != nil {
+			t.Fatalf("Failed to prepare test config: %v", err)
+		}
+		defer (*tempCleanup)()
+		configs[name] = tempFile.Name()
+	}
+
+	tests := []struct {
+		name           string
+		args           []string
+		expectedErr    error
+		expectedOutput []string
+	}{
+		{"Export", []string{"--config", configs["clean"], "export", "--target", "", "--push=false"}, nil, []string{
+			"[default] Mirrored 2 commits as 2 target commits",
+		}},
+		{"Clean", []string{"--config", configs["clean"], "export", "audit"}, nil, []string{
+			"[default] Audited 2 commits and 0 files, nothing leaked.",
+		}},
+		{"Leaky", []string{"--config", configs["leaky"], "export", "audit"}, internal_errors.ErrLeaksFound, []string{
+			"[default] Audited 2 commits and 0 files, found 2 leaks:",
+			"term         message: contribution",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd.RootCmd.SetArgs(tt.args)
+
+			cmd_output := new(bytes.Buffer)
+			cmd.RootCmd.SetOut(cmd_output)
+			cmd.RootCmd.SetErr(cmd_output)
+
+			if err := cmd.RootCmd.Execute(); !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			for _, expected := range tt.expectedOutput {
+				if !bytes.Contains(cmd_output.Bytes(), []byte(expected)) {
+					t.Errorf("Expected output to contain %q, but got %q", expected, cmd_output.String())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/HideyoshiNakazone/tracko/external/cmd"
+	"github.com/HideyoshiNakazone/tracko/external/cmd/export_cmd"
+	"github.com/HideyoshiNakazone/tracko/lib/config_handler"
+	"github.com/HideyoshiNakazone/tracko/lib/config_model"
+	"github.com/HideyoshiNakazone/tracko/lib/data_export"
+	"github.com/HideyoshiNakazone/tracko/lib/repo"
+)
+
+func Test_ExecuteExport_Format(t *testing.T) {
+	base := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	sourcePath, sourceCleanup, err := repo.PrepareTestRepository([]repo.TestCommit{
+		{Author: "Test User", Email: "test@example.com", Message: "feat: first", When: base},
+		{Author: "Test User", Email: "test@example.com", Message: "fix: second", When: base.Add(time.Hour)},
+	})
+	if err != nil {
+		t.Fatalf("Failed to prepare source repository: %v", err)
+	}
+	defer (*sourceCleanup)()
+
+	// Prepare config
+	expectedConfig, err := config_model.NewConfigBuilder().
+		WithDBPath("/tmp/test.db").
+		WithTrackedAuthor("Test User", []string{"test@example.com"}).
+		WithTargetRepo(t.TempDir(), "Test User", "test@personal.example.com").
+		WithProject(filepath.Base(sourcePath), "Acme", "Platform", "").
+		WithTrackedRepos([]string{sourcePath}).
+		Build()
+
+	if err != nil {
+		t.Fatalf("Failed to build expected config: %v", err)
+	}
+
+	tempFile, tempCleanup, err := config_handler.PrepareTestConfig(expectedConfig)
+	if err != nil {
+		t.Fatalf("Failed to prepare test config: %v", err)
+	}
+	defer (*tempCleanup)()
+
+	// Flags keep their value between executions, later tests mirror commits
+	// without passing --format
+	t.Cleanup(func() {
+		flags := export_cmd.ExportCmd.Flags()
+		flags.Set("format", "")
+		flags.Set("out", "")
+		flags.Set("range", "")
+		flags.Set("palette", "")
+		flags.Lookup("repo").Value.(pflag.SliceValue).Replace(nil)
+	})
+
+	outPath := filepath.Join(t.TempDir(), "commits.csv")
+	sitePath := filepath.Join(t.TempDir(), "site")
+
+	tests := []struct {
+		name           string
+		args           []string
+		expectedOutput string
+		wantErr        bool
+	}{
+		{"CSV to stdout", []string{"--format", "csv", "--out", ""}, "hash,author_name", false},
+		{"NDJSON to stdout", []string{"--format", "ndjson", "--out", ""}, `"subject":"fix: second"`, false},
+		{"ICS to stdout", []string{"--format", "ics", "--out", ""}, "SUMMARY:" + filepath.Base(sourcePath) + ": 2 commits", false},
+		{"Toggl to stdout", []string{"--format", "toggl", "--out", ""}, "Platform,Acme,", false},
+		{"CSV to file", []string{"--format", "csv", "--out", outPath}, "Wrote 2 commits to " + outPath + ".", false},
+		{"SVG heatmap", []string{"--format", "svg", "--range", "2025", "--palette", "blue", "--out", ""}, `aria-label="2 commits from 2025-01-01 to 2025-12-31"`, false},
+		{"Invalid range", []string{"--format", "svg", "--range", "someday", "--out", ""}, "", true},
+		{"OpenMetrics to stdout", []string{"--format", "openmetrics", "--range", "", "--out", ""}, "tracko_commits_total{repo=\"" + filepath.Base(sourcePath) + "\"} 2\n", false},
+		{"HTML site", []string{"--format", "html", "--range", "", "--out", sitePath}, "Wrote 2 commits to " + filepath.Join(sitePath, data_export.SiteIndex) + ".", false},
+		{"HTML to stdout", []string{"--format", "html", "--out", ""}, "", true},
+		{"Parquet to stdout", []string{"--format", "parquet", "--out", ""}, "", true},
+		{"Unknown format", []string{"--format", "xml", "--out", ""}, "", true},
+		// Repo filters add up between executions, keep it last
+		{"SVG of other repos", []string{"--format", "svg", "--range", "2025", "--repo", "other", "--out", ""}, `aria-label="0 commits from 2025-01-01 to 2025-12-31"`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd.RootCmd.SetArgs(append(
+				[]string{
+					"--config", tempFile.Name(),
+					"export", "--target", "",
+				},
+				tt.args...,
+			))
+
+			cmd_output := new(bytes.Buffer)
+			cmd.RootCmd.SetOut(cmd_output)
+			cmd.RootCmd.SetErr(cmd_output)
+
+			err := cmd.RootCmd.Execute()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected an error, got output %q", cmd_output.String())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Command execution failed: %v", err)
+			}
+
+			if !bytes.Contains(cmd_output.Bytes(), []byte(tt.expectedOutput)) {
+				t.Errorf("Expected output to contain %q, but got %q", tt.expectedOutput, cmd_output.String())
+			}
+		})
+	}
+
+	file, err := os.Open(outPath)
+	if err != nil {
+		t.Fatalf("Failed to open %s: %v", outPath, err)
+	}
+	defer file.Close()
+
+	rows, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read CSV: %v", err)
+	}
+	if len(rows) != 3 || len(rows[0]) != len(data_export.Columns) {
+		t.Errorf("Expected a header and 2 rows of %d columns, got %q", len(data_export.Columns), rows)
+	}
+}
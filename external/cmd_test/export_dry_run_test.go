@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/HideyoshiNakazone/tracko/external/cmd"
+	"github.com/HideyoshiNakazone/tracko/external/cmd/export_cmd"
+	"github.com/HideyoshiNakazone/tracko/lib/config_handler"
+	"github.com/HideyoshiNakazone/tracko/lib/config_model"
+	"github.com/HideyoshiNakazone/tracko/lib/repo"
+)
+
+func Test_ExecuteExport_DryRun(t *testing.T) {
+	base := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	sourcePath, sourceCleanup, err := repo.PrepareTestRepository([]repo.TestCommit{
+		{Author: "Test User", Email: "test@example.com", Message: "feat: first", When: base},
+		{Author: "Test User", Email: "test@example.com", Message: "fix: second", When: base.Add(time.Hour)},
+	})
+	if err != nil {
+		t.Fatalf("Failed to prepare source repository: %v", err)
+	}
+	defer (*sourceCleanup)()
+
+	targetPath, targetCleanup, err := repo.PrepareTestRepository(nil)
+	if err != nil {
+		t.Fatalf("Failed to prepare target repository: %v", err)
+	}
+	defer (*targetCleanup)()
+
+	// Prepare config
+	expectedConfig, err := config_model.NewConfigBuilder().
+		WithDBPath("/tmp/test.db").
+		WithTrackedAuthor("Test User", []string{"test@example.com"}).
+		WithTarget(config_model.NewTargetBuilder(config_model.DefaultTargetName, targetPath).
+			WithAuthor("Test User", "test@example.com").
+			WithStrategy(config_model.StrategyDaily, 0)).
+		WithTrackedRepos([]string{sourcePath}).
+		Build()
+	if err != nil {
+		t.Fatalf("Failed to build expected config: %v", err)
+	}
+
+	tempFile, tempCleanup, err := config_handler.PrepareTestConfig(expectedConfig)
+	if err != nil {
+		t.Fatalf("Failed to prepare test config: %v", err)
+	}
+	defer (*tempCleanup)()
+
+	t.Cleanup(func() {
+		flags := export_cmd.ExportCmd.Flags()
+		flags.Set("dry-run", "false")
+		flags.Set("range", "")
+		flags.Lookup("repo").Value.(pflag.SliceValue).Replace(nil)
+	})
+
+	cmd.RootCmd.SetArgs(
+		[]string{
+			"--config", tempFile.Name(),
+			"export", "--target", "", "--dry-run", "--range", "2025-01-01..2025-01-31",
+		},
+	)
+
+	cmd_output := new(bytes.Buffer)
+	cmd.RootCmd.SetOut(cmd_output)
+	cmd.RootCmd.SetErr(cmd_output)
+
+	if err := cmd.RootCmd.Execute(); err != nil {
+		t.Fatalf("Command execution failed: %v", err)
+	}
+
+	output := cmd_output.String()
+	for _, expected := range []string{
+		`Warning: target "default" exports as test@example.com, which is a tracked email.`,
+		"[default] Would mirror 2 commits as 1 target commits (0 already exported).",
+		"2025-01-01 11:00 +0000",
+		"(2 source commits)",
+		"Before:",
+		"After:",
+		"Less ·░▒▓█ More",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, but got %q", expected, output)
+		}
+	}
+
+	// 2025-01-01 is a Wednesday, empty before and the busiest day after
+	before, after, _ := strings.Cut(output, "After:")
+	if !strings.Contains(before, "\nWed ·····\n") || !strings.Contains(after, "\nWed █····\n") {
+		t.Errorf("Expected the export to show up only after, got %q", output)
+	}
+
+	if _, err := os.Stat(filepath.Join(targetPath, ".git", "tracko")); !os.IsNotExist(err) {
+		t.Errorf("Expected no mapping table in the target, got %v", err)
+	}
+	if entries, _ := os.ReadDir(filepath.Join(targetPath, ".git", "refs", "heads")); len(entries) != 0 {
+		t.Errorf("Expected no commits in the target, got branches %v", entries)
+	}
+
+	// Targets pick their repos in the config, --repo only filters data exports
+	for _, args := range [][]string{{"--dry-run"}, {"--dry-run=false"}} {
+		cmd.RootCmd.SetArgs(append([]string{"--config", tempFile.Name(), "export", "--repo", "other"}, args...))
+		if err := cmd.RootCmd.Execute(); err == nil || !strings.Contains(err.Error(), "--repo can only be combined with --format") {
+			t.Errorf("Expected --repo to be rejected with %v, got %v", args, err)
+		}
+	}
+	if entries, _ := os.ReadDir(filepath.Join(targetPath, ".git", "refs", "heads")); len(entries) != 0 {
+		t.Errorf("Expected no commits in the target, got branches %v", entries)
+	}
+}
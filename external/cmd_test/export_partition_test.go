@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/HideyoshiNakazone/tracko/external/cmd"
+	"github.com/HideyoshiNakazone/tracko/external/cmd/export_cmd"
+	"github.com/HideyoshiNakazone/tracko/lib/config_handler"
+	"github.com/HideyoshiNakazone/tracko/lib/config_model"
+	"github.com/HideyoshiNakazone/tracko/lib/repo"
+)
+
+func Test_ExecuteExport_Partitions(t *testing.T) {
+	base := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	sourcePath, sourceCleanup, err := repo.PrepareTestRepository([]repo.TestCommit{
+		{Author: "Test User", Email: "test@example.com", Message: "feat: last year", When: base.AddDate(-1, 0, 0)},
+		{Author: "Test User", Email: "test@example.com", Message: "feat: this year", When: base},
+		{Author: "Test User", Email: "test@example.com", Message: "fix: this year", When: base.Add(time.Hour)},
+	})
+	if err != nil {
+		t.Fatalf("Failed to prepare source repository: %v", err)
+	}
+	defer (*sourceCleanup)()
+
+	targetPath, targetCleanup, err := repo.PrepareTestRepository(nil)
+	if err != nil {
+		t.Fatalf("Failed to prepare target repository: %v", err)
+	}
+	defer (*targetCleanup)()
+
+	// Prepare config
+	expectedConfig, err := config_model.NewConfigBuilder().
+		WithDBPath("/tmp/test.db").
+		WithTrackedAuthor("Test User", []string{"test@example.com"}).
+		WithTarget(config_model.NewTargetBuilder("github", targetPath).
+			WithAuthor("Test User", "test@personal.example.com").
+			WithLayout(config_model.LayoutSplitYear, config_model.LayoutIntoBranch)).
+		WithTrackedRepos([]string{sourcePath}).
+		Build()
+	if err != nil {
+		t.Fatalf("Failed to build expected config: %v", err)
+	}
+
+	tempFile, tempCleanup, err := config_handler.PrepareTestConfig(expectedConfig)
+	if err != nil {
+		t.Fatalf("Failed to prepare test config: %v", err)
+	}
+	defer (*tempCleanup)()
+
+	tests := []struct {
+		name             string
+		target           string
+		expectedOutput   []string
+		unexpectedOutput string
+	}{
+		{
+			name:             "Single partition",
+			target:           "github-2025",
+			expectedOutput:   []string{"[github-2025] Mirrored 2 commits as 2 target commits (0 already exported)."},
+			unexpectedOutput: "[github-2024]",
+		},
+		{
+			name:   "Every partition",
+			target: "github",
+			expectedOutput: []string{
+				"[github-2024] Mirrored 1 commits as 1 target commits (0 already exported).",
+				"[github-2025] Mirrored 0 commits as 0 target commits (2 already exported).",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd.RootCmd.SetArgs(
+				[]string{
+					"--config", tempFile.Name(),
+					"export", "--target", tt.target,
+				},
+			)
+			t.Cleanup(func() {
+				export_cmd.ExportCmd.PersistentFlags().Set("target", "")
+			})
+
+			cmd_output := new(bytes.Buffer)
+			cmd.RootCmd.SetOut(cmd_output)
+			cmd.RootCmd.SetErr(cmd_output)
+
+			if err := cmd.RootCmd.Execute(); err != nil {
+				t.Fatalf("Command execution failed: %v", err)
+			}
+
+			for _, expected := range tt.expectedOutput {
+				if !bytes.Contains(cmd_output.Bytes(), []byte(expected)) {
+					t.Errorf("Expected output to contain %q, but got %q", expected, cmd_output.String())
+				}
+			}
+			if tt.unexpectedOutput != "" && bytes.Contains(cmd_output.Bytes(), []byte(tt.unexpectedOutput)) {
+				t.Errorf("Expected output not to contain %q, but got %q", tt.unexpectedOutput, cmd_output.String())
+			}
+		})
+	}
+}
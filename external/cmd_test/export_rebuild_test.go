@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/HideyoshiNakazone/tracko/external/cmd"
+	"github.com/HideyoshiNakazone/tracko/lib/config_handler"
+	"github.com/HideyoshiNakazone/tracko/lib/config_model"
+	"github.com/HideyoshiNakazone/tracko/lib/repo"
+)
+
+func Test_ExecuteExportRebuild(t *testing.T) {
+	base := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	sourcePath, sourceCleanup, err := repo.PrepareTestRepository([]repo.TestCommit{
+		{Author: "Test User", Email: "test@example.com", Message: "feat: first", When: base},
+		{Author: "Test User", Email: "test@example.com", Message: "fix: second", When: base.Add(time.Hour)},
+	})
+	if err != nil {
+		t.Fatalf("Failed to prepare source repository: %v", err)
+	}
+	defer (*sourceCleanup)()
+
+	targetPath, targetCleanup, err := repo.PrepareTestRepository(nil)
+	if err != nil {
+		t.Fatalf("Failed to prepare target repository: %v", err)
+	}
+	defer (*targetCleanup)()
+
+	// Prepare config
+	expectedConfig, err := config_model.NewConfigBuilder().
+		WithDBPath("/tmp/test.db").
+		WithTrackedAuthor("Test User", []string{"test@example.com"}).
+		WithTargetRepo(targetPath, "Test User", "test@personal.example.com").
+		WithTrackedRepos([]string{sourcePath}).
+		Build()
+
+	if err != nil {
+		t.Fatalf("Failed to build expected config: %v", err)
+	}
+
+	tempFile, tempCleanup, err := config_handler.PrepareTestConfig(expectedConfig)
+	if err != nil {
+		t.Fatalf("Failed to prepare test config: %v", err)
+	}
+	defer (*tempCleanup)()
+
+	tests := []struct {
+		name           string
+		args           []string
+		expectedOutput []string
+	}{
+		{"Export", []string{"export", "--push=false"}, []string{"[default] Mirrored 2 commits"}},
+		{"Rebuild", []string{"export", "rebuild", "--target", ""}, []string{
+			"[default] Removed 2 mirrored commits, replayed 0",
+			"[default] Mirrored 2 commits as 2 target commits.",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd.RootCmd.SetArgs(append([]string{"--config", tempFile.Name()}, tt.args...))
+
+			cmd_output := new(bytes.Buffer)
+			cmd.RootCmd.SetOut(cmd_output)
+			cmd.RootCmd.SetErr(cmd_output)
+
+			if err := cmd.RootCmd.Execute(); err != nil {
+				t.Fatalf("Command execution failed: %v", err)
+			}
+
+			for _, expected := range tt.expectedOutput {
+				if !bytes.Contains(cmd_output.Bytes(), []byte(expected)) {
+					t.Errorf("Expected output to contain %q, but got %q", expected, cmd_output.String())
+				}
+			}
+		})
+	}
+}
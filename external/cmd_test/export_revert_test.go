@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/HideyoshiNakazone/tracko/external/cmd"
+	"github.com/HideyoshiNakazone/tracko/lib/config_handler"
+	"github.com/HideyoshiNakazone/tracko/lib/config_model"
+	"github.com/HideyoshiNakazone/tracko/lib/repo"
+)
+
+func Test_ExecuteExportRevert(t *testing.T) {
+	base := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	sourcePath, sourceCleanup, err := repo.PrepareTestRepository([]repo.TestCommit{
+		{Author: "Test User", Email: "test@example.com", Message: "feat: first", When: base},
+		{Author: "Test User", Email: "test@example.com", Message: "fix: second", When: base.AddDate(0, 0, 1)},
+	})
+	if err != nil {
+		t.Fatalf("Failed to prepare source repository: %v", err)
+	}
+	defer (*sourceCleanup)()
+
+	targetPath, targetCleanup, err := repo.PrepareTestRepository(nil)
+	if err != nil {
+		t.Fatalf("Failed to prepare target repository: %v", err)
+	}
+	defer (*targetCleanup)()
+
+	// Prepare config
+	expectedConfig, err := config_model.NewConfigBuilder().
+		WithDBPath("/tmp/test.db").
+		WithTrackedAuthor("Test User", []string{"test@example.com"}).
+		WithTargetRepo(targetPath, "Test User", "test@personal.example.com").
+		WithTrackedRepos([]string{sourcePath}).
+		Build()
+
+	if err != nil {
+		t.Fatalf("Failed to build expected config: %v", err)
+	}
+
+	tempFile, tempCleanup, err := config_handler.PrepareTestConfig(expectedConfig)
+	if err != nil {
+		t.Fatalf("Failed to prepare test config: %v", err)
+	}
+	defer (*tempCleanup)()
+
+	tests := []struct {
+		name           string
+		args           []string
+		expectedOutput string
+		wantErr        bool
+	}{
+		{"Export", []string{"export", "--push=false"}, "[default] Mirrored 2 commits", false},
+		{"Missing since", []string{"export", "revert", "--since", ""}, "", true},
+		{"Invalid since", []string{"export", "revert", "--since", "01/02/2025"}, "", true},
+		{"Revert", []string{"export", "revert", "--since", "2025-01-02"}, "[default] Removed 1 mirrored commits, replayed 0 (backup at refs/tracko/backup/default/", false},
+		{"Nothing to revert", []string{"export", "revert", "--since", "2025-01-02"}, "[default] Nothing to revert.", false},
+		{"Export again", []string{"export", "--push=false"}, "[default] Mirrored 1 commits", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd.RootCmd.SetArgs(append([]string{"--config", tempFile.Name()}, tt.args...))
+
+			cmd_output := new(bytes.Buffer)
+			cmd.RootCmd.SetOut(cmd_output)
+			cmd.RootCmd.SetErr(cmd_output)
+
+			err := cmd.RootCmd.Execute()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Execute() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !bytes.Contains(cmd_output.Bytes(), []byte(tt.expectedOutput)) {
+				t.Errorf("Expected output to contain %q, but got %q", tt.expectedOutput, cmd_output.String())
+			}
+		})
+	}
+}
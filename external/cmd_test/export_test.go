@@ -0,0 +1,328 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v6"
+	"github.com/go-git/go-git/v6/config"
+
+	"github.com/HideyoshiNakazone/tracko/external/cmd"
+	"github.com/HideyoshiNakazone/tracko/external/cmd/export_cmd"
+	"github.com/HideyoshiNakazone/tracko/lib/config_handler"
+	"github.com/HideyoshiNakazone/tracko/lib/config_model"
+	"github.com/HideyoshiNakazone/tracko/lib/internal_errors"
+	"github.com/HideyoshiNakazone/tracko/lib/repo"
+)
+
+func Test_ExecuteExport(t *testing.T) {
+	base := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	sourcePath, sourceCleanup, err := repo.PrepareTestRepository([]repo.TestCommit{
+		{Author: "Test User", Email: "test@example.com", Message: "feat: first", When: base},
+		{Author: "Test User", Email: "test@example.com", Message: "fix: second", When: base.Add(time.Hour)},
+	})
+	if err != nil {
+		t.Fatalf("Failed to prepare source repository: %v", err)
+	}
+	defer (*sourceCleanup)()
+
+	targetPath, targetCleanup, err := repo.PrepareTestRepository(nil)
+	if err != nil {
+		t.Fatalf("Failed to prepare target repository: %v", err)
+	}
+	defer (*targetCleanup)()
+
+	// Prepare config
+	expectedConfig, err := config_model.NewConfigBuilder().
+		WithDBPath("/tmp/test.db").
+		WithTrackedAuthor("Test User", []string{"test@example.com"}).
+		WithTargetRepo(targetPath, "Test User", "test@personal.example.com").
+		WithTrackedRepos([]string{sourcePath}).
+		Build()
+
+	if err != nil {
+		t.Fatalf("Failed to build expected config: %v", err)
+	}
+
+	tempFile, tempCleanup, err := config_handler.PrepareTestConfig(expectedConfig)
+	if err != nil {
+		t.Fatalf("Failed to prepare test config: %v", err)
+	}
+	defer (*tempCleanup)()
+
+	tests := []struct {
+		name           string
+		expectedOutput string
+	}{
+		{"First export", "[default] Mirrored 2 commits as 2 target commits (0 already exported)."},
+		{"Second export", "[default] Mirrored 0 commits as 0 target commits (2 already exported)."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd.RootCmd.SetArgs(
+				[]string{
+					"--config", tempFile.Name(),
+					"export",
+				},
+			)
+
+			cmd_output := new(bytes.Buffer)
+			cmd.RootCmd.SetOut(cmd_output)
+			cmd.RootCmd.SetErr(cmd_output)
+
+			if err := cmd.RootCmd.Execute(); err != nil {
+				t.Fatalf("Command execution failed: %v", err)
+			}
+
+			if !bytes.Contains(cmd_output.Bytes(), []byte(tt.expectedOutput)) {
+				t.Errorf("Expected output to contain %q, but got %q", tt.expectedOutput, cmd_output.String())
+			}
+		})
+	}
+}
+
+func Test_ExecuteExport_Push(t *testing.T) {
+	base := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	sourcePath, sourceCleanup, err := repo.PrepareTestRepository([]repo.TestCommit{
+		{Author: "Test User", Email: "test@example.com", Message: "feat: first", When: base},
+	})
+	if err != nil {
+		t.Fatalf("Failed to prepare source repository: %v", err)
+	}
+	defer (*sourceCleanup)()
+
+	targetPath, targetCleanup, err := repo.PrepareTestRepository(nil)
+	if err != nil {
+		t.Fatalf("Failed to prepare target repository: %v", err)
+	}
+	defer (*targetCleanup)()
+
+	barePath, err := os.MkdirTemp("", "tracko_test_remote_*")
+	if err != nil {
+		t.Fatalf("Failed to create remote directory: %v", err)
+	}
+	defer os.RemoveAll(barePath)
+
+	if _, err := git.PlainInit(barePath, true); err != nil {
+		t.Fatalf("Failed to init remote repository: %v", err)
+	}
+
+	target, err := git.PlainOpen(targetPath)
+	if err != nil {
+		t.Fatalf("Failed to open target repository: %v", err)
+	}
+	if _, err := target.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{barePath}}); err != nil {
+		t.Fatalf("Failed to create remote: %v", err)
+	}
+
+	// Prepare config
+	expectedConfig, err := config_model.NewConfigBuilder().
+		WithDBPath("/tmp/test.db").
+		WithTrackedAuthor("Test User", []string{"test@example.com"}).
+		WithTargetRepo(targetPath, "Test User", "test@personal.example.com").
+		WithTrackedRepos([]string{sourcePath}).
+		Build()
+
+	if err != nil {
+		t.Fatalf("Failed to build expected config: %v", err)
+	}
+
+	tempFile, tempCleanup, err := config_handler.PrepareTestConfig(expectedConfig)
+	if err != nil {
+		t.Fatalf("Failed to prepare test config: %v", err)
+	}
+	defer (*tempCleanup)()
+
+	cmd.RootCmd.SetArgs(
+		[]string{
+			"--config", tempFile.Name(),
+			"export", "--push",
+		},
+	)
+
+	cmd_output := new(bytes.Buffer)
+	cmd.RootCmd.SetOut(cmd_output)
+	cmd.RootCmd.SetErr(cmd_output)
+
+	if err := cmd.RootCmd.Execute(); err != nil {
+		t.Fatalf("Command execution failed: %v", err)
+	}
+
+	if !bytes.Contains(cmd_output.Bytes(), []byte("Pushed ")) {
+		t.Errorf("Expected output to report the push, but got %q", cmd_output.String())
+	}
+
+	head, _ := target.Head()
+	bare, _ := git.PlainOpen(barePath)
+	ref, err := bare.Reference(head.Name(), true)
+	if err != nil || ref.Hash() != head.Hash() {
+		t.Errorf("Expected remote %s at %s, got %v (%v)", head.Name(), head.Hash(), ref, err)
+	}
+}
+
+func Test_ExecuteExport_PushLeak(t *testing.T) {
+	base := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	sourcePath, sourceCleanup, err := repo.PrepareTestRepository([]repo.TestCommit{
+		{Author: "Test User", Email: "test@example.com", Message: "feat: first", When: base},
+	})
+	if err != nil {
+		t.Fatalf("Failed to prepare source repository: %v", err)
+	}
+	defer (*sourceCleanup)()
+
+	targetPath, targetCleanup, err := repo.PrepareTestRepository(nil)
+	if err != nil {
+		t.Fatalf("Failed to prepare target repository: %v", err)
+	}
+	defer (*targetCleanup)()
+
+	barePath, err := os.MkdirTemp("", "tracko_test_remote_*")
+	if err != nil {
+		t.Fatalf("Failed to create remote directory: %v", err)
+	}
+	defer os.RemoveAll(barePath)
+
+	if _, err := git.PlainInit(barePath, true); err != nil {
+		t.Fatalf("Failed to init remote repository: %v", err)
+	}
+
+	target, err := git.PlainOpen(targetPath)
+	if err != nil {
+		t.Fatalf("Failed to open target repository: %v", err)
+	}
+	if _, err := target.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{barePath}}); err != nil {
+		t.Fatalf("Failed to create remote: %v", err)
+	}
+
+	// A word of the default message template is sensitive, so every target
+	// commit leaks it
+	expectedConfig, err := config_model.NewConfigBuilder().
+		WithDBPath("/tmp/test.db").
+		WithTrackedAuthor("Test User", []string{"test@example.com"}).
+		WithTargetRepo(targetPath, "Test User", "test@personal.example.com").
+		WithTrackedRepos([]string{sourcePath}).
+		WithSensitiveTerms([]string{"Contribution"}).
+		Build()
+
+	if err != nil {
+		t.Fatalf("Failed to build expected config: %v", err)
+	}
+
+	tempFile, tempCleanup, err := config_handler.PrepareTestConfig(expectedConfig)
+	if err != nil {
+		t.Fatalf("Failed to prepare test config: %v", err)
+	}
+	defer (*tempCleanup)()
+
+	t.Cleanup(func() {
+		export_cmd.ExportCmd.Flags().Set("push", "false")
+	})
+
+	cmd.RootCmd.SetArgs(
+		[]string{
+			"--config", tempFile.Name(),
+			"export", "--push",
+		},
+	)
+
+	cmd_output := new(bytes.Buffer)
+	cmd.RootCmd.SetOut(cmd_output)
+	cmd.RootCmd.SetErr(cmd_output)
+
+	if err := cmd.RootCmd.Execute(); !errors.Is(err, internal_errors.ErrLeaksFound) {
+		t.Fatalf("Expected the leak to block the push, got %v", err)
+	}
+
+	if !bytes.Contains(cmd_output.Bytes(), []byte("found 1 leaks:")) || bytes.Contains(cmd_output.Bytes(), []byte("Pushed ")) {
+		t.Errorf("Expected output to report the leak and no push, but got %q", cmd_output.String())
+	}
+
+	head, _ := target.Head()
+	bare, _ := git.PlainOpen(barePath)
+	if ref, err := bare.Reference(head.Name(), true); err == nil {
+		t.Errorf("Expected nothing pushed to the remote, got %s at %s", ref.Name(), ref.Hash())
+	}
+}
+
+func Test_ExecuteExport_Target(t *testing.T) {
+	base := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	sourcePath, sourceCleanup, err := repo.PrepareTestRepository([]repo.TestCommit{
+		{Author: "Test User", Email: "test@example.com", Message: "feat: first", When: base},
+	})
+	if err != nil {
+		t.Fatalf("Failed to prepare source repository: %v", err)
+	}
+	defer (*sourceCleanup)()
+
+	githubPath, githubCleanup, err := repo.PrepareTestRepository(nil)
+	if err != nil {
+		t.Fatalf("Failed to prepare target repository: %v", err)
+	}
+	defer (*githubCleanup)()
+
+	gitlabPath, gitlabCleanup, err := repo.PrepareTestRepository(nil)
+	if err != nil {
+		t.Fatalf("Failed to prepare target repository: %v", err)
+	}
+	defer (*gitlabCleanup)()
+
+	// Prepare config
+	expectedConfig, err := config_model.NewConfigBuilder().
+		WithDBPath("/tmp/test.db").
+		WithTrackedAuthor("Test User", []string{"test@example.com"}).
+		WithTarget(config_model.NewTargetBuilder("github", githubPath).WithAuthor("Test User", "test@personal.example.com")).
+		WithTarget(config_model.NewTargetBuilder("gitlab", gitlabPath).WithAuthor("Test User", "test@personal.example.com")).
+		WithTrackedRepos([]string{sourcePath}).
+		Build()
+
+	if err != nil {
+		t.Fatalf("Failed to build expected config: %v", err)
+	}
+
+	tempFile, tempCleanup, err := config_handler.PrepareTestConfig(expectedConfig)
+	if err != nil {
+		t.Fatalf("Failed to prepare test config: %v", err)
+	}
+	defer (*tempCleanup)()
+
+	tests := []struct {
+		name           string
+		target         string
+		expectedOutput []string
+		wantErr        bool
+	}{
+		{"Single target", "gitlab", []string{"[gitlab] Mirrored 1 commits"}, false},
+		{"All targets", "", []string{"[github] Mirrored 1 commits", "[gitlab] Mirrored 0 commits"}, false},
+		{"Unknown target", "bitbucket", []string{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd.RootCmd.SetArgs(
+				[]string{
+					"--config", tempFile.Name(),
+					"export", "--push=false", "--target", tt.target,
+				},
+			)
+
+			cmd_output := new(bytes.Buffer)
+			cmd.RootCmd.SetOut(cmd_output)
+			cmd.RootCmd.SetErr(cmd_output)
+
+			err := cmd.RootCmd.Execute()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Execute() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			for _, expected := range tt.expectedOutput {
+				if !bytes.Contains(cmd_output.Bytes(), []byte(expected)) {
+					t.Errorf("Expected output to contain %q, but got %q", expected, cmd_output.String())
+				}
+			}
+		})
+	}
+}
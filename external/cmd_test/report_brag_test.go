@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/HideyoshiNakazone/tracko/external/cmd"
+	"github.com/HideyoshiNakazone/tracko/lib/config_handler"
+	"github.com/HideyoshiNakazone/tracko/lib/config_model"
+	"github.com/HideyoshiNakazone/tracko/lib/repo"
+)
+
+func Test_ExecuteReportBrag(t *testing.T) {
+	base := time.Date(2026, 2, 1, 10, 0, 0, 0, time.UTC)
+	sourcePath, sourceCleanup, err := repo.PrepareTestRepository([]repo.TestCommit{
+		{Author: "Test User", Email: "test@example.com", Message: "feat: first", When: base},
+		{Author: "Test User", Email: "test@example.com", Message: "fix: second", When: base.AddDate(0, 6, 0)},
+	})
+	if err != nil {
+		t.Fatalf("Failed to prepare source repository: %v", err)
+	}
+	defer (*sourceCleanup)()
+
+	// Prepare config
+	expectedConfig, err := config_model.NewConfigBuilder().
+		WithDBPath("/tmp/test.db").
+		WithTrackedAuthor("Test User", []string{"test@example.com"}).
+		WithTargetRepo(t.TempDir(), "Test User", "test@personal.example.com").
+		WithTrackedRepos([]string{sourcePath}).
+		Build()
+
+	if err != nil {
+		t.Fatalf("Failed to build expected config: %v", err)
+	}
+
+	tempFile, tempCleanup, err := config_handler.PrepareTestConfig(expectedConfig)
+	if err != nil {
+		t.Fatalf("Failed to prepare test config: %v", err)
+	}
+	defer (*tempCleanup)()
+
+	templatePath := filepath.Join(t.TempDir(), "brag.tmpl")
+	if err := os.WriteFile(templatePath, []byte(`{{.Period.Label}}: {{plural .Stats.Commits "commit"}}`), 0o644); err != nil {
+		t.Fatalf("Failed to write template: %v", err)
+	}
+	outPath := filepath.Join(t.TempDir(), "brag.md")
+
+	tests := []struct {
+		name           string
+		args           []string
+		expectedOutput string
+		wantErr        bool
+	}{
+		{"Default template", []string{"--period", "2026-H1", "--template", "", "--out", ""}, "- feat: first (2026-02-01, `", false},
+		{"Custom template", []string{"--period", "2026", "--template", templatePath, "--out", ""}, "2026: 2 commits", false},
+		{"To file", []string{"--period", "2026-H2", "--template", "", "--out", outPath}, "Wrote the 2026-H2 brag document to " + outPath + ".", false},
+		{"Missing period", []string{"--period", "", "--out", ""}, "", true},
+		{"Invalid period", []string{"--period", "2026-H3", "--out", ""}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd.RootCmd.SetArgs(append(
+				[]string{
+					"--config", tempFile.Name(),
+					"report", "brag",
+				},
+				tt.args...,
+			))
+
+			cmd_output := new(bytes.Buffer)
+			cmd.RootCmd.SetOut(cmd_output)
+			cmd.RootCmd.SetErr(cmd_output)
+
+			err := cmd.RootCmd.Execute()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected an error, got output %q", cmd_output.String())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Command execution failed: %v", err)
+			}
+
+			if !bytes.Contains(cmd_output.Bytes(), []byte(tt.expectedOutput)) {
+				t.Errorf("Expected output to contain %q, but got %q", tt.expectedOutput, cmd_output.String())
+			}
+		})
+	}
+
+	document, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", outPath, err)
+	}
+	if !bytes.Contains(document, []byte("- fix: second: +")) {
+		t.Errorf("Expected the H2 document to quote the second commit, got %s", document)
+	}
+}
@@ -1,13 +1,30 @@
 package flags
 
-import "os"
+import (
+	"os"
+
+	"github.com/HideyoshiNakazone/tracko/lib/config_handler"
+)
 
 var ConfigPath string
 
+// GetConfigPath returns the config file set by the --config flag, or else by
+// TRACKO_CONFIG_PATH, empty when neither is set.
 func GetConfigPath() string {
-	configPath := os.Getenv("TRACKO_CONFIG_PATH")
-	if configPath != "" {
-		return configPath
+	if ConfigPath != "" {
+		return ConfigPath
+	}
+	return os.Getenv(config_handler.EnvVar("config_path"))
+}
+
+// GetConfigPathSource returns where the config file in use was chosen.
+func GetConfigPathSource() string {
+	switch {
+	case ConfigPath != "":
+		return config_handler.SourceFlag
+	case os.Getenv(config_handler.EnvVar("config_path")) != "":
+		return config_handler.SourceEnv
+	default:
+		return config_handler.SourceDefault
 	}
-	return ConfigPath
 }
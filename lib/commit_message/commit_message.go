@@ -0,0 +1,62 @@
+package commit_message
+
+import (
+	"bytes"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// DefaultTemplate keeps mirrored commits fully anonymous.
+const DefaultTemplate = "Mirrored contribution"
+
+// TemplateData is the data available to export message templates.
+type TemplateData struct {
+	RepoAlias    string
+	Date         time.Time
+	Additions    int
+	Deletions    int
+	FilesChanged int
+	Type         string
+	SourceID     string
+	// Commits is the number of source commits folded into the target commit
+	Commits int
+}
+
+var sampleData = TemplateData{
+	RepoAlias:    "repo",
+	Date:         time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+	Additions:    1,
+	Deletions:    1,
+	FilesChanged: 1,
+	Type:         "feat",
+	SourceID:     "0000000000000000000000000000000000000000000000000000000000000000",
+	Commits:      1,
+}
+
+// Parse compiles a message template and renders it once against sample data,
+// so that references to unknown fields fail here instead of during an export.
+func Parse(text string) (*template.Template, error) {
+	if text == "" {
+		text = DefaultTemplate
+	}
+
+	tmpl, err := template.New("message").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := Render(tmpl, sampleData); err != nil {
+		return nil, err
+	}
+
+	return tmpl, nil
+}
+
+func Render(tmpl *template.Template, data TemplateData) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
@@ -0,0 +1,70 @@
+package commit_message
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_Parse(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		wantErr bool
+	}{
+		{"Empty uses default", "", false},
+		{"Static text", "Work on a private project", false},
+		{"Known fields", "{{.Type}} in {{.RepoAlias}} (+{{.Additions}} -{{.Deletions}}, {{.FilesChanged}} files) on {{.Date.Format \"2006-01-02\"}} {{.SourceID}}", false},
+		{"Syntax error", "{{.Type", true},
+		{"Unknown field", "{{.Hash}}", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.text)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_Render(t *testing.T) {
+	data := TemplateData{
+		RepoAlias:    "backend",
+		Date:         time.Date(2025, 3, 14, 9, 30, 0, 0, time.UTC),
+		Additions:    10,
+		Deletions:    2,
+		FilesChanged: 3,
+		Type:         "feat",
+		SourceID:     "abc123",
+	}
+
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{"Default template", "", DefaultTemplate},
+		{"Repo and type", "{{.Type}}: work on {{.RepoAlias}}", "feat: work on backend"},
+		{"Stats and date", "{{.Date.Format \"2006-01-02\"}} +{{.Additions}} -{{.Deletions}} ({{.FilesChanged}} files)", "2025-03-14 +10 -2 (3 files)"},
+		{"Conditional type", "{{if .Type}}{{.Type}}{{else}}change{{end}} {{.SourceID}}", "feat abc123"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := Parse(tt.text)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			got, err := Render(tmpl, data)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+
+			if got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
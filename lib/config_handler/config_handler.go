@@ -1,10 +1,10 @@
 package config_handler
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 
-	"github.com/go-viper/mapstructure/v2"
 	"github.com/spf13/viper"
 
 	"github.com/HideyoshiNakazone/tracko/lib/config_model"
@@ -20,6 +20,19 @@ var trackedPaths = []string{
 const configFormat string = "yaml"
 
 func PrepareConfig(filePath string) error {
+	LocateConfig(filePath)
+
+	// Outdated config files are rewritten to the current schema on load
+	if _, err := MigrateConfig(); err != nil && !errors.Is(err, internal_errors.ErrConfigNotInitialized) {
+		return err
+	}
+	_, err := GetConfig()
+
+	return err
+}
+
+// LocateConfig points viper at the config file, without reading it.
+func LocateConfig(filePath string) {
 	if filePath == "" {
 		for _, path := range trackedPaths {
 			viper.AddConfigPath(path)
@@ -29,38 +42,78 @@ func PrepareConfig(filePath string) error {
 	}
 
 	viper.SetConfigType(configFormat)
-	// other configurations be placed here, like migrations
-	_, err := GetConfig()
-
-	return err
 }
 
+// GetConfig reads the config file in use, with the fields overridden by
+// TRACKO_* env vars.
 func GetConfig() (*config_model.ConfigModel, error) {
-	err := viper.ReadInConfig()
+	return readConfig(true)
+}
+
+// GetFileConfig reads the config file in use alone, for commands writing
+// the config back so that env overrides never end up in the file.
+func GetFileConfig() (*config_model.ConfigModel, error) {
+	return readConfig(false)
+}
+
+func readConfig(withEnv bool) (*config_model.ConfigModel, error) {
+	settings, err := readSettings()
 	if err != nil {
-		return nil, internal_errors.ErrConfigNotInitialized
+		return nil, err
+	}
+	if withEnv {
+		if _, err := applyEnvOverrides(settings); err != nil {
+			return nil, err
+		}
 	}
 
-	var cfg config_model.ConfigDTO
-	if err := viper.Unmarshal(&cfg); err != nil {
+	cfg, err := decodeSettings(settings)
+	if err != nil {
 		return nil, err
 	}
 
-	if cfg.Version == "" {
-		return nil, internal_errors.ErrConfigNotInitialized
+	if err := validateValues(cfg); err != nil {
+		return nil, err
 	}
 
 	return cfg.ToModel()
 }
 
+// readSettings reads the config file in use, files not migrated yet are
+// read as if they were.
+func readSettings() (map[string]any, error) {
+	if err := viper.ReadInConfig(); err != nil {
+		return nil, internal_errors.ErrConfigNotInitialized
+	}
+
+	settings := viper.AllSettings()
+	if version, _ := settings["version"].(string); version == "" {
+		return nil, internal_errors.ErrConfigNotInitialized
+	}
+	if _, _, err := Migrate(settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// GetConfigAttr returns the effective value at key, env overrides included.
 func GetConfigAttr[T any](key string) (T, error) {
 	var zero T
 
-	if err := viper.ReadInConfig(); err != nil {
+	settings, err := readSettings()
+	if err != nil {
 		return zero, fmt.Errorf("failed to read config: %w", err)
 	}
+	if _, err := applyEnvOverrides(settings); err != nil {
+		return zero, err
+	}
+
+	effective := viper.New()
+	if err := effective.MergeConfigMap(settings); err != nil {
+		return zero, err
+	}
 
-	val := viper.Get(key)
+	val := effective.Get(key)
 	if val == nil {
 		return zero, fmt.Errorf("config value for %q is nil", key)
 	}
@@ -86,8 +139,8 @@ func SetConfig(cfg *config_model.ConfigModel) error {
 		return err
 	}
 
-	m := map[string]any{}
-	if err := mapstructure.Decode(cfg_dto, &m); err != nil {
+	m, err := utils.DecodeToMap(cfg_dto)
+	if err != nil {
 		return err
 	}
 
@@ -123,5 +176,15 @@ func SetConfigAttr(key string, value any) error {
 		return fmt.Errorf("failed to unmarshal config: %w", err)
 	}
 
+	if err := validateValues(&cfg); err != nil {
+		viper.ReadInConfig()
+		return err
+	}
+
+	if _, err := cfg.ToModel(); err != nil {
+		viper.ReadInConfig()
+		return err
+	}
+
 	return viper.WriteConfig()
 }
@@ -1,10 +1,13 @@
 package config_handler
 
 import (
+	"errors"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/HideyoshiNakazone/tracko/lib/config_model"
+	"github.com/HideyoshiNakazone/tracko/lib/internal_errors"
 	"github.com/spf13/viper"
 )
 
@@ -20,7 +23,7 @@ func TestSetAndGetConfig(t *testing.T) {
 	cfg, err := config_model.NewConfigBuilder().
 		WithDBPath("/tmp/test.db").
 		WithTrackedAuthor("Test User", []string{"test@example.com"}).
-		WithTargetRepo("repo1").
+		WithTargetRepo("repo1", "Test User", "test@personal.example.com").
 		WithTrackedRepos([]string{"repo1", "repo2"}).
 		Build()
 
@@ -73,13 +76,14 @@ func Test_SetConfigAttr(t *testing.T) {
 		{"Restricted case", args{"version", "value1"}, true},
 		{"Valid case", args{"db_path", "value1"}, false},
 		{"Invalid case", args{"invalid_field", "value2"}, true},
+		{"Deprecated target repo", args{"target_repo", "value3"}, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			cfg, err := config_model.NewConfigBuilder().
 				WithDBPath("/tmp/test.db").
 				WithTrackedAuthor("Test User", []string{"test@example.com"}).
-				WithTargetRepo("repo1").
+				WithTargetRepo("repo1", "Test User", "test@personal.example.com").
 				WithTrackedRepos([]string{"repo1", "repo2"}).
 				Build()
 
@@ -100,3 +104,20 @@ func Test_SetConfigAttr(t *testing.T) {
 		})
 	}
 }
+
+func Test_GetConfig_DeprecatedTargetRepo(t *testing.T) {
+	content := "version: v1\ndb_path: /tmp/test.db\nauthor:\n  name: Test User\n  emails: [test@example.com]\ntarget_repo: /tmp/mirror\ntracked_repos: []\n"
+	path := prepareConfigFile(t, content)
+
+	// target_repo is migrated into the default target, which has no author
+	// to export as until one is added
+	err := PrepareConfig(path)
+	if !errors.Is(err, internal_errors.ErrInvalidConfig) || !strings.Contains(err.Error(), "targets[0].author.name: is required") {
+		t.Errorf("Expected the default target to require an author, got %v", err)
+	}
+
+	migrated, _ := os.ReadFile(path)
+	if strings.Contains(string(migrated), "target_repo") || !strings.Contains(string(migrated), "path: /tmp/mirror") {
+		t.Errorf("Expected target_repo moved to targets, got %s", migrated)
+	}
+}
@@ -0,0 +1,199 @@
+package config_handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/HideyoshiNakazone/tracko/lib/config_model"
+	"github.com/HideyoshiNakazone/tracko/lib/internal_errors"
+	"github.com/HideyoshiNakazone/tracko/lib/utils"
+)
+
+// EnvPrefix starts the name of every env var overriding a config field.
+const EnvPrefix = "TRACKO_"
+
+// Value sources, where the effective value of a config field comes from.
+// Flags override env vars, which override the config file, which overrides
+// the defaults.
+const (
+	SourceFlag    = "flag"
+	SourceEnv     = "env"
+	SourceFile    = "file"
+	SourceDefault = "default"
+)
+
+// EnvVar returns the env var overriding the config field at key, like
+// TRACKO_AUTHOR_EMAILS for author.emails or TRACKO_TARGETS_0_PATH for
+// targets[0].path.
+func EnvVar(key string) string {
+	replacer := strings.NewReplacer(".", "_", "[", "_", "]", "")
+	return EnvPrefix + strings.ToUpper(replacer.Replace(key))
+}
+
+// applyEnvOverrides overrides settings with the TRACKO_* env vars
+// of every field of config_model.ConfigDTO and returns the keys overridden.
+// Lists of values are comma separated and lists of objects are JSON arrays,
+// the fields of their elements are overridden by index, like
+// TRACKO_TARGETS_0_PATH, for the elements the list already has. The objects
+// and lists held by settings are copied before being overridden.
+func applyEnvOverrides(settings map[string]any) ([]string, error) {
+	return overrideStruct(reflect.TypeOf(config_model.ConfigDTO{}), settings, "")
+}
+
+func overrideStruct(t reflect.Type, settings map[string]any, prefix string) ([]string, error) {
+	overridden := []string{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("mapstructure"), ",")
+		if !field.IsExported() || name == "" || name == "-" || field.Tag.Get("restricted") == "true" {
+			continue
+		}
+		key := name
+		if prefix != "" {
+			key = prefix + "." + name
+		}
+
+		keys, err := overrideField(field.Type, settings, name, key)
+		if err != nil {
+			return nil, err
+		}
+		overridden = append(overridden, keys...)
+	}
+	return overridden, nil
+}
+
+func overrideField(t reflect.Type, settings map[string]any, name string, key string) ([]string, error) {
+	value, set := os.LookupEnv(EnvVar(key))
+
+	switch {
+	case t.Kind() == reflect.Struct:
+		// Copied, settings share the objects and lists of the file read by viper
+		nested, _ := settings[name].(map[string]any)
+		nested = maps.Clone(nested)
+		if nested == nil {
+			nested = map[string]any{}
+		}
+		keys, err := overrideStruct(t, nested, key)
+		if len(keys) > 0 {
+			settings[name] = nested
+		}
+		return keys, err
+
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Struct:
+		keys := []string{}
+		if set {
+			var items []any
+			if err := json.Unmarshal([]byte(value), &items); err != nil {
+				return nil, fmt.Errorf("%w: %s must be a JSON array: %v", internal_errors.ErrInvalidConfig, EnvVar(key), err)
+			}
+			settings[name] = items
+			keys = append(keys, key)
+		}
+
+		items, _ := settings[name].([]any)
+		items = slices.Clone(items)
+		for i, item := range items {
+			element, ok := item.(map[string]any)
+			if !ok {
+				continue
+			}
+			element = maps.Clone(element)
+			elementKeys, err := overrideStruct(t.Elem(), element, fmt.Sprintf("%s[%d]", key, i))
+			if err != nil {
+				return nil, err
+			}
+			items[i] = element
+			keys = append(keys, elementKeys...)
+		}
+		if len(keys) > 0 {
+			settings[name] = items
+		}
+		return keys, nil
+
+	case t.Kind() == reflect.Slice:
+		if !set {
+			return nil, nil
+		}
+		items := []any{}
+		for _, item := range utils.SplitList(value) {
+			items = append(items, item)
+		}
+		settings[name] = items
+		return []string{key}, nil
+
+	default:
+		if !set {
+			return nil, nil
+		}
+		settings[name] = value
+		return []string{key}, nil
+	}
+}
+
+// Sources tells where the effective value of every config field comes from.
+type Sources struct {
+	file       map[string]any
+	overridden []string
+}
+
+// ReadSources reads the config file in use and the env vars overriding it.
+func ReadSources() (*Sources, error) {
+	file, err := readSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	overridden, err := applyEnvOverrides(maps.Clone(file))
+	if err != nil {
+		return nil, err
+	}
+	return &Sources{file: file, overridden: overridden}, nil
+}
+
+// Of returns the source of the value at key, like author.emails or
+// targets[0].path: env when it or a list or object holding it is overridden,
+// file when the config file sets it and default otherwise.
+func (s *Sources) Of(key string) string {
+	for _, overridden := range s.overridden {
+		if key == overridden || strings.HasPrefix(key, overridden+".") || strings.HasPrefix(key, overridden+"[") {
+			return SourceEnv
+		}
+	}
+	if _, ok := lookupSetting(s.file, key); ok {
+		return SourceFile
+	}
+	return SourceDefault
+}
+
+// lookupSetting returns the value at key in settings, keys index lists like
+// targets[0].path.
+func lookupSetting(settings map[string]any, key string) (any, bool) {
+	var current any = settings
+	for _, part := range strings.Split(key, ".") {
+		name, index, indexed := strings.Cut(strings.TrimSuffix(part, "]"), "[")
+
+		m, ok := current.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if current, ok = m[name]; !ok {
+			return nil, false
+		}
+		if !indexed {
+			continue
+		}
+
+		items, ok := current.([]any)
+		var i int
+		if _, err := fmt.Sscan(index, &i); !ok || err != nil || i < 0 || i >= len(items) {
+			return nil, false
+		}
+		current = items[i]
+	}
+	return current, true
+}
@@ -0,0 +1,225 @@
+package config_handler
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/HideyoshiNakazone/tracko/lib/config_model"
+	"github.com/HideyoshiNakazone/tracko/lib/internal_errors"
+)
+
+const envConfig = `version: v2
+db_path: /tmp/test.db
+author:
+  name: Test User
+  emails: [test@example.com]
+targets:
+  - name: default
+    path: /tmp/mirror
+    author:
+      name: Test User
+      email: test@personal.example.com
+tracked_repos:
+  - path: /tmp/repo1
+`
+
+func Test_EnvVar(t *testing.T) {
+	tests := map[string]string{
+		"db_path":           "TRACKO_DB_PATH",
+		"author.emails":     "TRACKO_AUTHOR_EMAILS",
+		"targets[0].path":   "TRACKO_TARGETS_0_PATH",
+		"targets[1].push.x": "TRACKO_TARGETS_1_PUSH_X",
+	}
+
+	for key, want := range tests {
+		if got := EnvVar(key); got != want {
+			t.Errorf("EnvVar(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func Test_GetConfig_EnvOverrides(t *testing.T) {
+	tests := []struct {
+		name  string
+		env   map[string]string
+		check func(cfg *config_model.ConfigModel) bool
+	}{
+		{
+			name:  "no env vars",
+			env:   map[string]string{},
+			check: func(cfg *config_model.ConfigModel) bool { return cfg.DBPath() == "/tmp/test.db" },
+		},
+		{
+			name:  "scalar",
+			env:   map[string]string{"TRACKO_DB_PATH": "/data/tracko.db"},
+			check: func(cfg *config_model.ConfigModel) bool { return cfg.DBPath() == "/data/tracko.db" },
+		},
+		{
+			name: "nested list",
+			env:  map[string]string{"TRACKO_AUTHOR_EMAILS": "ci@example.com, bot@example.com"},
+			check: func(cfg *config_model.ConfigModel) bool {
+				return cfg.TrackedAuthor().Name() == "Test User" &&
+					reflect.DeepEqual(cfg.TrackedAuthor().Emails(), []string{"ci@example.com", "bot@example.com"})
+			},
+		},
+		{
+			name: "nested field missing from the file",
+			env:  map[string]string{"TRACKO_SESSIONS_GAP": "90m"},
+			check: func(cfg *config_model.ConfigModel) bool {
+				return cfg.Sessions().Gap() == 90*time.Minute
+			},
+		},
+		{
+			name: "target fields by index",
+			env: map[string]string{
+				"TRACKO_TARGETS_0_BRANCH":      "activity",
+				"TRACKO_TARGETS_0_CREATE":      "true",
+				"TRACKO_TARGETS_0_MAX_PER_DAY": "3",
+			},
+			check: func(cfg *config_model.ConfigModel) bool {
+				target := cfg.Targets()[0]
+				return target.Path() == "/tmp/mirror" && target.Branch() == "activity" && target.Create() && target.MaxPerDay() == 3
+			},
+		},
+		{
+			name: "list of objects",
+			env:  map[string]string{"TRACKO_TRACKED_REPOS": `[{"path": "/ci/repo1"}, {"path": "/ci/repo2"}]`},
+			check: func(cfg *config_model.ConfigModel) bool {
+				return reflect.DeepEqual(cfg.TrackedRepos(), []string{"/ci/repo1", "/ci/repo2"})
+			},
+		},
+		{
+			name: "list of objects and its elements",
+			env: map[string]string{
+				"TRACKO_TARGETS":        `[{"name": "ci", "path": "/ci/mirror", "author": {"name": "CI", "email": "ci@personal.example.com"}}]`,
+				"TRACKO_TARGETS_0_PATH": "/ci/other",
+			},
+			check: func(cfg *config_model.ConfigModel) bool {
+				return len(cfg.Targets()) == 1 && cfg.Targets()[0].Name() == "ci" && cfg.Targets()[0].Path() == "/ci/other"
+			},
+		},
+		{
+			name:  "restricted field",
+			env:   map[string]string{"TRACKO_VERSION": "v9"},
+			check: func(cfg *config_model.ConfigModel) bool { return cfg.Version() == config_model.CurrentVersion },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prepareConfigFile(t, envConfig)
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			cfg, err := GetConfig()
+			if err != nil {
+				t.Fatalf("GetConfig() error = %v", err)
+			}
+			if !tt.check(cfg) {
+				dto, _ := config_model.ConfigDTOFromModel(cfg)
+				t.Errorf("Unexpected config with %v: %+v", tt.env, dto)
+			}
+		})
+	}
+}
+
+func Test_GetConfig_InvalidEnvOverride(t *testing.T) {
+	tests := map[string]string{
+		"TRACKO_TARGETS":               "/ci/mirror",
+		"TRACKO_AUTHOR_EMAILS":         "not an email",
+		"TRACKO_TARGETS_0_MAX_PER_DAY": "many",
+	}
+
+	for key, value := range tests {
+		t.Run(key, func(t *testing.T) {
+			prepareConfigFile(t, envConfig)
+			t.Setenv(key, value)
+
+			if _, err := GetConfig(); !errors.Is(err, internal_errors.ErrInvalidConfig) {
+				t.Errorf("GetConfig() error = %v, want ErrInvalidConfig", err)
+			}
+		})
+	}
+}
+
+func Test_GetConfigAttr_EnvOverride(t *testing.T) {
+	prepareConfigFile(t, envConfig)
+	t.Setenv("TRACKO_AUTHOR_NAME", "CI")
+
+	name, err := GetConfigAttr[string]("author.name")
+	if err != nil || name != "CI" {
+		t.Errorf("GetConfigAttr() = %q, %v, want the env value", name, err)
+	}
+}
+
+func Test_SetConfig_KeepsEnvOutOfFile(t *testing.T) {
+	path := prepareConfigFile(t, envConfig)
+	t.Setenv("TRACKO_DB_PATH", "/data/tracko.db")
+	t.Setenv("TRACKO_TARGETS_0_BRANCH", "activity")
+
+	cfg, err := GetFileConfig()
+	if err != nil {
+		t.Fatalf("GetFileConfig() error = %v", err)
+	}
+	if cfg.DBPath() != "/tmp/test.db" {
+		t.Errorf("Expected the file value, got %q", cfg.DBPath())
+	}
+
+	// Reading the effective config must not leak into what is written back
+	if _, err := GetConfig(); err != nil {
+		t.Fatalf("GetConfig() error = %v", err)
+	}
+	cfg, err = cfg.AppendTrackedRepo("/tmp/repo2")
+	if err != nil {
+		t.Fatalf("AppendTrackedRepo() error = %v", err)
+	}
+	if err := SetConfig(cfg); err != nil {
+		t.Fatalf("SetConfig() error = %v", err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+	if strings.Contains(string(content), "/data/tracko.db") || strings.Contains(string(content), "activity") {
+		t.Errorf("Expected the env values to stay out of the file, got %s", content)
+	}
+	if !strings.Contains(string(content), "/tmp/repo2") {
+		t.Errorf("Expected the new repo in the file, got %s", content)
+	}
+}
+
+func Test_ReadSources(t *testing.T) {
+	prepareConfigFile(t, envConfig)
+	t.Setenv("TRACKO_AUTHOR_EMAILS", "ci@example.com")
+	t.Setenv("TRACKO_TARGETS", `[{"name": "ci", "path": "/ci/mirror", "author": {"name": "CI", "email": "ci@personal.example.com"}}]`)
+
+	sources, err := ReadSources()
+	if err != nil {
+		t.Fatalf("ReadSources() error = %v", err)
+	}
+
+	tests := map[string]string{
+		"db_path":            SourceFile,
+		"author.name":        SourceFile,
+		"author.emails":      SourceEnv,
+		"targets[0].path":    SourceEnv,
+		"tracked_repos":      SourceFile,
+		"tracked_repos[0]":   SourceFile,
+		"sensitive_terms":    SourceDefault,
+		"sessions.gap":       SourceDefault,
+		"targets[1].branch":  SourceEnv,
+		"tracked_repos[3].x": SourceDefault,
+	}
+
+	for key, want := range tests {
+		if got := sources.Of(key); got != want {
+			t.Errorf("Of(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
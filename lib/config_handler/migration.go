@@ -0,0 +1,169 @@
+package config_handler
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/HideyoshiNakazone/tracko/lib/config_model"
+	"github.com/HideyoshiNakazone/tracko/lib/internal_errors"
+)
+
+// BackupTimeFormat is the timestamp in the name of config backups, taken
+// before a config file is migrated.
+const BackupTimeFormat = "20060102T150405"
+
+// Migration rewrites the settings of a config file from the schema of
+// version From to the one of version To. Apply works on the settings as read
+// by viper, the version key is updated by Migrate. The notes Apply returns
+// tell what is left to fix by hand once the file is migrated.
+type Migration struct {
+	From        string
+	To          string
+	Description string
+	Apply       func(settings map[string]any) ([]string, error)
+}
+
+// migrations are keyed by the version they migrate from, every schema change
+// registers one so that old files are rewritten step by step.
+var migrations = map[string]Migration{}
+
+func registerMigration(migration Migration) {
+	migrations[migration.From] = migration
+}
+
+// Migrate applies to settings, in place, every migration from their version
+// up to config_model.CurrentVersion and returns the ones applied, in order,
+// with their notes.
+func Migrate(settings map[string]any) ([]Migration, []string, error) {
+	applied := []Migration{}
+	notes := []string{}
+
+	version, _ := settings["version"].(string)
+	for version != config_model.CurrentVersion {
+		migration, ok := migrations[version]
+		if !ok {
+			return applied, notes, fmt.Errorf("%w: unknown config version %q, the latest is %s", internal_errors.ErrInvalidConfig, version, config_model.CurrentVersion)
+		}
+		migrationNotes, err := migration.Apply(settings)
+		if err != nil {
+			return applied, notes, fmt.Errorf("failed to migrate config from %s to %s: %w", migration.From, migration.To, err)
+		}
+
+		version = migration.To
+		settings["version"] = version
+		applied = append(applied, migration)
+		notes = append(notes, migrationNotes...)
+	}
+	return applied, notes, nil
+}
+
+// MigrationResult is the migration of a config file, Content is the file
+// once migrated and Backup the copy taken before it was rewritten. Notes
+// are the fixes left to the user.
+type MigrationResult struct {
+	Path    string
+	From    string
+	To      string
+	Applied []Migration
+	Notes   []string
+	Content []byte
+	Backup  string
+}
+
+// PlanMigration migrates the config file in use in memory, without writing
+// anything.
+func PlanMigration() (*MigrationResult, error) {
+	if err := viper.ReadInConfig(); err != nil {
+		return nil, internal_errors.ErrConfigNotInitialized
+	}
+
+	settings := viper.AllSettings()
+	version, _ := settings["version"].(string)
+	if version == "" {
+		return nil, internal_errors.ErrConfigNotInitialized
+	}
+
+	applied, notes, err := Migrate(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := encodeSettings(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MigrationResult{
+		Path:    viper.ConfigFileUsed(),
+		From:    version,
+		To:      config_model.CurrentVersion,
+		Applied: applied,
+		Notes:   notes,
+		Content: content,
+	}, nil
+}
+
+// MigrateConfig rewrites the config file in use to the current schema, after
+// copying it next to itself as <file>.<timestamp>.bak. Files already at the
+// current version are left untouched.
+func MigrateConfig() (*MigrationResult, error) {
+	result, err := PlanMigration()
+	if err != nil || len(result.Applied) == 0 {
+		return result, err
+	}
+
+	info, err := os.Stat(result.Path)
+	if err != nil {
+		return nil, err
+	}
+	original, err := os.ReadFile(result.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Backup = fmt.Sprintf("%s.%s.bak", result.Path, time.Now().Format(BackupTimeFormat))
+	if err := os.WriteFile(result.Backup, original, info.Mode().Perm()); err != nil {
+		return nil, fmt.Errorf("failed to back up config: %w", err)
+	}
+	if err := os.WriteFile(result.Path, result.Content, info.Mode().Perm()); err != nil {
+		return nil, err
+	}
+
+	if err := viper.ReadInConfig(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// decodeSettings reads settings as a config, like viper.Unmarshal does with
+// the file in use.
+func decodeSettings(settings map[string]any) (*config_model.ConfigDTO, error) {
+	v := viper.New()
+	if err := v.MergeConfigMap(settings); err != nil {
+		return nil, err
+	}
+
+	var cfg config_model.ConfigDTO
+	if err := v.Unmarshal(&cfg); err != nil {
+		return nil, fmt.Errorf("%w: %v", internal_errors.ErrInvalidConfig, err)
+	}
+	return &cfg, nil
+}
+
+func encodeSettings(settings map[string]any) ([]byte, error) {
+	v := viper.New()
+	v.SetConfigType(configFormat)
+	if err := v.MergeConfigMap(settings); err != nil {
+		return nil, err
+	}
+
+	var content bytes.Buffer
+	if err := v.WriteConfigTo(&content); err != nil {
+		return nil, err
+	}
+	return content.Bytes(), nil
+}
@@ -0,0 +1,186 @@
+package config_handler
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+
+	"github.com/HideyoshiNakazone/tracko/lib/config_model"
+	"github.com/HideyoshiNakazone/tracko/lib/internal_errors"
+)
+
+const v1Config = `version: v1
+db_path: /tmp/test.db
+author:
+  name: Test User
+  emails: [test@example.com]
+targets:
+  - name: default
+    path: /tmp/mirror
+    author:
+      name: Test User
+      email: test@personal.example.com
+tracked_repos:
+  - /tmp/repo1
+  - $HOME/repo2
+`
+
+func prepareConfigFile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	LocateConfig(path)
+	return path
+}
+
+func Test_MigrateConfig(t *testing.T) {
+	path := prepareConfigFile(t, v1Config)
+	expectedRepos := []string{"/tmp/repo1", "$HOME/repo2"}
+
+	// Files are read as migrated before they are rewritten
+	cfg, err := GetConfig()
+	if err != nil {
+		t.Fatalf("GetConfig() error = %v", err)
+	}
+	if cfg.Version() != config_model.CurrentVersion || !reflect.DeepEqual(cfg.TrackedRepos(), expectedRepos) {
+		t.Errorf("Expected the migrated repos %v, got %v at %s", expectedRepos, cfg.TrackedRepos(), cfg.Version())
+	}
+
+	plan, err := PlanMigration()
+	if err != nil {
+		t.Fatalf("PlanMigration() error = %v", err)
+	}
+	if plan.From != "v1" || plan.To != config_model.CurrentVersion || len(plan.Applied) != 1 {
+		t.Errorf("Expected a single migration from v1, got %+v", plan)
+	}
+	if !strings.Contains(string(plan.Content), "- path: /tmp/repo1") {
+		t.Errorf("Expected the repos as objects, got %s", plan.Content)
+	}
+	if content, _ := os.ReadFile(path); string(content) != v1Config {
+		t.Errorf("Expected the plan to leave the file untouched, got %s", content)
+	}
+
+	result, err := MigrateConfig()
+	if err != nil {
+		t.Fatalf("MigrateConfig() error = %v", err)
+	}
+	if backup, err := os.ReadFile(result.Backup); err != nil || string(backup) != v1Config {
+		t.Errorf("Expected a backup of the original file, got %q (%v)", backup, err)
+	}
+	if !strings.HasPrefix(result.Backup, path+".") || !strings.HasSuffix(result.Backup, ".bak") {
+		t.Errorf("Expected the backup next to the config, got %s", result.Backup)
+	}
+
+	if version := viper.GetString("version"); version != config_model.CurrentVersion {
+		t.Errorf("Expected the file at %s, got %s", config_model.CurrentVersion, version)
+	}
+	cfg, err = GetConfig()
+	if err != nil {
+		t.Fatalf("GetConfig() error = %v", err)
+	}
+	if !reflect.DeepEqual(cfg.TrackedRepos(), expectedRepos) {
+		t.Errorf("Expected repos %v, got %v", expectedRepos, cfg.TrackedRepos())
+	}
+
+	// Migrating again is a no-op
+	result, err = MigrateConfig()
+	if err != nil {
+		t.Fatalf("MigrateConfig() error = %v", err)
+	}
+	if len(result.Applied) != 0 || result.Backup != "" {
+		t.Errorf("Expected nothing to migrate, got %+v", result)
+	}
+	backups, _ := filepath.Glob(path + ".*.bak")
+	if len(backups) != 1 {
+		t.Errorf("Expected a single backup, got %v", backups)
+	}
+}
+
+func Test_MigrateConfig_OnLoad(t *testing.T) {
+	path := prepareConfigFile(t, v1Config)
+
+	if err := PrepareConfig(path); err != nil {
+		t.Fatalf("PrepareConfig() error = %v", err)
+	}
+	if content, _ := os.ReadFile(path); !strings.Contains(string(content), "version: "+config_model.CurrentVersion) {
+		t.Errorf("Expected the file migrated on load, got %s", content)
+	}
+}
+
+func Test_Migrate(t *testing.T) {
+	tests := []struct {
+		name      string
+		settings  map[string]any
+		want      map[string]any
+		wantNotes int
+		wantErr   error
+	}{
+		{
+			name:     "v1 repos",
+			settings: map[string]any{"version": "v1", "tracked_repos": []any{"/tmp/repo1"}},
+			want:     map[string]any{"version": "v2", "tracked_repos": []any{map[string]any{"path": "/tmp/repo1"}}},
+		},
+		{
+			name:     "v1 without repos",
+			settings: map[string]any{"version": "v1"},
+			want:     map[string]any{"version": "v2", "tracked_repos": []any{}},
+		},
+		{
+			name:      "v1 target repo",
+			settings:  map[string]any{"version": "v1", "target_repo": "/tmp/mirror"},
+			want:      map[string]any{"version": "v2", "tracked_repos": []any{}, "targets": []any{map[string]any{"name": "default", "path": "/tmp/mirror"}}},
+			wantNotes: 1,
+		},
+		{
+			name:     "v1 target repo next to targets",
+			settings: map[string]any{"version": "v1", "target_repo": "/tmp/mirror", "targets": []any{map[string]any{"name": "work", "path": "/tmp/work"}}},
+			want:     map[string]any{"version": "v2", "tracked_repos": []any{}, "targets": []any{map[string]any{"name": "work", "path": "/tmp/work"}}},
+		},
+		{
+			name:     "Invalid v1 target repo",
+			settings: map[string]any{"version": "v1", "target_repo": 42},
+			wantErr:  internal_errors.ErrInvalidConfig,
+		},
+		{
+			name:     "Current version",
+			settings: map[string]any{"version": config_model.CurrentVersion, "tracked_repos": []any{map[string]any{"path": "/tmp/repo1"}}},
+			want:     map[string]any{"version": config_model.CurrentVersion, "tracked_repos": []any{map[string]any{"path": "/tmp/repo1"}}},
+		},
+		{
+			name:     "Unknown version",
+			settings: map[string]any{"version": "v99"},
+			wantErr:  internal_errors.ErrInvalidConfig,
+		},
+		{
+			name:     "Invalid v1 repo",
+			settings: map[string]any{"version": "v1", "tracked_repos": []any{42}},
+			wantErr:  internal_errors.ErrInvalidConfig,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, notes, err := Migrate(tt.settings)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Migrate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && !reflect.DeepEqual(tt.settings, tt.want) {
+				t.Errorf("Migrate() got %v, want %v", tt.settings, tt.want)
+			}
+			if len(notes) != tt.wantNotes {
+				t.Errorf("Migrate() notes = %v, want %d", notes, tt.wantNotes)
+			}
+		})
+	}
+}
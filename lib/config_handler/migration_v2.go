@@ -0,0 +1,66 @@
+package config_handler
+
+import (
+	"fmt"
+
+	"github.com/HideyoshiNakazone/tracko/lib/config_model"
+	"github.com/HideyoshiNakazone/tracko/lib/internal_errors"
+)
+
+// v2 turns tracked repos into objects, so that repos can carry settings of
+// their own, and moves the single target_repo into targets.
+func init() {
+	registerMigration(Migration{
+		From:        "v1",
+		To:          "v2",
+		Description: "tracked_repos become objects with a path, target_repo becomes the default target",
+		Apply:       migrateToV2,
+	})
+}
+
+func migrateToV2(settings map[string]any) ([]string, error) {
+	if err := migrateTrackedReposToObjects(settings); err != nil {
+		return nil, err
+	}
+	return migrateTargetRepoToTargets(settings)
+}
+
+func migrateTrackedReposToObjects(settings map[string]any) error {
+	repos, _ := settings["tracked_repos"].([]any)
+
+	objects := make([]any, 0, len(repos))
+	for _, repo := range repos {
+		path, ok := repo.(string)
+		if !ok {
+			return fmt.Errorf("%w: tracked repo %v is not a path", internal_errors.ErrInvalidConfig, repo)
+		}
+		objects = append(objects, map[string]any{"path": path})
+	}
+
+	settings["tracked_repos"] = objects
+	return nil
+}
+
+// migrateTargetRepoToTargets moves target_repo into a default target, v1
+// only read it when no targets were set. Targets need an author that
+// target_repo never had, it is left to the user to add.
+func migrateTargetRepoToTargets(settings map[string]any) ([]string, error) {
+	value, ok := settings["target_repo"]
+	if !ok {
+		return nil, nil
+	}
+	delete(settings, "target_repo")
+
+	path, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("%w: target_repo %v is not a path", internal_errors.ErrInvalidConfig, value)
+	}
+	if targets, _ := settings["targets"].([]any); len(targets) > 0 || path == "" {
+		return nil, nil
+	}
+
+	settings["targets"] = []any{map[string]any{"name": config_model.DefaultTargetName, "path": path}}
+	return []string{fmt.Sprintf(
+		"target_repo %s moved to targets[0], add targets[0].author with the name and email to author the mirrored commits as", path,
+	)}, nil
+}
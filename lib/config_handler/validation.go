@@ -0,0 +1,192 @@
+package config_handler
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/HideyoshiNakazone/tracko/lib/config_model"
+	"github.com/HideyoshiNakazone/tracko/lib/internal_errors"
+	"github.com/HideyoshiNakazone/tracko/lib/repo"
+	"github.com/HideyoshiNakazone/tracko/lib/utils"
+)
+
+// ValidationError lists every rule a config breaks, it wraps
+// internal_errors.ErrInvalidConfig.
+type ValidationError struct {
+	Violations []utils.Violation
+}
+
+func (e *ValidationError) Error() string {
+	violations := make([]string, 0, len(e.Violations))
+	for _, violation := range e.Violations {
+		violations = append(violations, violation.String())
+	}
+	return fmt.Sprintf("%s: %s", internal_errors.ErrInvalidConfig, strings.Join(violations, "; "))
+}
+
+func (e *ValidationError) Unwrap() error {
+	return internal_errors.ErrInvalidConfig
+}
+
+// valueRules only look at the values of the config, they are checked every
+// time the config is loaded.
+var valueRules = map[string]utils.ValidationRule{
+	"required": validateRequired,
+	"email":    validateEmail,
+}
+
+// systemRules look at the machine the config is used on. They are only
+// checked by ValidateConfig, a repo on an unmounted disk should not keep
+// every other command from loading the config.
+var systemRules = map[string]utils.ValidationRule{
+	"git_repo": validateGitRepo,
+	"writable": validateWritable,
+	"target":   validateTarget,
+}
+
+// validateValues checks the value rules of the config, every command does
+// on load.
+func validateValues(cfg *config_model.ConfigDTO) error {
+	violations := utils.ValidateModel(cfg, valueRules)
+	if len(violations) > 0 {
+		return &ValidationError{Violations: violations}
+	}
+	return nil
+}
+
+// ValidateConfig checks the effective config, the file in use with its env
+// overrides, against every rule, the ones checked on load and the ones that
+// look at the repositories and paths it points to, and returns every
+// violation found. A config without violations also returns its warnings,
+// see config_model.ConfigModel.Warnings.
+func ValidateConfig() ([]utils.Violation, []string, error) {
+	settings, err := readSettings()
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err := applyEnvOverrides(settings); err != nil {
+		return nil, nil, err
+	}
+
+	cfg, err := decodeSettings(settings)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rules := map[string]utils.ValidationRule{}
+	for name, rule := range valueRules {
+		rules[name] = rule
+	}
+	for name, rule := range systemRules {
+		rules[name] = rule
+	}
+
+	violations := utils.ValidateModel(cfg, rules)
+	if len(violations) > 0 {
+		return violations, nil, nil
+	}
+
+	// What the rules do not cover, like unknown strategies, is still caught
+	// when building the model
+	model, err := cfg.ToModel()
+	if err != nil {
+		return []utils.Violation{{Message: err.Error()}}, nil, nil
+	}
+	return violations, model.Warnings(), nil
+}
+
+func validateRequired(v reflect.Value) string {
+	var missing bool
+	switch v.Kind() {
+	case reflect.String:
+		missing = strings.TrimSpace(v.String()) == ""
+	case reflect.Slice, reflect.Map:
+		missing = v.Len() == 0
+	default:
+		missing = v.IsZero()
+	}
+	if missing {
+		return "is required"
+	}
+	return ""
+}
+
+func validateEmail(v reflect.Value) string {
+	if v.String() == "" || config_model.IsEmail(v.String()) {
+		return ""
+	}
+	return fmt.Sprintf("%q is not a valid email", v.String())
+}
+
+func validateGitRepo(v reflect.Value) string {
+	path := os.ExpandEnv(v.String())
+	if path == "" {
+		return ""
+	}
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Sprintf("%s does not exist", path)
+	}
+	if !repo.IsGitRepository(path) {
+		return fmt.Sprintf("%s is not a git repository", path)
+	}
+	return ""
+}
+
+// validateWritable checks that the file at the path can be written, or
+// created when it is missing.
+func validateWritable(v reflect.Value) string {
+	path := os.ExpandEnv(v.String())
+	if path == "" {
+		return ""
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err == nil {
+		file.Close()
+		return ""
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return fmt.Sprintf("%s is not writable: %v", path, err)
+	}
+
+	dir := filepath.Dir(path)
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return fmt.Sprintf("%s cannot be created, directory %s does not exist", path, dir)
+	}
+	probe, err := os.CreateTemp(dir, ".tracko-*")
+	if err != nil {
+		return fmt.Sprintf("%s cannot be created in %s", path, dir)
+	}
+	probe.Close()
+	os.Remove(probe.Name())
+	return ""
+}
+
+// validateTarget checks that the repository of a target exists, or that the
+// export can bootstrap it.
+func validateTarget(v reflect.Value) string {
+	target, ok := v.Interface().(config_model.TargetDTO)
+	if !ok || target.Path == "" {
+		return ""
+	}
+	// Partitions are repositories of their own, next to the target path
+	if target.Layout.Split != config_model.LayoutSplitNone && target.Layout.Into == config_model.LayoutIntoRepo {
+		return ""
+	}
+
+	path := os.ExpandEnv(target.Path)
+	if _, err := os.Stat(path); err != nil {
+		if target.Create || target.URL != "" {
+			return ""
+		}
+		return fmt.Sprintf("path %s does not exist, set create or url to bootstrap it", path)
+	}
+	if !repo.IsGitRepository(path) {
+		return fmt.Sprintf("path %s is not a git repository", path)
+	}
+	return ""
+}
@@ -0,0 +1,139 @@
+package config_handler
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/HideyoshiNakazone/tracko/lib/internal_errors"
+	"github.com/HideyoshiNakazone/tracko/lib/repo"
+	"github.com/HideyoshiNakazone/tracko/lib/utils"
+)
+
+func prepareGitRepository(t *testing.T) string {
+	t.Helper()
+
+	repoPath, cleanup, err := repo.PrepareTestRepository(nil)
+	if err != nil {
+		t.Fatalf("Failed to prepare repository: %v", err)
+	}
+	t.Cleanup(*cleanup)
+	return repoPath
+}
+
+func Test_ValidateConfig(t *testing.T) {
+	repoPath := prepareGitRepository(t)
+	dir := t.TempDir()
+
+	valid := fmt.Sprintf(`version: v2
+db_path: %[1]s/tracko.db
+author:
+  name: Test User
+  emails: [test@example.com]
+targets:
+  - name: github
+    path: %[2]s
+    author:
+      name: Personal
+      email: me@personal.dev
+  - name: gitlab
+    path: %[1]s/mirror
+    create: true
+    author:
+      name: Test User
+      email: test@example.com
+tracked_repos:
+  - path: %[2]s
+`, dir, repoPath)
+
+	prepareConfigFile(t, valid)
+	violations, warnings, err := ValidateConfig()
+	if err != nil {
+		t.Fatalf("ValidateConfig() error = %v", err)
+	}
+	if len(violations) != 0 {
+		t.Errorf("Expected a valid config, got %v", violations)
+	}
+	expectedWarnings := []string{`target "gitlab" exports as test@example.com, which is a tracked email`}
+	if !reflect.DeepEqual(warnings, expectedWarnings) {
+		t.Errorf("Expected warnings %q, got %q", expectedWarnings, warnings)
+	}
+
+	invalid := fmt.Sprintf(`version: v2
+db_path: %[1]s/missing/tracko.db
+author:
+  name: ""
+  emails: [test@example.com, not-an-email]
+targets:
+  - name: github
+    path: %[1]s/mirror
+    author:
+      name: Personal
+      email: Personal <me@personal.dev>
+  - name: gitlab
+    path: %[1]s
+tracked_repos:
+  - path: %[2]s
+  - path: %[1]s/gone
+  - path: ""
+sensitive_terms: ["acme", " "]
+`, dir, repoPath)
+
+	prepareConfigFile(t, invalid)
+	violations, _, err = ValidateConfig()
+	if err != nil {
+		t.Fatalf("ValidateConfig() error = %v", err)
+	}
+
+	fields := []string{}
+	for _, violation := range violations {
+		fields = append(fields, violation.Field)
+	}
+	expected := []string{
+		"db_path",
+		"author.name",
+		"author.emails[1]",
+		"targets[0]",
+		"targets[1]",
+		"targets[0].author.email",
+		"targets[1].author.name",
+		"targets[1].author.email",
+		"tracked_repos[1].path",
+		"tracked_repos[2].path",
+		"sensitive_terms[1]",
+	}
+	if !reflect.DeepEqual(fields, expected) {
+		t.Errorf("Expected violations of %v, got %v", expected, violations)
+	}
+	for i, message := range map[int]string{
+		0: filepath.Join(dir, "missing", "tracko.db") + " cannot be created, directory " + filepath.Join(dir, "missing") + " does not exist",
+		3: "path " + filepath.Join(dir, "mirror") + " does not exist, set create or url to bootstrap it",
+		4: "path " + dir + " is not a git repository",
+		8: filepath.Join(dir, "gone") + " does not exist",
+	} {
+		if violations[i].Message != message {
+			t.Errorf("Expected %q for %s, got %q", message, violations[i].Field, violations[i].Message)
+		}
+	}
+
+	// Loading only fails on the value rules, all of them at once
+	_, err = GetConfig()
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || !errors.Is(err, internal_errors.ErrInvalidConfig) {
+		t.Fatalf("Expected a ValidationError, got %v", err)
+	}
+	expectedViolations := []utils.Violation{
+		{Field: "author.name", Message: "is required"},
+		{Field: "author.emails[1]", Message: `"not-an-email" is not a valid email`},
+		{Field: "targets[0].author.email", Message: `"Personal <me@personal.dev>" is not a valid email`},
+		{Field: "targets[1].author.name", Message: "is required"},
+		{Field: "targets[1].author.email", Message: "is required"},
+		{Field: "tracked_repos[2].path", Message: "is required"},
+		{Field: "sensitive_terms[1]", Message: "is required"},
+	}
+	if !reflect.DeepEqual(validationErr.Violations, expectedViolations) {
+		t.Errorf("Expected %v on load, got %v", expectedViolations, validationErr.Violations)
+	}
+}
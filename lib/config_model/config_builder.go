@@ -1,6 +1,11 @@
 package config_model
 
-import "github.com/HideyoshiNakazone/tracko/lib/internal_errors"
+import (
+	"strings"
+	"time"
+
+	"github.com/HideyoshiNakazone/tracko/lib/internal_errors"
+)
 
 // ConfigModelBuilder is a builder for ConfigModel
 type ConfigModelBuilder struct {
@@ -10,11 +15,14 @@ type ConfigModelBuilder struct {
 func NewConfigBuilder() *ConfigModelBuilder {
 	return &ConfigModelBuilder{
 		config: &ConfigModel{
-			version:       CurrentVersion,
-			dbPath:        DefaultDBPath,
-			trackedAuthor: ConfigAuthorModel{},
-			targetRepo:    "",
-			trackedRepos:  []string{},
+			version:        CurrentVersion,
+			dbPath:         DefaultDBPath,
+			trackedAuthor:  ConfigAuthorModel{},
+			targets:        []ConfigTargetModel{},
+			trackedRepos:   []string{},
+			sessions:       NewDefaultSessionModel(),
+			projects:       []ConfigProjectModel{},
+			sensitiveTerms: []string{},
 		},
 	}
 }
@@ -45,8 +53,14 @@ func (c *ConfigModelBuilder) WithAppendTrackedAuthorEmail(email string) *ConfigM
 	return c
 }
 
-func (c *ConfigModelBuilder) WithTargetRepo(repo string) *ConfigModelBuilder {
-	c.config.targetRepo = repo
+// WithTargetRepo adds a default target mirroring every tracked repo into the
+// repository at repo, authored as authorName and authorEmail.
+func (c *ConfigModelBuilder) WithTargetRepo(repo string, authorName string, authorEmail string) *ConfigModelBuilder {
+	return c.WithTarget(NewTargetBuilder(DefaultTargetName, repo).WithAuthor(authorName, authorEmail))
+}
+
+func (c *ConfigModelBuilder) WithTarget(target *ConfigTargetBuilder) *ConfigModelBuilder {
+	c.config.targets = append(c.config.targets, *target.target)
 	return c
 }
 
@@ -55,6 +69,25 @@ func (c *ConfigModelBuilder) WithTrackedRepos(repos []string) *ConfigModelBuilde
 	return c
 }
 
+func (c *ConfigModelBuilder) WithSessions(gap time.Duration, lead time.Duration) *ConfigModelBuilder {
+	c.config.sessions = ConfigSessionModel{gap: gap, lead: lead}
+	return c
+}
+
+// WithProject maps the repo, by path or alias, to a client and project for
+// timesheets.
+func (c *ConfigModelBuilder) WithProject(repo string, client string, project string, task string) *ConfigModelBuilder {
+	c.config.projects = append(c.config.projects, ConfigProjectModel{repo: repo, client: client, project: project, task: task})
+	return c
+}
+
+// WithSensitiveTerms sets the words the export audit looks for in target
+// repositories.
+func (c *ConfigModelBuilder) WithSensitiveTerms(terms []string) *ConfigModelBuilder {
+	c.config.sensitiveTerms = terms
+	return c
+}
+
 func (c *ConfigModelBuilder) Build() (*ConfigModel, error) {
 	if c.config.version == "" {
 		return nil, internal_errors.ErrInvalidConfig
@@ -72,9 +105,35 @@ func (c *ConfigModelBuilder) Build() (*ConfigModel, error) {
 		return nil, internal_errors.ErrInvalidConfig
 	}
 
-	if c.config.targetRepo == "" {
+	if !c.config.sessions.isValid() {
+		return nil, internal_errors.ErrInvalidConfig
+	}
+
+	for _, term := range c.config.sensitiveTerms {
+		if strings.TrimSpace(term) == "" {
+			return nil, internal_errors.ErrInvalidConfig
+		}
+	}
+
+	projectRepos := map[string]bool{}
+	for _, project := range c.config.projects {
+		if !project.isValid() || projectRepos[project.repo] {
+			return nil, internal_errors.ErrInvalidConfig
+		}
+		projectRepos[project.repo] = true
+	}
+
+	if len(c.config.targets) == 0 {
 		return nil, internal_errors.ErrInvalidConfig
 	}
 
+	targetNames := map[string]bool{}
+	for _, target := range c.config.targets {
+		if !target.isValid() || targetNames[target.name] {
+			return nil, internal_errors.ErrInvalidConfig
+		}
+		targetNames[target.name] = true
+	}
+
 	return c.config, nil
 }
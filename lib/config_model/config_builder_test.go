@@ -3,6 +3,9 @@ package config_model
 import (
 	"reflect"
 	"testing"
+	"time"
+
+	"github.com/HideyoshiNakazone/tracko/lib/commit_message"
 )
 
 
@@ -20,7 +23,7 @@ func Test_ConfigBuilder(t *testing.T) {
 						WithTrackedAuthor("Test User", []string{
 							"test@example.com",
 						}).
-						WithTargetRepo("repo1"),
+						WithTargetRepo("repo1", "Test User", "test@personal.example.com"),
             expected: &ConfigModel{
 				version: 	 	CurrentVersion,
 				dbPath:        	"/tmp/test.db",
@@ -28,8 +31,22 @@ func Test_ConfigBuilder(t *testing.T) {
 					name:  "Test User",
 					emails: []string{"test@example.com"},
 				},
-				targetRepo:   	"repo1",
+				targets: 		[]ConfigTargetModel{{
+					name:            DefaultTargetName,
+					path:            "repo1",
+					repos:           []string{},
+					messageTemplate: commit_message.DefaultTemplate,
+					strategy:        StrategyOneToOne,
+					content:         ContentEmpty,
+					author:          ConfigIdentityModel{name: "Test User", email: "test@personal.example.com"},
+					signing:         NewDefaultSigningModel(),
+					push:            NewDefaultPushModel(),
+					layout:          NewDefaultLayoutModel(),
+				}},
 				trackedRepos: 	[]string{},
+				sessions: 		NewDefaultSessionModel(),
+				projects: 		[]ConfigProjectModel{},
+				sensitiveTerms: []string{},
 			},
 			wantErr: 	false,
         },
@@ -42,10 +59,71 @@ func Test_ConfigBuilder(t *testing.T) {
             expected: 	nil,
             wantErr: 	true,
         },
+        {
+            name:    	"invalid config - invalid message template",
+            builder: 	NewConfigBuilder().
+							WithTrackedAuthor("Test User", []string{
+								"test@example.com",
+							}).
+							WithTarget(NewTargetBuilder("github", "repo1").
+								WithAuthor("Test User", "test@personal.example.com").
+								WithMessageTemplate("{{.Unknown}}")),
+            expected: 	nil,
+            wantErr: 	true,
+        },
+        {
+            name:    	"invalid config - unknown push auth method",
+            builder: 	NewConfigBuilder().
+							WithTrackedAuthor("Test User", []string{
+								"test@example.com",
+							}).
+							WithTarget(NewTargetBuilder("github", "repo1").
+								WithAuthor("Test User", "test@personal.example.com").
+								WithPushAuth("password")),
+            expected: 	nil,
+            wantErr: 	true,
+        },
+        {
+            name:    	"invalid config - duplicated target name",
+            builder: 	NewConfigBuilder().
+							WithTrackedAuthor("Test User", []string{
+								"test@example.com",
+							}).
+							WithTarget(NewTargetBuilder("github", "repo1").WithAuthor("Test User", "test@personal.example.com")).
+							WithTarget(NewTargetBuilder("github", "repo2").WithAuthor("Test User", "test@personal.example.com")),
+            expected: 	nil,
+            wantErr: 	true,
+        },
+        {
+            name:    	"invalid config - inverted date range",
+            builder: 	NewConfigBuilder().
+							WithTrackedAuthor("Test User", []string{
+								"test@example.com",
+							}).
+							WithTarget(NewTargetBuilder("github", "repo1").
+								WithAuthor("Test User", "test@personal.example.com").
+								WithDateRange(
+									time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+									time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+								)),
+            expected: 	nil,
+            wantErr: 	true,
+        },
+        {
+            name:    	"invalid config - target without author",
+            builder: 	NewConfigBuilder().
+							WithDBPath("/tmp/test.db").
+							WithTrackedAuthor("Test User", []string{
+								"test@example.com",
+							}).
+							WithTarget(NewTargetBuilder("github", "repo1")),
+            expected: 	nil,
+            wantErr: 	true,
+        },
         {
             name:    	"invalid config - missing tracked author",
             builder: 	NewConfigBuilder().
-							WithTargetRepo("repo1"),
+							WithTargetRepo("repo1", "Test User", "test@personal.example.com"),
             expected: 	nil,
             wantErr: 	true,
         },
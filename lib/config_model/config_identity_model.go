@@ -0,0 +1,70 @@
+package config_model
+
+import (
+	"fmt"
+	"net/mail"
+	"strings"
+
+	"github.com/HideyoshiNakazone/tracko/lib/internal_errors"
+)
+
+// Internal Identity Model
+// The name and email mirrored commits are authored as, usually the personal
+// identity registered on the public host rather than the tracked one.
+type ConfigIdentityModel struct {
+	name  string
+	email string
+}
+
+func (i ConfigIdentityModel) Name() string {
+	return i.name
+}
+
+func (i ConfigIdentityModel) Email() string {
+	return i.email
+}
+
+// Matches reports whether the identity uses one of emails, ignoring case.
+func (i ConfigIdentityModel) Matches(emails []string) bool {
+	for _, email := range emails {
+		if strings.EqualFold(strings.TrimSpace(email), i.email) {
+			return true
+		}
+	}
+	return false
+}
+
+func (i ConfigIdentityModel) isValid() bool {
+	return i.name != "" && IsEmail(i.email)
+}
+
+// IsEmail reports whether email is a bare address, like user@example.com,
+// without a display name or angle brackets.
+func IsEmail(email string) bool {
+	address, err := mail.ParseAddress(email)
+	return err == nil && address.Address == email && address.Name == ""
+}
+
+// External Identity DTO
+type IdentityDTO struct {
+	Name  string `mapstructure:"name" validate:"required"`
+	Email string `mapstructure:"email" validate:"required,email"`
+}
+
+func (i IdentityDTO) ToModel() (ConfigIdentityModel, error) {
+	model := ConfigIdentityModel{
+		name:  strings.TrimSpace(i.Name),
+		email: strings.TrimSpace(i.Email),
+	}
+	if !model.isValid() {
+		return ConfigIdentityModel{}, fmt.Errorf("%w: author requires a name and a valid email, got %q <%s>", internal_errors.ErrInvalidConfig, i.Name, i.Email)
+	}
+	return model, nil
+}
+
+func IdentityDTOFromModel(model ConfigIdentityModel) IdentityDTO {
+	return IdentityDTO{
+		Name:  model.name,
+		Email: model.email,
+	}
+}
@@ -0,0 +1,153 @@
+package config_model
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/HideyoshiNakazone/tracko/lib/internal_errors"
+)
+
+// Layout splits, they control how the export of a target is partitioned.
+const (
+	LayoutSplitNone      = ""
+	LayoutSplitYear      = "year"
+	LayoutSplitRepoGroup = "repo-group"
+)
+
+var LayoutSplits = []string{LayoutSplitNone, LayoutSplitYear, LayoutSplitRepoGroup}
+
+// Layout destinations, they control where every partition is written.
+const (
+	LayoutIntoBranch = "branch"
+	LayoutIntoRepo   = "repo"
+)
+
+var LayoutIntos = []string{LayoutIntoBranch, LayoutIntoRepo}
+
+// DefaultPartitionBranch is the branch partitions are named after when the
+// target has no branch configured.
+var DefaultPartitionBranch = "tracko"
+
+// Internal Repo Group Model
+type ConfigRepoGroupModel struct {
+	name  string
+	repos []string
+}
+
+func (g ConfigRepoGroupModel) Name() string {
+	return g.name
+}
+
+// Repos are the tracked repos of the group, by path or alias.
+func (g ConfigRepoGroupModel) Repos() []string {
+	return g.repos
+}
+
+// Internal Layout Model
+// Every partition of a split target is exported as a target of its own, on
+// its own branch or in its own repository, with its own mapping table.
+type ConfigLayoutModel struct {
+	split  string
+	into   string
+	groups []ConfigRepoGroupModel
+}
+
+func NewDefaultLayoutModel() ConfigLayoutModel {
+	return ConfigLayoutModel{
+		split:  LayoutSplitNone,
+		into:   LayoutIntoBranch,
+		groups: []ConfigRepoGroupModel{},
+	}
+}
+
+// Split is what the export is partitioned by, none keeps a single export.
+func (l ConfigLayoutModel) Split() string {
+	return l.split
+}
+
+// Into is where partitions are written, to branches of the target repository
+// or to repositories next to it.
+func (l ConfigLayoutModel) Into() string {
+	return l.into
+}
+
+// Groups are the partitions of the repo-group split, repos in no group are
+// not exported.
+func (l ConfigLayoutModel) Groups() []ConfigRepoGroupModel {
+	return l.groups
+}
+
+func (l ConfigLayoutModel) Partitioned() bool {
+	return l.split != LayoutSplitNone
+}
+
+func (l ConfigLayoutModel) isValid() bool {
+	if !slices.Contains(LayoutSplits, l.split) || !slices.Contains(LayoutIntos, l.into) {
+		return false
+	}
+	if l.split != LayoutSplitRepoGroup {
+		return len(l.groups) == 0
+	}
+	if len(l.groups) == 0 {
+		return false
+	}
+
+	names := []string{}
+	for _, group := range l.groups {
+		if group.name == "" || len(group.repos) == 0 || slices.Contains(names, group.name) {
+			return false
+		}
+		names = append(names, group.name)
+	}
+	return true
+}
+
+// External Repo Group DTO
+type RepoGroupDTO struct {
+	Name  string   `mapstructure:"name"`
+	Repos []string `mapstructure:"repos"`
+}
+
+// External Layout DTO
+type LayoutDTO struct {
+	Split  string         `mapstructure:"split"`
+	Into   string         `mapstructure:"into"`
+	Groups []RepoGroupDTO `mapstructure:"groups"`
+}
+
+// ToModel reads a missing split as none and a missing into as branch, a
+// target without a layout keeps a single export.
+func (l LayoutDTO) ToModel() (ConfigLayoutModel, error) {
+	model := NewDefaultLayoutModel()
+	model.split = l.Split
+	if l.Into != "" {
+		model.into = l.Into
+	}
+	for _, group := range l.Groups {
+		model.groups = append(model.groups, ConfigRepoGroupModel{name: group.Name, repos: group.Repos})
+	}
+
+	if !slices.Contains(LayoutSplits, model.split) {
+		return ConfigLayoutModel{}, fmt.Errorf("%w: unknown layout split %q", internal_errors.ErrInvalidConfig, model.split)
+	}
+	if !slices.Contains(LayoutIntos, model.into) {
+		return ConfigLayoutModel{}, fmt.Errorf("%w: unknown layout into %q, must be one of %v", internal_errors.ErrInvalidConfig, model.into, LayoutIntos)
+	}
+	if !model.isValid() {
+		return ConfigLayoutModel{}, fmt.Errorf("%w: the %s layout split requires groups with a unique name and repos, and only it takes groups", internal_errors.ErrInvalidConfig, LayoutSplitRepoGroup)
+	}
+
+	return model, nil
+}
+
+func LayoutDTOFromModel(model ConfigLayoutModel) LayoutDTO {
+	dto := LayoutDTO{
+		Split:  model.split,
+		Into:   model.into,
+		Groups: []RepoGroupDTO{},
+	}
+	for _, group := range model.groups {
+		dto.Groups = append(dto.Groups, RepoGroupDTO{Name: group.name, Repos: group.repos})
+	}
+	return dto
+}
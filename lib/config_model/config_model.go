@@ -2,11 +2,15 @@ package config_model
 
 import (
 	"fmt"
+	"path/filepath"
 	"slices"
+	"strings"
+
+	"github.com/HideyoshiNakazone/tracko/lib/internal_errors"
 )
 
 
-var CurrentVersion = "v1"
+var CurrentVersion = "v2"
 var DefaultDBPath = "$HOME/.config/tracko.db"
 
 
@@ -26,11 +30,14 @@ func (a ConfigAuthorModel) Emails() []string {
 }
 
 type ConfigModel struct {
-	version       string
-	dbPath        string
-	trackedAuthor ConfigAuthorModel
-	targetRepo    string
-	trackedRepos  []string
+	version        string
+	dbPath         string
+	trackedAuthor  ConfigAuthorModel
+	targets        []ConfigTargetModel
+	trackedRepos   []string
+	sessions       ConfigSessionModel
+	projects       []ConfigProjectModel
+	sensitiveTerms []string
 }
 
 
@@ -47,14 +54,61 @@ func (c ConfigModel) TrackedAuthor() ConfigAuthorModel {
 	return c.trackedAuthor
 }
 
-func (c ConfigModel) TargetRepo() string {
-	return c.targetRepo
+func (c ConfigModel) Targets() []ConfigTargetModel {
+	return c.targets
+}
+
+func (c ConfigModel) Target(name string) (ConfigTargetModel, error) {
+	for _, target := range c.targets {
+		if target.name == name {
+			return target, nil
+		}
+	}
+	return ConfigTargetModel{}, fmt.Errorf("%w: %s", internal_errors.ErrUnknownTarget, name)
 }
 
 func (c ConfigModel) TrackedRepos() []string {
 	return c.trackedRepos
 }
 
+func (c ConfigModel) Sessions() ConfigSessionModel {
+	return c.sessions
+}
+
+func (c ConfigModel) Projects() []ConfigProjectModel {
+	return c.projects
+}
+
+// SensitiveTerms are words that must never show up in a target repository,
+// like client or internal project names, checked by the export audit.
+func (c ConfigModel) SensitiveTerms() []string {
+	return c.sensitiveTerms
+}
+
+// Project returns the client and project the repo at repoPath is billed to,
+// repos without a mapping are their own project without client.
+func (c ConfigModel) Project(repoPath string) ConfigProjectModel {
+	for _, project := range c.projects {
+		if project.Matches(repoPath) {
+			return project
+		}
+	}
+	alias := filepath.Base(repoPath)
+	return ConfigProjectModel{repo: alias, project: alias}
+}
+
+// Warnings lists the settings that are valid but likely wrong, like a target
+// exporting as one of the tracked emails, which are usually corporate ones.
+func (c ConfigModel) Warnings() []string {
+	warnings := []string{}
+	for _, target := range c.targets {
+		if author := target.Author(); author.Matches(c.trackedAuthor.emails) {
+			warnings = append(warnings, fmt.Sprintf("target %q exports as %s, which is a tracked email", target.name, author.Email()))
+		}
+	}
+	return warnings
+}
+
 
 // Manipulation methods for config
 func (c ConfigModel) AppendTrackedRepo(repo string) (*ConfigModel, error) {
@@ -78,8 +132,8 @@ func (c ConfigModel) RemoveTrackedRepo(repo string) (*ConfigModel, error) {
 
 // External Config DTO
 type AuthorDTO struct {
-	Name   string   `mapstructure:"name"`
-	Emails []string `mapstructure:"emails"`
+	Name   string   `mapstructure:"name" validate:"required"`
+	Emails []string `mapstructure:"emails" validate:"required,dive,email"`
 }
 
 func (a AuthorDTO) ToModel() *ConfigAuthorModel {
@@ -89,12 +143,22 @@ func (a AuthorDTO) ToModel() *ConfigAuthorModel {
 	}
 }
 
+// RepoDTO is a tracked repo, an object since v2 so that repos can carry
+// settings of their own, only the path for now.
+type RepoDTO struct {
+	Path string `mapstructure:"path" validate:"required,git_repo"`
+}
+
 type ConfigDTO struct {
-	Version       string   	`mapstructure:"version" restricted:"true"`
-	DBPath        string   	`mapstructure:"db_path"`
-	TrackedAuthor AuthorDTO `mapstructure:"author"`
-	TargetRepo    string   	`mapstructure:"target_repo"`
-	TrackedRepos  []string 	`mapstructure:"tracked_repos"`
+	Version       string       `mapstructure:"version" restricted:"true" validate:"required"`
+	DBPath        string       `mapstructure:"db_path" validate:"required,writable"`
+	TrackedAuthor AuthorDTO    `mapstructure:"author"`
+	Targets       []TargetDTO  `mapstructure:"targets" validate:"dive,target"`
+	TrackedRepos  []RepoDTO    `mapstructure:"tracked_repos"`
+	Sessions      SessionDTO   `mapstructure:"sessions"`
+	Projects      []ProjectDTO `mapstructure:"projects"`
+
+	SensitiveTerms []string `mapstructure:"sensitive_terms" validate:"dive,required"`
 }
 
 func (c ConfigDTO) ToModel() (*ConfigModel, error) {
@@ -102,12 +166,48 @@ func (c ConfigDTO) ToModel() (*ConfigModel, error) {
 	if trackedAuthor == nil {
 		return nil, fmt.Errorf("invalid author")
 	}
+	targets := make([]ConfigTargetModel, 0, len(c.Targets))
+	for _, targetDTO := range c.Targets {
+		target, err := targetDTO.ToModel()
+		if err != nil {
+			return nil, err
+		}
+		if slices.ContainsFunc(targets, func(t ConfigTargetModel) bool { return t.name == target.name }) {
+			return nil, fmt.Errorf("%w: duplicated target %q", internal_errors.ErrInvalidConfig, target.name)
+		}
+		targets = append(targets, *target)
+	}
+	sessions, err := c.Sessions.ToModel()
+	if err != nil {
+		return nil, err
+	}
+	projects, err := projectsFromDTOs(c.Projects)
+	if err != nil {
+		return nil, err
+	}
+	trackedRepos := make([]string, 0, len(c.TrackedRepos))
+	for _, repo := range c.TrackedRepos {
+		if strings.TrimSpace(repo.Path) == "" {
+			return nil, fmt.Errorf("%w: tracked repo without a path", internal_errors.ErrInvalidConfig)
+		}
+		trackedRepos = append(trackedRepos, repo.Path)
+	}
+	sensitiveTerms := []string{}
+	for _, term := range c.SensitiveTerms {
+		if strings.TrimSpace(term) == "" {
+			return nil, fmt.Errorf("%w: empty sensitive term", internal_errors.ErrInvalidConfig)
+		}
+		sensitiveTerms = append(sensitiveTerms, term)
+	}
 	return &ConfigModel{
-		version:       c.Version,
-		dbPath:        c.DBPath,
-		trackedAuthor: *trackedAuthor,
-		targetRepo:    c.TargetRepo,
-		trackedRepos:  c.TrackedRepos,
+		version:        c.Version,
+		dbPath:         c.DBPath,
+		trackedAuthor:  *trackedAuthor,
+		targets:        targets,
+		trackedRepos:   trackedRepos,
+		sessions:       sessions,
+		projects:       projects,
+		sensitiveTerms: sensitiveTerms,
 	}, nil
 }
 
@@ -115,6 +215,18 @@ func ConfigDTOFromModel(model *ConfigModel) (*ConfigDTO, error) {
 	if model == nil {
 		return nil, fmt.Errorf("invalid config model")
 	}
+	targets := make([]TargetDTO, 0, len(model.targets))
+	for _, target := range model.targets {
+		targets = append(targets, TargetDTOFromModel(target))
+	}
+	trackedRepos := make([]RepoDTO, 0, len(model.trackedRepos))
+	for _, repo := range model.trackedRepos {
+		trackedRepos = append(trackedRepos, RepoDTO{Path: repo})
+	}
+	projects := make([]ProjectDTO, 0, len(model.projects))
+	for _, project := range model.projects {
+		projects = append(projects, ProjectDTOFromModel(project))
+	}
 	return &ConfigDTO{
 		Version:       model.version,
 		DBPath:        model.dbPath,
@@ -122,7 +234,11 @@ func ConfigDTOFromModel(model *ConfigModel) (*ConfigDTO, error) {
 			Name:   model.trackedAuthor.name,
 			Emails: model.trackedAuthor.emails,
 		},
-		TargetRepo:    model.targetRepo,
-		TrackedRepos:  model.trackedRepos,
+		Targets:       targets,
+		TrackedRepos:  trackedRepos,
+		Sessions:      SessionDTOFromModel(model.sessions),
+		Projects:      projects,
+
+		SensitiveTerms: model.sensitiveTerms,
 	}, nil
 }
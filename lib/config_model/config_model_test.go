@@ -1,8 +1,10 @@
 package config_model
 
 import (
+	"errors"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/HideyoshiNakazone/tracko/lib/internal_errors"
 )
@@ -20,25 +22,40 @@ func Test_ConfigModelBuilder_Build(t *testing.T) {
 				version:       "v1",
 				dbPath:        "$HOME/.config/tracko.db",
 				trackedAuthor: ConfigAuthorModel{name: "test", emails: []string{"test@example.com"}},
-				targetRepo:    "test/repo",
+				targets:       []ConfigTargetModel{*NewTargetBuilder("default", "test/repo").WithAuthor("Test User", "test@personal.example.com").target},
 				trackedRepos:  []string{"repo1", "repo2"},
+				sessions:      NewDefaultSessionModel(),
 			},
 			want: &ConfigModel{
 				version:       "v1",
 				dbPath:        "$HOME/.config/tracko.db",
 				trackedAuthor: ConfigAuthorModel{name: "test", emails: []string{"test@example.com"}},
-				targetRepo:    "test/repo",
+				targets:       []ConfigTargetModel{*NewTargetBuilder("default", "test/repo").WithAuthor("Test User", "test@personal.example.com").target},
 				trackedRepos:  []string{"repo1", "repo2"},
+				sessions:      NewDefaultSessionModel(),
 			},
 			wantErr: nil,
 		},
+		{
+			name: "invalid config - invalid session gap",
+			config: &ConfigModel{
+				version:       "v1",
+				dbPath:        "$HOME/.config/tracko.db",
+				trackedAuthor: ConfigAuthorModel{name: "test", emails: []string{"test@example.com"}},
+				targets:       []ConfigTargetModel{*NewTargetBuilder("default", "test/repo").WithAuthor("Test User", "test@personal.example.com").target},
+				trackedRepos:  []string{"repo1", "repo2"},
+				sessions:      ConfigSessionModel{gap: 0, lead: DefaultSessionLead},
+			},
+			want:    nil,
+			wantErr: internal_errors.ErrInvalidConfig,
+		},
 		{
 			name: "invalid config - missing version",
 			config: &ConfigModel{
 				version:       "",
 				dbPath:        "$HOME/.config/tracko.db",
 				trackedAuthor: ConfigAuthorModel{name: "test", emails: []string{"test@example.com"}},
-				targetRepo:    "test/repo",
+				targets:       []ConfigTargetModel{*NewTargetBuilder("default", "test/repo").WithAuthor("Test User", "test@personal.example.com").target},
 				trackedRepos:  []string{"repo1", "repo2"},
 			},
 			want:    nil,
@@ -50,7 +67,7 @@ func Test_ConfigModelBuilder_Build(t *testing.T) {
 				version:       "v1",
 				dbPath:        "",
 				trackedAuthor: ConfigAuthorModel{name: "test", emails: []string{"test@example.com"}},
-				targetRepo:    "test/repo",
+				targets:       []ConfigTargetModel{*NewTargetBuilder("default", "test/repo").WithAuthor("Test User", "test@personal.example.com").target},
 				trackedRepos:  []string{"repo1", "repo2"},
 			},
 			want:    nil,
@@ -62,19 +79,33 @@ func Test_ConfigModelBuilder_Build(t *testing.T) {
 				version:       "v1",
 				dbPath:        "$HOME/.config/tracko.db",
 				trackedAuthor: ConfigAuthorModel{name: "", emails: []string{"test@example.com"}},
-				targetRepo:    "test/repo",
+				targets:       []ConfigTargetModel{*NewTargetBuilder("default", "test/repo").WithAuthor("Test User", "test@personal.example.com").target},
 				trackedRepos:  []string{"repo1", "repo2"},
 			},
 			want:    nil,
 			wantErr: internal_errors.ErrInvalidConfig,
 		},
 		{
-			name: "invalid config - missing target repo",
+			name: "invalid config - blank sensitive term",
+			config: &ConfigModel{
+				version:        "v1",
+				dbPath:         "$HOME/.config/tracko.db",
+				trackedAuthor:  ConfigAuthorModel{name: "test", emails: []string{"test@example.com"}},
+				targets:        []ConfigTargetModel{*NewTargetBuilder("default", "test/repo").WithAuthor("Test User", "test@personal.example.com").target},
+				trackedRepos:   []string{"repo1", "repo2"},
+				sessions:       NewDefaultSessionModel(),
+				sensitiveTerms: []string{"acme", " "},
+			},
+			want:    nil,
+			wantErr: internal_errors.ErrInvalidConfig,
+		},
+		{
+			name: "invalid config - missing targets",
 			config: &ConfigModel{
 				version:       "v1",
 				dbPath:        "$HOME/.config/tracko.db",
 				trackedAuthor: ConfigAuthorModel{name: "test", emails: []string{"test@example.com"}},
-				targetRepo:    "",
+				targets:       []ConfigTargetModel{},
 				trackedRepos:  []string{"repo1", "repo2"},
 			},
 			want:    nil,
@@ -97,3 +128,151 @@ func Test_ConfigModelBuilder_Build(t *testing.T) {
 		})
 	}
 }
+
+func Test_SessionDTO_ToModel(t *testing.T) {
+	tests := []struct {
+		name    string
+		dto     SessionDTO
+		want    ConfigSessionModel
+		wantErr bool
+	}{
+		{"defaults", SessionDTO{}, NewDefaultSessionModel(), false},
+		{"custom", SessionDTO{Gap: "90m", Lead: "0s"}, ConfigSessionModel{gap: 90 * time.Minute, lead: 0}, false},
+		{"invalid gap", SessionDTO{Gap: "soon"}, ConfigSessionModel{}, true},
+		{"zero gap", SessionDTO{Gap: "0s"}, ConfigSessionModel{}, true},
+		{"negative lead", SessionDTO{Lead: "-5m"}, ConfigSessionModel{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.dto.ToModel()
+			if tt.wantErr {
+				if !errors.Is(err, internal_errors.ErrInvalidConfig) {
+					t.Errorf("ToModel() error = %v, want ErrInvalidConfig", err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("ToModel() = %+v, %v, want %+v", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func Test_ConfigDTO_Projects(t *testing.T) {
+	base := ConfigDTO{
+		Version:       "v1",
+		DBPath:        "/tmp/test.db",
+		TrackedAuthor: AuthorDTO{Name: "test", Emails: []string{"test@example.com"}},
+		Targets:       []TargetDTO{{Name: DefaultTargetName, Path: "test/repo", Author: IdentityDTO{Name: "Personal", Email: "me@personal.dev"}}},
+	}
+
+	tests := []struct {
+		name     string
+		projects []ProjectDTO
+		repoPath string
+		want     ConfigProjectModel
+		wantErr  bool
+	}{
+		{
+			name:     "mapped by alias",
+			projects: []ProjectDTO{{Repo: "backend", Client: "Acme", Project: "Platform", Task: "API"}},
+			repoPath: "/src/backend",
+			want:     ConfigProjectModel{repo: "backend", client: "Acme", project: "Platform", task: "API"},
+		},
+		{
+			name:     "project defaults to alias",
+			projects: []ProjectDTO{{Repo: "/src/backend", Client: "Acme"}},
+			repoPath: "/src/backend",
+			want:     ConfigProjectModel{repo: "/src/backend", client: "Acme", project: "backend"},
+		},
+		{
+			name:     "unmapped repo",
+			projects: []ProjectDTO{{Repo: "frontend", Client: "Acme"}},
+			repoPath: "/src/backend",
+			want:     ConfigProjectModel{repo: "backend", project: "backend"},
+		},
+		{
+			name:     "missing repo",
+			projects: []ProjectDTO{{Client: "Acme", Project: "Platform"}},
+			wantErr:  true,
+		},
+		{
+			name:     "duplicated repo",
+			projects: []ProjectDTO{{Repo: "backend"}, {Repo: "backend", Client: "Acme"}},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dto := base
+			dto.Projects = tt.projects
+
+			cfg, err := dto.ToModel()
+			if tt.wantErr {
+				if !errors.Is(err, internal_errors.ErrInvalidConfig) {
+					t.Errorf("ToModel() error = %v, want ErrInvalidConfig", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ToModel() error = %v", err)
+			}
+			if got := cfg.Project(tt.repoPath); got != tt.want {
+				t.Errorf("Project() = %+v, want %+v", got, tt.want)
+			}
+
+			roundTrip, err := ConfigDTOFromModel(cfg)
+			if err != nil || !reflect.DeepEqual(roundTrip.Projects, projectDTOs(cfg.Projects())) {
+				t.Errorf("Expected projects to round trip, got %+v, %v", roundTrip.Projects, err)
+			}
+		})
+	}
+}
+
+func projectDTOs(projects []ConfigProjectModel) []ProjectDTO {
+	dtos := []ProjectDTO{}
+	for _, project := range projects {
+		dtos = append(dtos, ProjectDTOFromModel(project))
+	}
+	return dtos
+}
+
+func Test_ConfigModel_Warnings(t *testing.T) {
+	tests := []struct {
+		name   string
+		author IdentityDTO
+		want   []string
+	}{
+		{
+			name:   "personal author",
+			author: IdentityDTO{Name: "Personal", Email: "me@personal.dev"},
+			want:   []string{},
+		},
+		{
+			name:   "tracked email",
+			author: IdentityDTO{Name: "Work", Email: "Test@Corp.example.com"},
+			want:   []string{`target "default" exports as Test@Corp.example.com, which is a tracked email`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dto := ConfigDTO{
+				Version:       "v1",
+				DBPath:        "/tmp/test.db",
+				TrackedAuthor: AuthorDTO{Name: "test", Emails: []string{"test@example.com", "test@corp.example.com"}},
+				Targets:       []TargetDTO{{Name: DefaultTargetName, Path: "test/repo", Author: tt.author}},
+			}
+
+			cfg, err := dto.ToModel()
+			if err != nil {
+				t.Fatalf("ToModel() error = %v", err)
+			}
+			if got := cfg.Warnings(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Warnings() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package config_model
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+
+	"github.com/HideyoshiNakazone/tracko/lib/internal_errors"
+)
+
+// Internal Project Model
+// Maps a tracked repo, by path or alias, to the client and project it is
+// billed to in timesheets.
+type ConfigProjectModel struct {
+	repo    string
+	client  string
+	project string
+	task    string
+}
+
+func (p ConfigProjectModel) Repo() string {
+	return p.repo
+}
+
+func (p ConfigProjectModel) Client() string {
+	return p.client
+}
+
+func (p ConfigProjectModel) Project() string {
+	return p.project
+}
+
+func (p ConfigProjectModel) Task() string {
+	return p.task
+}
+
+// Matches reports whether the project mapping applies to the repo at
+// repoPath, either by its path or by its alias.
+func (p ConfigProjectModel) Matches(repoPath string) bool {
+	return p.repo == repoPath || p.repo == filepath.Base(repoPath)
+}
+
+func (p ConfigProjectModel) isValid() bool {
+	return p.repo != "" && p.project != ""
+}
+
+// External Project DTO
+type ProjectDTO struct {
+	Repo    string `mapstructure:"repo"`
+	Client  string `mapstructure:"client"`
+	Project string `mapstructure:"project"`
+	Task    string `mapstructure:"task"`
+}
+
+// ToModel defaults the project name to the repo alias.
+func (p ProjectDTO) ToModel() (ConfigProjectModel, error) {
+	model := ConfigProjectModel{
+		repo:    p.Repo,
+		client:  p.Client,
+		project: p.Project,
+		task:    p.Task,
+	}
+	if model.project == "" {
+		model.project = filepath.Base(p.Repo)
+	}
+	if !model.isValid() {
+		return ConfigProjectModel{}, fmt.Errorf("%w: project mapping without repo", internal_errors.ErrInvalidConfig)
+	}
+	return model, nil
+}
+
+func ProjectDTOFromModel(model ConfigProjectModel) ProjectDTO {
+	return ProjectDTO{
+		Repo:    model.repo,
+		Client:  model.client,
+		Project: model.project,
+		Task:    model.task,
+	}
+}
+
+func projectsFromDTOs(dtos []ProjectDTO) ([]ConfigProjectModel, error) {
+	projects := make([]ConfigProjectModel, 0, len(dtos))
+	for _, dto := range dtos {
+		project, err := dto.ToModel()
+		if err != nil {
+			return nil, err
+		}
+		if slices.ContainsFunc(projects, func(p ConfigProjectModel) bool { return p.repo == project.repo }) {
+			return nil, fmt.Errorf("%w: duplicated project mapping for repo %q", internal_errors.ErrInvalidConfig, project.repo)
+		}
+		projects = append(projects, project)
+	}
+	return projects, nil
+}
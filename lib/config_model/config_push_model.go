@@ -0,0 +1,110 @@
+package config_model
+
+import "slices"
+
+const (
+	PushAuthAuto             = "auto"
+	PushAuthNone             = "none"
+	PushAuthSSHAgent         = "ssh-agent"
+	PushAuthSSHKey           = "ssh-key"
+	PushAuthToken            = "token"
+	PushAuthCredentialHelper = "credential-helper"
+)
+
+var PushAuthMethods = []string{
+	PushAuthAuto,
+	PushAuthNone,
+	PushAuthSSHAgent,
+	PushAuthSSHKey,
+	PushAuthToken,
+	PushAuthCredentialHelper,
+}
+
+var DefaultPushRemote = "origin"
+var DefaultPushTokenEnv = "TRACKO_GIT_TOKEN"
+
+// Internal Push Model
+type ConfigPushModel struct {
+	remote        string
+	auth          string
+	username      string
+	sshKeyPath    string
+	passphraseEnv string
+	tokenEnv      string
+}
+
+func NewDefaultPushModel() ConfigPushModel {
+	return ConfigPushModel{
+		remote:   DefaultPushRemote,
+		auth:     PushAuthAuto,
+		tokenEnv: DefaultPushTokenEnv,
+	}
+}
+
+func (p ConfigPushModel) Remote() string {
+	return p.remote
+}
+
+func (p ConfigPushModel) Auth() string {
+	return p.auth
+}
+
+func (p ConfigPushModel) Username() string {
+	return p.username
+}
+
+func (p ConfigPushModel) SSHKeyPath() string {
+	return p.sshKeyPath
+}
+
+func (p ConfigPushModel) PassphraseEnv() string {
+	return p.passphraseEnv
+}
+
+func (p ConfigPushModel) TokenEnv() string {
+	return p.tokenEnv
+}
+
+func (p ConfigPushModel) isValid() bool {
+	return p.remote != "" && slices.Contains(PushAuthMethods, p.auth)
+}
+
+// External Push DTO
+type PushDTO struct {
+	Remote        string `mapstructure:"remote"`
+	Auth          string `mapstructure:"auth"`
+	Username      string `mapstructure:"username"`
+	SSHKeyPath    string `mapstructure:"ssh_key_path"`
+	PassphraseEnv string `mapstructure:"passphrase_env"`
+	TokenEnv      string `mapstructure:"token_env"`
+}
+
+// ToModel reads a missing remote as origin, a missing auth as auto and a
+// missing token_env as TRACKO_GIT_TOKEN.
+func (p PushDTO) ToModel() ConfigPushModel {
+	model := NewDefaultPushModel()
+	if p.Remote != "" {
+		model.remote = p.Remote
+	}
+	if p.Auth != "" {
+		model.auth = p.Auth
+	}
+	if p.TokenEnv != "" {
+		model.tokenEnv = p.TokenEnv
+	}
+	model.username = p.Username
+	model.sshKeyPath = p.SSHKeyPath
+	model.passphraseEnv = p.PassphraseEnv
+	return model
+}
+
+func PushDTOFromModel(model ConfigPushModel) PushDTO {
+	return PushDTO{
+		Remote:        model.remote,
+		Auth:          model.auth,
+		Username:      model.username,
+		SSHKeyPath:    model.sshKeyPath,
+		PassphraseEnv: model.passphraseEnv,
+		TokenEnv:      model.tokenEnv,
+	}
+}
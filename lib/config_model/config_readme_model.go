@@ -0,0 +1,41 @@
+package config_model
+
+// Internal Readme Model
+// The README.md and badge regenerated in the target after every export, so
+// the mirror explains itself instead of being a wall of empty commits.
+type ConfigReadmeModel struct {
+	enabled  bool
+	template string
+}
+
+// Enabled reports whether the README.md and badge are written at all, they
+// are not by default.
+func (r ConfigReadmeModel) Enabled() bool {
+	return r.enabled
+}
+
+// Template is the path of a text/template file the README.md is rendered
+// with, empty means the built-in one.
+func (r ConfigReadmeModel) Template() string {
+	return r.template
+}
+
+// External Readme DTO
+type ReadmeDTO struct {
+	Enabled  bool   `mapstructure:"enabled"`
+	Template string `mapstructure:"template"`
+}
+
+func (r ReadmeDTO) ToModel() ConfigReadmeModel {
+	return ConfigReadmeModel{
+		enabled:  r.Enabled,
+		template: r.Template,
+	}
+}
+
+func ReadmeDTOFromModel(model ConfigReadmeModel) ReadmeDTO {
+	return ReadmeDTO{
+		Enabled:  model.enabled,
+		Template: model.template,
+	}
+}
@@ -0,0 +1,77 @@
+package config_model
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/HideyoshiNakazone/tracko/lib/internal_errors"
+)
+
+var DefaultSessionGap = 2 * time.Hour
+var DefaultSessionLead = 30 * time.Minute
+
+// Internal Session Model
+// Commits closer than gap to the previous one belong to the same work session,
+// every session starts lead before its first commit to account for the work
+// done before committing.
+type ConfigSessionModel struct {
+	gap  time.Duration
+	lead time.Duration
+}
+
+func NewDefaultSessionModel() ConfigSessionModel {
+	return ConfigSessionModel{
+		gap:  DefaultSessionGap,
+		lead: DefaultSessionLead,
+	}
+}
+
+func (s ConfigSessionModel) Gap() time.Duration {
+	return s.gap
+}
+
+func (s ConfigSessionModel) Lead() time.Duration {
+	return s.lead
+}
+
+func (s ConfigSessionModel) isValid() bool {
+	return s.gap > 0 && s.lead >= 0
+}
+
+// External Session DTO
+// Durations are written as Go durations, like "2h" or "45m".
+type SessionDTO struct {
+	Gap  string `mapstructure:"gap"`
+	Lead string `mapstructure:"lead"`
+}
+
+// ToModel reads a missing gap as 2h and a missing lead as 30m, gap must be
+// positive and lead must not be negative.
+func (s SessionDTO) ToModel() (ConfigSessionModel, error) {
+	model := NewDefaultSessionModel()
+
+	if s.Gap != "" {
+		gap, err := time.ParseDuration(s.Gap)
+		if err != nil || gap <= 0 {
+			return ConfigSessionModel{}, fmt.Errorf("%w: invalid session gap %q", internal_errors.ErrInvalidConfig, s.Gap)
+		}
+		model.gap = gap
+	}
+
+	if s.Lead != "" {
+		lead, err := time.ParseDuration(s.Lead)
+		if err != nil || lead < 0 {
+			return ConfigSessionModel{}, fmt.Errorf("%w: invalid session lead %q", internal_errors.ErrInvalidConfig, s.Lead)
+		}
+		model.lead = lead
+	}
+
+	return model, nil
+}
+
+func SessionDTOFromModel(model ConfigSessionModel) SessionDTO {
+	return SessionDTO{
+		Gap:  model.gap.String(),
+		Lead: model.lead.String(),
+	}
+}
@@ -0,0 +1,83 @@
+package config_model
+
+import "slices"
+
+const (
+	SigningFormatNone    = "none"
+	SigningFormatOpenPGP = "openpgp"
+	SigningFormatSSH     = "ssh"
+)
+
+var SigningFormats = []string{
+	SigningFormatNone,
+	SigningFormatOpenPGP,
+	SigningFormatSSH,
+}
+
+// Internal Signing Model
+type ConfigSigningModel struct {
+	format        string
+	keyPath       string
+	passphraseEnv string
+}
+
+func NewDefaultSigningModel() ConfigSigningModel {
+	return ConfigSigningModel{
+		format: SigningFormatNone,
+	}
+}
+
+// Format is how mirrored commits are signed, none leaves them unsigned.
+func (s ConfigSigningModel) Format() string {
+	return s.format
+}
+
+// KeyPath is the armored OpenPGP private key or the SSH private key used to
+// sign mirrored commits.
+func (s ConfigSigningModel) KeyPath() string {
+	return s.keyPath
+}
+
+// PassphraseEnv is the environment variable holding the passphrase of the
+// key, empty when the key is not encrypted.
+func (s ConfigSigningModel) PassphraseEnv() string {
+	return s.passphraseEnv
+}
+
+func (s ConfigSigningModel) Enabled() bool {
+	return s.format != SigningFormatNone
+}
+
+func (s ConfigSigningModel) isValid() bool {
+	if !slices.Contains(SigningFormats, s.format) {
+		return false
+	}
+	return !s.Enabled() || s.keyPath != ""
+}
+
+// External Signing DTO
+type SigningDTO struct {
+	Format        string `mapstructure:"format"`
+	KeyPath       string `mapstructure:"key_path"`
+	PassphraseEnv string `mapstructure:"passphrase_env"`
+}
+
+// ToModel reads a missing format as none, mirrored commits of a target
+// without signing stay unsigned.
+func (s SigningDTO) ToModel() ConfigSigningModel {
+	model := NewDefaultSigningModel()
+	if s.Format != "" {
+		model.format = s.Format
+	}
+	model.keyPath = s.KeyPath
+	model.passphraseEnv = s.PassphraseEnv
+	return model
+}
+
+func SigningDTOFromModel(model ConfigSigningModel) SigningDTO {
+	return SigningDTO{
+		Format:        model.format,
+		KeyPath:       model.keyPath,
+		PassphraseEnv: model.passphraseEnv,
+	}
+}
@@ -0,0 +1,165 @@
+package config_model
+
+import (
+	"time"
+
+	"github.com/HideyoshiNakazone/tracko/lib/commit_message"
+	"github.com/HideyoshiNakazone/tracko/lib/internal_errors"
+)
+
+// ConfigTargetBuilder is a builder for ConfigTargetModel
+type ConfigTargetBuilder struct {
+	target *ConfigTargetModel
+}
+
+func NewTargetBuilder(name string, path string) *ConfigTargetBuilder {
+	return &ConfigTargetBuilder{
+		target: &ConfigTargetModel{
+			name:            name,
+			path:            path,
+			repos:           []string{},
+			messageTemplate: commit_message.DefaultTemplate,
+			strategy:        StrategyOneToOne,
+			content:         ContentEmpty,
+			signing:         NewDefaultSigningModel(),
+			push:            NewDefaultPushModel(),
+			layout:          NewDefaultLayoutModel(),
+		},
+	}
+}
+
+// WithURL clones the target from url when it is missing, a target without
+// a path is cloned into the target cache directory.
+func (t *ConfigTargetBuilder) WithURL(url string) *ConfigTargetBuilder {
+	t.target.url = url
+	return t
+}
+
+// WithCreate initialises an empty target repository when it is missing.
+func (t *ConfigTargetBuilder) WithCreate(create bool) *ConfigTargetBuilder {
+	t.target.create = create
+	return t
+}
+
+func (t *ConfigTargetBuilder) WithBranch(branch string) *ConfigTargetBuilder {
+	t.target.branch = branch
+	return t
+}
+
+// WithOrphanBranch creates the target branch without history when it is
+// missing, instead of from the current head.
+func (t *ConfigTargetBuilder) WithOrphanBranch(orphan bool) *ConfigTargetBuilder {
+	t.target.orphan = orphan
+	return t
+}
+
+func (t *ConfigTargetBuilder) WithRepos(repos []string) *ConfigTargetBuilder {
+	t.target.repos = repos
+	return t
+}
+
+func (t *ConfigTargetBuilder) WithDateRange(since time.Time, until time.Time) *ConfigTargetBuilder {
+	t.target.since = since
+	t.target.until = until
+	return t
+}
+
+func (t *ConfigTargetBuilder) WithMessageTemplate(messageTemplate string) *ConfigTargetBuilder {
+	t.target.messageTemplate = messageTemplate
+	return t
+}
+
+// WithStrategy sets the export strategy, maxPerDay is only used by the
+// capped strategy.
+func (t *ConfigTargetBuilder) WithStrategy(strategy string, maxPerDay int) *ConfigTargetBuilder {
+	t.target.strategy = strategy
+	t.target.maxPerDay = maxPerDay
+	return t
+}
+
+// WithTimezone normalises mirrored commits to a home timezone, given by its
+// IANA name. An empty name keeps the offset of every source commit.
+func (t *ConfigTargetBuilder) WithTimezone(timezone string) *ConfigTargetBuilder {
+	t.target.timezone = timezone
+	return t
+}
+
+func (t *ConfigTargetBuilder) WithContent(content string) *ConfigTargetBuilder {
+	t.target.content = content
+	return t
+}
+
+// WithRepoNames names source repos by their alias in content files instead
+// of an opaque label.
+func (t *ConfigTargetBuilder) WithRepoNames(repoNames bool) *ConfigTargetBuilder {
+	t.target.repoNames = repoNames
+	return t
+}
+
+// WithAuthor authors mirrored commits as name and email, every target
+// requires one.
+func (t *ConfigTargetBuilder) WithAuthor(name string, email string) *ConfigTargetBuilder {
+	t.target.author = ConfigIdentityModel{name: name, email: email}
+	return t
+}
+
+// WithSigning signs mirrored commits with the key at keyPath, an armored
+// OpenPGP private key or an SSH private key depending on format.
+func (t *ConfigTargetBuilder) WithSigning(format string, keyPath string, passphraseEnv string) *ConfigTargetBuilder {
+	t.target.signing.format = format
+	t.target.signing.keyPath = keyPath
+	t.target.signing.passphraseEnv = passphraseEnv
+	return t
+}
+
+func (t *ConfigTargetBuilder) WithPushRemote(remote string) *ConfigTargetBuilder {
+	t.target.push.remote = remote
+	return t
+}
+
+func (t *ConfigTargetBuilder) WithPushAuth(auth string) *ConfigTargetBuilder {
+	t.target.push.auth = auth
+	return t
+}
+
+func (t *ConfigTargetBuilder) WithPushSSHKey(sshKeyPath string, passphraseEnv string) *ConfigTargetBuilder {
+	t.target.push.sshKeyPath = sshKeyPath
+	t.target.push.passphraseEnv = passphraseEnv
+	return t
+}
+
+func (t *ConfigTargetBuilder) WithPushToken(username string, tokenEnv string) *ConfigTargetBuilder {
+	t.target.push.username = username
+	t.target.push.tokenEnv = tokenEnv
+	return t
+}
+
+// WithLayout partitions the export by split, into branches or repositories
+// depending on into.
+func (t *ConfigTargetBuilder) WithLayout(split string, into string) *ConfigTargetBuilder {
+	t.target.layout.split = split
+	t.target.layout.into = into
+	return t
+}
+
+// WithRepoGroup adds a partition of the repo-group split, holding the
+// commits of repos.
+func (t *ConfigTargetBuilder) WithRepoGroup(name string, repos []string) *ConfigTargetBuilder {
+	t.target.layout.groups = append(t.target.layout.groups, ConfigRepoGroupModel{name: name, repos: repos})
+	return t
+}
+
+// WithReadme writes a README.md and badge to the target after every export,
+// rendered with the template file at templatePath or the built-in one when
+// it is empty.
+func (t *ConfigTargetBuilder) WithReadme(templatePath string) *ConfigTargetBuilder {
+	t.target.readme = ConfigReadmeModel{enabled: true, template: templatePath}
+	return t
+}
+
+func (t *ConfigTargetBuilder) Build() (*ConfigTargetModel, error) {
+	if !t.target.isValid() {
+		return nil, internal_errors.ErrInvalidConfig
+	}
+	return t.target, nil
+}
@@ -0,0 +1,471 @@
+package config_model
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	// Bundled so that home timezones resolve on systems without a zoneinfo database
+	_ "time/tzdata"
+
+	"github.com/HideyoshiNakazone/tracko/lib/commit_message"
+	"github.com/HideyoshiNakazone/tracko/lib/internal_errors"
+	"github.com/HideyoshiNakazone/tracko/lib/readme_template"
+)
+
+var DefaultTargetName = "default"
+
+// DateFormat is the layout of the target date range fields
+const DateFormat = "2006-01-02"
+
+// Export strategies, they control how many target commits are written for the
+// source commits of a single day.
+const (
+	StrategyOneToOne = "one-to-one"
+	StrategyDaily    = "daily"
+	StrategyCapped   = "capped"
+)
+
+var Strategies = []string{StrategyOneToOne, StrategyDaily, StrategyCapped}
+
+// Content strategies, they control what mirrored commits change in the target
+// repository.
+const (
+	ContentEmpty       = "empty"
+	ContentActivityLog = "activity-log"
+	ContentJSONLedger  = "json-ledger"
+	ContentCSVLedger   = "csv-ledger"
+)
+
+var Contents = []string{ContentEmpty, ContentActivityLog, ContentJSONLedger, ContentCSVLedger}
+
+// Internal Target Model
+type ConfigTargetModel struct {
+	name            string
+	path            string
+	url             string
+	create          bool
+	branch          string
+	orphan          bool
+	repos           []string
+	since           time.Time
+	until           time.Time
+	messageTemplate string
+	strategy        string
+	maxPerDay       int
+	timezone        string
+	content         string
+	repoNames       bool
+	author          ConfigIdentityModel
+	signing         ConfigSigningModel
+	push            ConfigPushModel
+	layout          ConfigLayoutModel
+	readme          ConfigReadmeModel
+}
+
+func (t ConfigTargetModel) Name() string {
+	return t.name
+}
+
+func (t ConfigTargetModel) Path() string {
+	return t.path
+}
+
+// URL is where the target repository is cloned from when it is missing
+// locally, empty means it is never cloned.
+func (t ConfigTargetModel) URL() string {
+	return t.url
+}
+
+// Create reports whether an empty target repository is initialised at the
+// target path when it is missing.
+func (t ConfigTargetModel) Create() bool {
+	return t.create
+}
+
+// LocalPath is where the target repository lives on disk, the path with
+// environment variables expanded. Targets with only a URL are cloned into
+// the target cache directory, under their name.
+func (t ConfigTargetModel) LocalPath() string {
+	if t.path == "" {
+		return filepath.Join(TargetCacheDir(), t.name)
+	}
+	return os.ExpandEnv(t.path)
+}
+
+// Branch is the branch mirrored commits are written to, empty means the
+// branch currently checked out in the target repository.
+func (t ConfigTargetModel) Branch() string {
+	return t.branch
+}
+
+// Orphan reports whether a missing branch is created without history,
+// instead of from the current head of the target repository.
+func (t ConfigTargetModel) Orphan() bool {
+	return t.orphan
+}
+
+func (t ConfigTargetModel) Repos() []string {
+	return t.repos
+}
+
+func (t ConfigTargetModel) Since() time.Time {
+	return t.since
+}
+
+func (t ConfigTargetModel) Until() time.Time {
+	return t.until
+}
+
+func (t ConfigTargetModel) MessageTemplate() string {
+	return t.messageTemplate
+}
+
+func (t ConfigTargetModel) Strategy() string {
+	return t.strategy
+}
+
+func (t ConfigTargetModel) MaxPerDay() int {
+	return t.maxPerDay
+}
+
+// DailyCap is the maximum number of target commits written per day, zero
+// means every source commit gets its own target commit.
+func (t ConfigTargetModel) DailyCap() int {
+	switch t.strategy {
+	case StrategyDaily:
+		return 1
+	case StrategyCapped:
+		return t.maxPerDay
+	default:
+		return 0
+	}
+}
+
+// Timezone is the IANA name of the home timezone mirrored commits are
+// normalised to, empty keeps the UTC offset of every source commit.
+func (t ConfigTargetModel) Timezone() string {
+	return t.timezone
+}
+
+// InTimezone returns when as it is written to this target, converted to the
+// home timezone if one is configured and untouched otherwise.
+func (t ConfigTargetModel) InTimezone(when time.Time) time.Time {
+	if t.timezone == "" {
+		return when
+	}
+	location, err := time.LoadLocation(t.timezone)
+	if err != nil {
+		return when
+	}
+	return when.In(location)
+}
+
+// Content is the content strategy of mirrored commits, empty commits by
+// default.
+func (t ConfigTargetModel) Content() string {
+	return t.content
+}
+
+// RepoNames reports whether content files name source repos by their alias,
+// by default they get an opaque label so that the private repos are never
+// named in the target.
+func (t ConfigTargetModel) RepoNames() bool {
+	return t.repoNames
+}
+
+// Author is the identity mirrored commits are authored and committed as,
+// every target requires one so that the tracked emails never leak.
+func (t ConfigTargetModel) Author() ConfigIdentityModel {
+	return t.author
+}
+
+func (t ConfigTargetModel) Signing() ConfigSigningModel {
+	return t.signing
+}
+
+func (t ConfigTargetModel) Push() ConfigPushModel {
+	return t.push
+}
+
+func (t ConfigTargetModel) Layout() ConfigLayoutModel {
+	return t.layout
+}
+
+// Readme is the README.md and badge written to the target after an export.
+func (t ConfigTargetModel) Readme() ConfigReadmeModel {
+	return t.readme
+}
+
+// YearPartition returns the target the commits of year are exported as, for
+// targets split by year. Its date range is the year, narrowed to the date
+// range of this target.
+func (t ConfigTargetModel) YearPartition(year int) ConfigTargetModel {
+	partition := t.partition(strconv.Itoa(year))
+
+	partition.since = time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	if t.since.After(partition.since) {
+		partition.since = t.since
+	}
+	partition.until = time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC)
+	if !t.until.IsZero() && t.until.Before(partition.until) {
+		partition.until = t.until
+	}
+	return partition
+}
+
+// GroupPartitions returns the targets every repo group is exported as, for
+// targets split by repo group. The repos of a group replace the repos filter
+// of this target.
+func (t ConfigTargetModel) GroupPartitions() []ConfigTargetModel {
+	partitions := []ConfigTargetModel{}
+	for _, group := range t.layout.groups {
+		partition := t.partition(group.name)
+		partition.repos = group.repos
+		partitions = append(partitions, partition)
+	}
+	return partitions
+}
+
+// partition returns a copy of this target named after key, written to a
+// branch or a repository of its own depending on the layout. The branch is
+// suffixed with key and starts without history, so that partitions never
+// share commits. In a repository of its own the path and the url are
+// suffixed instead, the 2024 partition of a target cloned from
+// contributions.git is cloned from contributions-2024.git.
+func (t ConfigTargetModel) partition(key string) ConfigTargetModel {
+	partition := t
+	partition.name = t.name + "-" + key
+	partition.layout = NewDefaultLayoutModel()
+
+	switch t.layout.into {
+	case LayoutIntoBranch:
+		branch := t.branch
+		if branch == "" {
+			branch = DefaultPartitionBranch
+		}
+		partition.branch = branch + "-" + key
+		partition.orphan = true
+	case LayoutIntoRepo:
+		if t.path != "" {
+			partition.path = strings.TrimRight(t.path, `/\`) + "-" + key
+		}
+		if t.url != "" {
+			url := strings.TrimRight(t.url, "/")
+			if base, found := strings.CutSuffix(url, ".git"); found {
+				partition.url = base + "-" + key + ".git"
+			} else {
+				partition.url = url + "-" + key
+			}
+		}
+	}
+	return partition
+}
+
+// TargetCacheDir is the directory targets configured with only a URL are
+// cloned into.
+func TargetCacheDir() string {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		cacheDir = os.TempDir()
+	}
+	return filepath.Join(cacheDir, "tracko", "targets")
+}
+
+// IsRepoURL reports whether repo is the URL of a remote repository rather
+// than a local path, either a URL with a scheme or an scp-like SSH address.
+func IsRepoURL(repo string) bool {
+	if strings.Contains(repo, "://") {
+		return true
+	}
+	host, _, found := strings.Cut(repo, ":")
+	return found && strings.Contains(host, "@") && !strings.ContainsAny(host, `/\`)
+}
+
+// IncludesRepo reports whether commits of the tracked repo at repoPath are
+// exported to this target. Repos can be filtered by path or by alias (the
+// base name of the path), an empty filter includes every tracked repo.
+func (t ConfigTargetModel) IncludesRepo(repoPath string) bool {
+	if len(t.repos) == 0 {
+		return true
+	}
+	return slices.Contains(t.repos, repoPath) || slices.Contains(t.repos, filepath.Base(repoPath))
+}
+
+// IncludesDate reports whether a commit made at when falls inside the date
+// range of this target, both ends are inclusive.
+func (t ConfigTargetModel) IncludesDate(when time.Time) bool {
+	day := when.Format(DateFormat)
+	if !t.since.IsZero() && day < t.since.Format(DateFormat) {
+		return false
+	}
+	if !t.until.IsZero() && day > t.until.Format(DateFormat) {
+		return false
+	}
+	return true
+}
+
+func (t ConfigTargetModel) isValid() bool {
+	return t.validate() == nil
+}
+
+// validate checks every field of the target, the errors name the field at
+// fault. An enabled readme template is parsed and rendered here, so that a
+// broken one fails on load rather than halfway through an export.
+func (t ConfigTargetModel) validate() error {
+	if t.name == "" {
+		return fmt.Errorf("%w: target without a name", internal_errors.ErrInvalidConfig)
+	}
+	if t.path == "" && t.url == "" {
+		return fmt.Errorf("%w: target %q requires a path or a url", internal_errors.ErrInvalidConfig, t.name)
+	}
+	if !t.since.IsZero() && !t.until.IsZero() && t.until.Before(t.since) {
+		return fmt.Errorf("%w: target %q: until is before since", internal_errors.ErrInvalidConfig, t.name)
+	}
+	if _, err := commit_message.Parse(t.messageTemplate); err != nil {
+		return fmt.Errorf("%w: target %q: invalid message_template: %v", internal_errors.ErrInvalidConfig, t.name, err)
+	}
+	if !slices.Contains(Strategies, t.strategy) {
+		return fmt.Errorf("%w: target %q: unknown strategy %q, must be one of %v", internal_errors.ErrInvalidConfig, t.name, t.strategy, Strategies)
+	}
+	if t.strategy == StrategyCapped && t.maxPerDay < 1 {
+		return fmt.Errorf("%w: target %q: max_per_day must be at least 1 with the %s strategy", internal_errors.ErrInvalidConfig, t.name, StrategyCapped)
+	}
+	if _, err := time.LoadLocation(t.timezone); err != nil {
+		return fmt.Errorf("%w: target %q: invalid timezone: %v", internal_errors.ErrInvalidConfig, t.name, err)
+	}
+	if !slices.Contains(Contents, t.content) {
+		return fmt.Errorf("%w: target %q: unknown content %q, must be one of %v", internal_errors.ErrInvalidConfig, t.name, t.content, Contents)
+	}
+	if !t.author.isValid() {
+		return fmt.Errorf("%w: target %q: author requires a name and a valid email, the identity mirrored commits are authored as", internal_errors.ErrInvalidConfig, t.name)
+	}
+	if !t.signing.isValid() {
+		return fmt.Errorf("%w: target %q: signing.format must be one of %v and requires a key_path", internal_errors.ErrInvalidConfig, t.name, SigningFormats)
+	}
+	if !t.push.isValid() {
+		return fmt.Errorf("%w: target %q: push.auth must be one of %v and requires a remote", internal_errors.ErrInvalidConfig, t.name, PushAuthMethods)
+	}
+	if !t.layout.isValid() {
+		return fmt.Errorf("%w: target %q: the %s layout split requires groups with a unique name and repos, and only it takes groups", internal_errors.ErrInvalidConfig, t.name, LayoutSplitRepoGroup)
+	}
+	if t.readme.enabled {
+		if _, err := readme_template.Parse(t.readme.template); err != nil {
+			return fmt.Errorf("%w: target %q: invalid readme.template: %v", internal_errors.ErrInvalidConfig, t.name, err)
+		}
+	}
+	return nil
+}
+
+// External Target DTO
+type TargetDTO struct {
+	Name            string      `mapstructure:"name" validate:"required"`
+	Path            string      `mapstructure:"path"`
+	URL             string      `mapstructure:"url"`
+	Create          bool        `mapstructure:"create"`
+	Branch          string      `mapstructure:"branch"`
+	Orphan          bool        `mapstructure:"orphan"`
+	Repos           []string    `mapstructure:"repos"`
+	Since           string      `mapstructure:"since"`
+	Until           string      `mapstructure:"until"`
+	MessageTemplate string      `mapstructure:"message_template"`
+	Strategy        string      `mapstructure:"strategy"`
+	MaxPerDay       int         `mapstructure:"max_per_day"`
+	Timezone        string      `mapstructure:"timezone"`
+	Content         string      `mapstructure:"content"`
+	RepoNames       bool        `mapstructure:"repo_names"`
+	Author          IdentityDTO `mapstructure:"author"`
+	Signing         SigningDTO  `mapstructure:"signing"`
+	Push            PushDTO     `mapstructure:"push"`
+	Layout          LayoutDTO   `mapstructure:"layout"`
+	Readme          ReadmeDTO   `mapstructure:"readme"`
+}
+
+func (t TargetDTO) ToModel() (*ConfigTargetModel, error) {
+	model := &ConfigTargetModel{
+		name:            t.Name,
+		path:            t.Path,
+		url:             t.URL,
+		create:          t.Create,
+		branch:          t.Branch,
+		orphan:          t.Orphan,
+		repos:           t.Repos,
+		messageTemplate: t.MessageTemplate,
+		strategy:        t.Strategy,
+		maxPerDay:       t.MaxPerDay,
+		timezone:        t.Timezone,
+		content:         t.Content,
+		repoNames:       t.RepoNames,
+		signing:         t.Signing.ToModel(),
+		push:            t.Push.ToModel(),
+		readme:          t.Readme.ToModel(),
+	}
+	if model.messageTemplate == "" {
+		model.messageTemplate = commit_message.DefaultTemplate
+	}
+	if model.strategy == "" {
+		model.strategy = StrategyOneToOne
+	}
+	if model.content == "" {
+		model.content = ContentEmpty
+	}
+	if model.repos == nil {
+		model.repos = []string{}
+	}
+
+	// An invalid author is left empty, validate reports it with the rest
+	model.author, _ = t.Author.ToModel()
+
+	var err error
+	if model.layout, err = t.Layout.ToModel(); err != nil {
+		return nil, fmt.Errorf("target %q: %w", t.Name, err)
+	}
+	if t.Since != "" {
+		if model.since, err = time.Parse(DateFormat, t.Since); err != nil {
+			return nil, fmt.Errorf("%w: target %q: invalid since: %v", internal_errors.ErrInvalidConfig, t.Name, err)
+		}
+	}
+	if t.Until != "" {
+		if model.until, err = time.Parse(DateFormat, t.Until); err != nil {
+			return nil, fmt.Errorf("%w: target %q: invalid until: %v", internal_errors.ErrInvalidConfig, t.Name, err)
+		}
+	}
+
+	if err := model.validate(); err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+func TargetDTOFromModel(model ConfigTargetModel) TargetDTO {
+	dto := TargetDTO{
+		Name:            model.name,
+		Path:            model.path,
+		URL:             model.url,
+		Create:          model.create,
+		Branch:          model.branch,
+		Orphan:          model.orphan,
+		Repos:           model.repos,
+		MessageTemplate: model.messageTemplate,
+		Strategy:        model.strategy,
+		MaxPerDay:       model.maxPerDay,
+		Timezone:        model.timezone,
+		Content:         model.content,
+		RepoNames:       model.repoNames,
+		Author:          IdentityDTOFromModel(model.author),
+		Signing:         SigningDTOFromModel(model.signing),
+		Push:            PushDTOFromModel(model.push),
+		Layout:          LayoutDTOFromModel(model.layout),
+		Readme:          ReadmeDTOFromModel(model.readme),
+	}
+	if !model.since.IsZero() {
+		dto.Since = model.since.Format(DateFormat)
+	}
+	if !model.until.IsZero() {
+		dto.Until = model.until.Format(DateFormat)
+	}
+	return dto
+}
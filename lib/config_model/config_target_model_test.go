@@ -0,0 +1,330 @@
+package config_model
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/HideyoshiNakazone/tracko/lib/internal_errors"
+)
+
+func Test_ConfigTargetModel_IncludesRepo(t *testing.T) {
+	tests := []struct {
+		name     string
+		repos    []string
+		repoPath string
+		expected bool
+	}{
+		{"Empty filter", []string{}, "/home/user/backend", true},
+		{"Match by path", []string{"/home/user/backend"}, "/home/user/backend", true},
+		{"Match by alias", []string{"backend"}, "/home/user/backend", true},
+		{"No match", []string{"frontend"}, "/home/user/backend", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := ConfigTargetModel{repos: tt.repos}
+			if got := target.IncludesRepo(tt.repoPath); got != tt.expected {
+				t.Errorf("IncludesRepo() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func Test_ConfigTargetModel_IncludesDate(t *testing.T) {
+	since := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		since    time.Time
+		until    time.Time
+		when     time.Time
+		expected bool
+	}{
+		{"Unbounded", time.Time{}, time.Time{}, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), true},
+		{"Before since", since, until, time.Date(2024, 12, 31, 23, 59, 0, 0, time.UTC), false},
+		{"On since", since, until, since, true},
+		{"Last minute of until", since, until, time.Date(2025, 1, 31, 23, 59, 0, 0, time.UTC), true},
+		{"After until", since, until, time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), false},
+		{"Only since", since, time.Time{}, time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := ConfigTargetModel{since: tt.since, until: tt.until}
+			if got := target.IncludesDate(tt.when); got != tt.expected {
+				t.Errorf("IncludesDate() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func Test_ConfigTargetModel_DailyCap(t *testing.T) {
+	tests := []struct {
+		name      string
+		strategy  string
+		maxPerDay int
+		expected  int
+	}{
+		{"One to one", StrategyOneToOne, 5, 0},
+		{"Daily", StrategyDaily, 5, 1},
+		{"Capped", StrategyCapped, 5, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := ConfigTargetModel{strategy: tt.strategy, maxPerDay: tt.maxPerDay}
+			if got := target.DailyCap(); got != tt.expected {
+				t.Errorf("DailyCap() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func Test_ConfigTargetModel_InTimezone(t *testing.T) {
+	when := time.Date(2025, 1, 1, 1, 30, 0, 0, time.FixedZone("", 9*60*60))
+
+	tests := []struct {
+		name     string
+		timezone string
+		expected string
+	}{
+		{"Keep original offset", "", "2025-01-01T01:30:00+09:00"},
+		{"UTC", "UTC", "2024-12-31T16:30:00Z"},
+		{"Home timezone", "America/Sao_Paulo", "2024-12-31T13:30:00-03:00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := ConfigTargetModel{timezone: tt.timezone}
+			got := target.InTimezone(when)
+			if got.Format(time.RFC3339) != tt.expected {
+				t.Errorf("InTimezone() = %v, want %v", got.Format(time.RFC3339), tt.expected)
+			}
+			if !got.Equal(when) {
+				t.Errorf("Expected InTimezone() to keep the instant, got %v", got)
+			}
+		})
+	}
+}
+
+func Test_ConfigTargetModel_LocalPath(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", "/tmp/cache")
+	t.Setenv("TRACKO_TEST_HOME", "/home/user")
+
+	tests := []struct {
+		name     string
+		target   ConfigTargetModel
+		expected string
+	}{
+		{"Path", ConfigTargetModel{name: "github", path: "/tmp/mirror"}, "/tmp/mirror"},
+		{"Expanded path", ConfigTargetModel{name: "github", path: "$TRACKO_TEST_HOME/mirror"}, "/home/user/mirror"},
+		{"Path and URL", ConfigTargetModel{name: "github", path: "/tmp/mirror", url: "https://github.com/user/mirror.git"}, "/tmp/mirror"},
+		{"Only URL", ConfigTargetModel{name: "github", url: "https://github.com/user/mirror.git"}, "/tmp/cache/tracko/targets/github"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.target.LocalPath(); got != tt.expected {
+				t.Errorf("LocalPath() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func Test_ConfigTargetModel_YearPartition(t *testing.T) {
+	since := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		target   ConfigTargetModel
+		year     int
+		expected ConfigTargetModel
+	}{
+		{
+			name:     "Into default branch",
+			target:   ConfigTargetModel{name: "github", path: "/tmp/mirror", layout: ConfigLayoutModel{split: LayoutSplitYear, into: LayoutIntoBranch}},
+			year:     2025,
+			expected: ConfigTargetModel{name: "github-2025", path: "/tmp/mirror", branch: "tracko-2025", orphan: true, since: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), until: time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC), layout: NewDefaultLayoutModel()},
+		},
+		{
+			name:     "Into branch narrowed to the target range",
+			target:   ConfigTargetModel{name: "github", path: "/tmp/mirror", branch: "main", since: since, layout: ConfigLayoutModel{split: LayoutSplitYear, into: LayoutIntoBranch}},
+			year:     2024,
+			expected: ConfigTargetModel{name: "github-2024", path: "/tmp/mirror", branch: "main-2024", orphan: true, since: since, until: time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC), layout: NewDefaultLayoutModel()},
+		},
+		{
+			name:     "Into repo",
+			target:   ConfigTargetModel{name: "github", path: "/tmp/mirror/", url: "git@github.com:user/mirror.git", branch: "main", layout: ConfigLayoutModel{split: LayoutSplitYear, into: LayoutIntoRepo}},
+			year:     2025,
+			expected: ConfigTargetModel{name: "github-2025", path: "/tmp/mirror-2025", url: "git@github.com:user/mirror-2025.git", branch: "main", since: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), until: time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC), layout: NewDefaultLayoutModel()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.target.YearPartition(tt.year); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("YearPartition() = %+v, want %+v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func Test_ConfigTargetModel_GroupPartitions(t *testing.T) {
+	target := ConfigTargetModel{
+		name:  "github",
+		url:   "https://github.com/user/mirror",
+		repos: []string{"backend", "frontend", "dotfiles"},
+		layout: ConfigLayoutModel{
+			split: LayoutSplitRepoGroup,
+			into:  LayoutIntoRepo,
+			groups: []ConfigRepoGroupModel{
+				{name: "work", repos: []string{"backend", "frontend"}},
+				{name: "personal", repos: []string{"dotfiles"}},
+			},
+		},
+	}
+
+	expected := []ConfigTargetModel{
+		{name: "github-work", url: "https://github.com/user/mirror-work", repos: []string{"backend", "frontend"}, layout: NewDefaultLayoutModel()},
+		{name: "github-personal", url: "https://github.com/user/mirror-personal", repos: []string{"dotfiles"}, layout: NewDefaultLayoutModel()},
+	}
+	if got := target.GroupPartitions(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("GroupPartitions() = %+v, want %+v", got, expected)
+	}
+}
+
+func Test_IsRepoURL(t *testing.T) {
+	tests := []struct {
+		repo     string
+		expected bool
+	}{
+		{"https://github.com/user/mirror.git", true},
+		{"ssh://git@github.com/user/mirror.git", true},
+		{"file:///srv/git/mirror.git", true},
+		{"git@github.com:user/mirror.git", true},
+		{"/home/user/mirror", false},
+		{"user/mirror", false},
+		{"C:\\Users\\user\\mirror", false},
+		{"./dir@2:mirror/x", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsRepoURL(tt.repo); got != tt.expected {
+			t.Errorf("IsRepoURL(%q) = %v, want %v", tt.repo, got, tt.expected)
+		}
+	}
+}
+
+func Test_TargetDTO_ToModel(t *testing.T) {
+	personal := IdentityDTO{Name: "Personal", Email: "me@personal.dev"}
+
+	templates := t.TempDir()
+	t.Setenv("TRACKO_TEST_TEMPLATES", templates)
+	if err := os.WriteFile(filepath.Join(templates, "README.md.tmpl"), []byte("{{.Target}}: {{.Commits}} commits\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write template: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(templates, "broken.tmpl"), []byte("{{.Unknown}}\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write template: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		dto     TargetDTO
+		wantErr bool
+	}{
+		{"Minimal target", TargetDTO{Name: "github", Path: "/tmp/mirror", Author: personal}, false},
+		{"Full target", TargetDTO{Name: "github", Path: "/tmp/mirror", Author: personal, Branch: "main", Repos: []string{"backend"}, Since: "2024-01-01", Until: "2024-12-31", MessageTemplate: "{{.Type}}"}, false},
+		{"Missing name", TargetDTO{Path: "/tmp/mirror", Author: personal}, true},
+		{"Missing path", TargetDTO{Name: "github", Author: personal}, true},
+		{"URL without path", TargetDTO{Name: "github", URL: "git@github.com:user/mirror.git", Author: personal}, false},
+		{"Created orphan branch", TargetDTO{Name: "github", Path: "/tmp/mirror", Author: personal, Create: true, Branch: "mirror", Orphan: true}, false},
+		{"Invalid date", TargetDTO{Name: "github", Path: "/tmp/mirror", Author: personal, Since: "01/01/2024"}, true},
+		{"Inverted date range", TargetDTO{Name: "github", Path: "/tmp/mirror", Author: personal, Since: "2025-01-01", Until: "2024-01-01"}, true},
+		{"Invalid template", TargetDTO{Name: "github", Path: "/tmp/mirror", Author: personal, MessageTemplate: "{{.Unknown}}"}, true},
+		{"Daily strategy", TargetDTO{Name: "github", Path: "/tmp/mirror", Author: personal, Strategy: StrategyDaily}, false},
+		{"Capped strategy", TargetDTO{Name: "github", Path: "/tmp/mirror", Author: personal, Strategy: StrategyCapped, MaxPerDay: 3}, false},
+		{"Unknown strategy", TargetDTO{Name: "github", Path: "/tmp/mirror", Author: personal, Strategy: "weekly"}, true},
+		{"Capped strategy without max", TargetDTO{Name: "github", Path: "/tmp/mirror", Author: personal, Strategy: StrategyCapped}, true},
+		{"Home timezone", TargetDTO{Name: "github", Path: "/tmp/mirror", Author: personal, Timezone: "America/Sao_Paulo"}, false},
+		{"Unknown timezone", TargetDTO{Name: "github", Path: "/tmp/mirror", Author: personal, Timezone: "Mars/Olympus_Mons"}, true},
+		{"Ledger content", TargetDTO{Name: "github", Path: "/tmp/mirror", Author: personal, Content: ContentJSONLedger}, false},
+		{"Unknown content", TargetDTO{Name: "github", Path: "/tmp/mirror", Author: personal, Content: "readme"}, true},
+		{"OpenPGP signing", TargetDTO{Name: "github", Path: "/tmp/mirror", Author: personal, Signing: SigningDTO{Format: SigningFormatOpenPGP, KeyPath: "/tmp/key.asc", PassphraseEnv: "TRACKO_GPG_PASSPHRASE"}}, false},
+		{"SSH signing", TargetDTO{Name: "github", Path: "/tmp/mirror", Author: personal, Signing: SigningDTO{Format: SigningFormatSSH, KeyPath: "/tmp/id_ed25519"}}, false},
+		{"Signing without key", TargetDTO{Name: "github", Path: "/tmp/mirror", Author: personal, Signing: SigningDTO{Format: SigningFormatSSH}}, true},
+		{"Unknown signing format", TargetDTO{Name: "github", Path: "/tmp/mirror", Author: personal, Signing: SigningDTO{Format: "x509", KeyPath: "/tmp/cert.pem"}}, true},
+		{"Invalid push auth", TargetDTO{Name: "github", Path: "/tmp/mirror", Author: personal, Push: PushDTO{Auth: "password"}}, true},
+		{"Missing export author", TargetDTO{Name: "github", Path: "/tmp/mirror"}, true},
+		{"Export author without name", TargetDTO{Name: "github", Path: "/tmp/mirror", Author: IdentityDTO{Email: "me@personal.dev"}}, true},
+		{"Export author without email", TargetDTO{Name: "github", Path: "/tmp/mirror", Author: IdentityDTO{Name: "Personal"}}, true},
+		{"Export author with invalid email", TargetDTO{Name: "github", Path: "/tmp/mirror", Author: IdentityDTO{Name: "Personal", Email: "me.personal.dev"}}, true},
+		{"Export author with display name", TargetDTO{Name: "github", Path: "/tmp/mirror", Author: IdentityDTO{Name: "Personal", Email: "Personal <me@personal.dev>"}}, true},
+		{"Year layout", TargetDTO{Name: "github", Path: "/tmp/mirror", Author: personal, Layout: LayoutDTO{Split: LayoutSplitYear}}, false},
+		{"Repo group layout", TargetDTO{Name: "github", Path: "/tmp/mirror", Author: personal, Layout: LayoutDTO{Split: LayoutSplitRepoGroup, Into: LayoutIntoRepo, Groups: []RepoGroupDTO{{Name: "work", Repos: []string{"backend"}}}}}, false},
+		{"Unknown layout split", TargetDTO{Name: "github", Path: "/tmp/mirror", Author: personal, Layout: LayoutDTO{Split: "month"}}, true},
+		{"Unknown layout into", TargetDTO{Name: "github", Path: "/tmp/mirror", Author: personal, Layout: LayoutDTO{Split: LayoutSplitYear, Into: "tag"}}, true},
+		{"Repo group layout without groups", TargetDTO{Name: "github", Path: "/tmp/mirror", Author: personal, Layout: LayoutDTO{Split: LayoutSplitRepoGroup}}, true},
+		{"Repo group without repos", TargetDTO{Name: "github", Path: "/tmp/mirror", Author: personal, Layout: LayoutDTO{Split: LayoutSplitRepoGroup, Groups: []RepoGroupDTO{{Name: "work"}}}}, true},
+		{"Duplicated repo group", TargetDTO{Name: "github", Path: "/tmp/mirror", Author: personal, Layout: LayoutDTO{Split: LayoutSplitRepoGroup, Groups: []RepoGroupDTO{{Name: "work", Repos: []string{"backend"}}, {Name: "work", Repos: []string{"frontend"}}}}}, true},
+		{"Readme", TargetDTO{Name: "github", Path: "/tmp/mirror", Author: personal, Readme: ReadmeDTO{Enabled: true}}, false},
+		{"Readme with template", TargetDTO{Name: "github", Path: "/tmp/mirror", Author: personal, Readme: ReadmeDTO{Enabled: true, Template: "$TRACKO_TEST_TEMPLATES/README.md.tmpl"}}, false},
+		{"Readme with unknown field", TargetDTO{Name: "github", Path: "/tmp/mirror", Author: personal, Readme: ReadmeDTO{Enabled: true, Template: "$TRACKO_TEST_TEMPLATES/broken.tmpl"}}, true},
+		{"Readme with missing template", TargetDTO{Name: "github", Path: "/tmp/mirror", Author: personal, Readme: ReadmeDTO{Enabled: true, Template: "$TRACKO_TEST_TEMPLATES/missing.tmpl"}}, true},
+		{"Disabled readme with missing template", TargetDTO{Name: "github", Path: "/tmp/mirror", Author: personal, Readme: ReadmeDTO{Template: "$TRACKO_TEST_TEMPLATES/missing.tmpl"}}, false},
+		{"Groups without repo group layout", TargetDTO{Name: "github", Path: "/tmp/mirror", Author: personal, Layout: LayoutDTO{Split: LayoutSplitYear, Groups: []RepoGroupDTO{{Name: "work", Repos: []string{"backend"}}}}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model, err := tt.dto.ToModel()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ToModel() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if !errors.Is(err, internal_errors.ErrInvalidConfig) {
+					t.Errorf("Expected ErrInvalidConfig, got %v", err)
+				}
+				return
+			}
+
+			roundTrip, err := TargetDTOFromModel(*model).ToModel()
+			if err != nil {
+				t.Fatalf("Round trip ToModel() error = %v", err)
+			}
+			if !reflect.DeepEqual(roundTrip, model) {
+				t.Errorf("Expected round trip %+v, got %+v", model, roundTrip)
+			}
+		})
+	}
+}
+
+func Test_TargetDTO_ToModel_NamesField(t *testing.T) {
+	personal := IdentityDTO{Name: "Personal", Email: "me@personal.dev"}
+
+	tests := []struct {
+		name  string
+		dto   TargetDTO
+		field string
+	}{
+		{"Inverted date range", TargetDTO{Name: "github", Path: "/tmp/mirror", Author: personal, Since: "2025-01-01", Until: "2024-01-01"}, "until is before since"},
+		{"Invalid template", TargetDTO{Name: "github", Path: "/tmp/mirror", Author: personal, MessageTemplate: "{{.Unknown}}"}, "invalid message_template"},
+		{"Capped strategy without max", TargetDTO{Name: "github", Path: "/tmp/mirror", Author: personal, Strategy: StrategyCapped}, "max_per_day"},
+		{"Missing export author", TargetDTO{Name: "github", Path: "/tmp/mirror"}, "author requires a name"},
+		{"Signing without key", TargetDTO{Name: "github", Path: "/tmp/mirror", Author: personal, Signing: SigningDTO{Format: SigningFormatSSH}}, "signing.format"},
+		{"Invalid push auth", TargetDTO{Name: "github", Path: "/tmp/mirror", Author: personal, Push: PushDTO{Auth: "password"}}, "push.auth"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.dto.ToModel()
+			if !errors.Is(err, internal_errors.ErrInvalidConfig) || !strings.Contains(err.Error(), `target "github": `+tt.field) {
+				t.Errorf("Expected an error naming %s, got %v", tt.field, err)
+			}
+		})
+	}
+}
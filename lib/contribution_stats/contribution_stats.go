@@ -0,0 +1,136 @@
+package contribution_stats
+
+import (
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/HideyoshiNakazone/tracko/lib/config_model"
+	"github.com/HideyoshiNakazone/tracko/lib/repo"
+)
+
+// RepoStats sums the commits made to a single tracked repo.
+type RepoStats struct {
+	Repo         string
+	Commits      int
+	Additions    int
+	Deletions    int
+	FilesChanged int
+	First        time.Time
+	Last         time.Time
+}
+
+// LanguageStats counts the files changed in a language, a file changed by
+// several commits counts once per commit.
+type LanguageStats struct {
+	Language     string
+	FilesChanged int
+}
+
+// Streaks are runs of consecutive days with at least one commit. Current is
+// the run ending today, or yesterday when nothing was committed today yet.
+type Streaks struct {
+	Current int
+	Longest int
+}
+
+// DailyCounts returns the number of commits of each day, keyed by
+// config_model.DateFormat in the UTC offset of each commit.
+func DailyCounts(records []repo.CommitRecord) map[string]int {
+	counts := map[string]int{}
+	for _, record := range records {
+		counts[record.When.Format(config_model.DateFormat)]++
+	}
+	return counts
+}
+
+// ByRepo returns the stats of every repo with commits, most commits first.
+func ByRepo(records []repo.CommitRecord) []RepoStats {
+	index := map[string]int{}
+	var stats []RepoStats
+	for _, record := range records {
+		alias := filepath.Base(record.RepoPath)
+		i, ok := index[alias]
+		if !ok {
+			i = len(stats)
+			index[alias] = i
+			stats = append(stats, RepoStats{Repo: alias, First: record.When, Last: record.When})
+		}
+
+		s := &stats[i]
+		s.Commits++
+		s.Additions += record.Additions
+		s.Deletions += record.Deletions
+		s.FilesChanged += record.FilesChanged()
+		if record.When.Before(s.First) {
+			s.First = record.When
+		}
+		if record.When.After(s.Last) {
+			s.Last = record.When
+		}
+	}
+
+	sort.SliceStable(stats, func(i, j int) bool {
+		return stats[i].Commits > stats[j].Commits
+	})
+	return stats
+}
+
+// ByLanguage returns the files changed per language, guessed from the file
+// names, most changed first. Files of unknown languages are left out.
+func ByLanguage(records []repo.CommitRecord) []LanguageStats {
+	counts := map[string]int{}
+	for _, record := range records {
+		for _, file := range record.Files {
+			if language := Language(file); language != "" {
+				counts[language]++
+			}
+		}
+	}
+
+	stats := make([]LanguageStats, 0, len(counts))
+	for language, count := range counts {
+		stats = append(stats, LanguageStats{Language: language, FilesChanged: count})
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].FilesChanged != stats[j].FilesChanged {
+			return stats[i].FilesChanged > stats[j].FilesChanged
+		}
+		return stats[i].Language < stats[j].Language
+	})
+	return stats
+}
+
+// ComputeStreaks returns the streaks of counts as of the day of now.
+func ComputeStreaks(counts map[string]int, now time.Time) Streaks {
+	days := make([]string, 0, len(counts))
+	for day, count := range counts {
+		if count > 0 {
+			days = append(days, day)
+		}
+	}
+	sort.Strings(days)
+
+	var streaks Streaks
+	run := 0
+	var previous time.Time
+	for _, day := range days {
+		date, err := time.Parse(config_model.DateFormat, day)
+		if err != nil {
+			continue
+		}
+		if run > 0 && date.Equal(previous.AddDate(0, 0, 1)) {
+			run++
+		} else {
+			run = 1
+		}
+		previous = date
+		streaks.Longest = max(streaks.Longest, run)
+	}
+
+	today, _ := time.Parse(config_model.DateFormat, now.Format(config_model.DateFormat))
+	if run > 0 && (previous.Equal(today) || previous.Equal(today.AddDate(0, 0, -1))) {
+		streaks.Current = run
+	}
+	return streaks
+}
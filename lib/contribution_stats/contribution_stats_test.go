@@ -0,0 +1,78 @@
+package contribution_stats
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/HideyoshiNakazone/tracko/lib/repo"
+)
+
+func sampleRecords() []repo.CommitRecord {
+	day := func(d int, hour int) time.Time {
+		return time.Date(2025, 1, d, hour, 0, 0, 0, time.UTC)
+	}
+	return []repo.CommitRecord{
+		{RepoPath: "/src/backend", When: day(1, 9), Additions: 10, Deletions: 2, Files: []string{"main.go", "go.mod"}},
+		{RepoPath: "/src/backend", When: day(1, 15), Additions: 5, Files: []string{"api/users.go"}},
+		{RepoPath: "/src/frontend", When: day(2, 10), Additions: 20, Deletions: 4, Files: []string{"src/App.tsx", "src/app.css", "README.md"}},
+		{RepoPath: "/src/backend", When: day(3, 11), Deletions: 7, Files: []string{"Dockerfile", "LICENSE"}},
+		{RepoPath: "/src/frontend", When: day(6, 12), Additions: 1, Files: []string{"src/index.ts"}},
+	}
+}
+
+func Test_DailyCounts(t *testing.T) {
+	expected := map[string]int{"2025-01-01": 2, "2025-01-02": 1, "2025-01-03": 1, "2025-01-06": 1}
+	if counts := DailyCounts(sampleRecords()); !reflect.DeepEqual(counts, expected) {
+		t.Errorf("Expected counts %v, got %v", expected, counts)
+	}
+}
+
+func Test_ByRepo(t *testing.T) {
+	expected := []RepoStats{
+		{Repo: "backend", Commits: 3, Additions: 15, Deletions: 9, FilesChanged: 5, First: time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC), Last: time.Date(2025, 1, 3, 11, 0, 0, 0, time.UTC)},
+		{Repo: "frontend", Commits: 2, Additions: 21, Deletions: 4, FilesChanged: 4, First: time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC), Last: time.Date(2025, 1, 6, 12, 0, 0, 0, time.UTC)},
+	}
+	if stats := ByRepo(sampleRecords()); !reflect.DeepEqual(stats, expected) {
+		t.Errorf("Expected stats %+v, got %+v", expected, stats)
+	}
+}
+
+func Test_ByLanguage(t *testing.T) {
+	expected := []LanguageStats{
+		{Language: "Go", FilesChanged: 3},
+		{Language: "TypeScript", FilesChanged: 2},
+		{Language: "CSS", FilesChanged: 1},
+		{Language: "Dockerfile", FilesChanged: 1},
+		{Language: "Markdown", FilesChanged: 1},
+	}
+	if stats := ByLanguage(sampleRecords()); !reflect.DeepEqual(stats, expected) {
+		t.Errorf("Expected stats %+v, got %+v", expected, stats)
+	}
+}
+
+func Test_ComputeStreaks(t *testing.T) {
+	counts := DailyCounts(sampleRecords())
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want Streaks
+	}{
+		{"On the last commit day", time.Date(2025, 1, 6, 20, 0, 0, 0, time.UTC), Streaks{Current: 1, Longest: 3}},
+		{"The day after", time.Date(2025, 1, 7, 8, 0, 0, 0, time.UTC), Streaks{Current: 1, Longest: 3}},
+		{"Two days after", time.Date(2025, 1, 8, 8, 0, 0, 0, time.UTC), Streaks{Current: 0, Longest: 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ComputeStreaks(counts, tt.now); got != tt.want {
+				t.Errorf("ComputeStreaks() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	if got := ComputeStreaks(nil, time.Now()); got != (Streaks{}) {
+		t.Errorf("Expected no streaks without commits, got %+v", got)
+	}
+}
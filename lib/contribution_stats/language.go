@@ -0,0 +1,81 @@
+package contribution_stats
+
+import (
+	"path"
+	"strings"
+)
+
+// languagesByName holds file names that have a language of their own, checked
+// before extensions.
+var languagesByName = map[string]string{
+	"dockerfile":     "Dockerfile",
+	"makefile":       "Makefile",
+	"cmakelists.txt": "CMake",
+	"go.mod":         "Go",
+	"go.sum":         "Go",
+}
+
+var languagesByExtension = map[string]string{
+	".c":      "C",
+	".h":      "C",
+	".cc":     "C++",
+	".cpp":    "C++",
+	".cxx":    "C++",
+	".hpp":    "C++",
+	".cs":     "C#",
+	".clj":    "Clojure",
+	".css":    "CSS",
+	".scss":   "SCSS",
+	".sass":   "SCSS",
+	".dart":   "Dart",
+	".ex":     "Elixir",
+	".exs":    "Elixir",
+	".erl":    "Erlang",
+	".go":     "Go",
+	".hs":     "Haskell",
+	".html":   "HTML",
+	".htm":    "HTML",
+	".java":   "Java",
+	".js":     "JavaScript",
+	".mjs":    "JavaScript",
+	".cjs":    "JavaScript",
+	".jsx":    "JavaScript",
+	".json":   "JSON",
+	".kt":     "Kotlin",
+	".kts":    "Kotlin",
+	".lua":    "Lua",
+	".md":     "Markdown",
+	".ml":     "OCaml",
+	".php":    "PHP",
+	".pl":     "Perl",
+	".py":     "Python",
+	".r":      "R",
+	".rb":     "Ruby",
+	".rs":     "Rust",
+	".scala":  "Scala",
+	".sh":     "Shell",
+	".bash":   "Shell",
+	".zsh":    "Shell",
+	".sql":    "SQL",
+	".swift":  "Swift",
+	".tf":     "HCL",
+	".toml":   "TOML",
+	".ts":     "TypeScript",
+	".tsx":    "TypeScript",
+	".vue":    "Vue",
+	".svelte": "Svelte",
+	".xml":    "XML",
+	".yaml":   "YAML",
+	".yml":    "YAML",
+	".zig":    "Zig",
+}
+
+// Language guesses the language of a file from its name, empty when the file
+// is not source code of a known language.
+func Language(filePath string) string {
+	name := strings.ToLower(path.Base(filePath))
+	if language, ok := languagesByName[name]; ok {
+		return language
+	}
+	return languagesByExtension[path.Ext(name)]
+}
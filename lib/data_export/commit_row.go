@@ -0,0 +1,122 @@
+package data_export
+
+import (
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/HideyoshiNakazone/tracko/lib/config_model"
+	"github.com/HideyoshiNakazone/tracko/lib/repo"
+)
+
+// CommitRow is the schema shared by every structured export format, one row
+// per tracked commit. The columns, in order, are:
+//
+//	repo           alias of the tracked repository, the base name of its path
+//	repo_path      path of the tracked repository as configured
+//	hash           full hash of the source commit
+//	author_name    author name of the commit
+//	author_email   author email of the commit
+//	authored_at    author date in RFC 3339, keeping the original UTC offset
+//	authored_unix  author date in seconds since the Unix epoch
+//	date           calendar day of the author date (YYYY-MM-DD) in its offset
+//	type           conventional commit type, empty when the subject has none
+//	subject        first line of the commit message
+//	message        full commit message
+//	additions      lines added
+//	deletions      lines removed
+//	files_changed  number of files changed
+//	files          paths of the files changed, joined with ";" in CSV
+//
+// Columns are never renamed or reordered, new columns are only appended.
+type CommitRow struct {
+	Repo         string   `json:"repo" parquet:"repo"`
+	RepoPath     string   `json:"repo_path" parquet:"repo_path"`
+	Hash         string   `json:"hash" parquet:"hash"`
+	AuthorName   string   `json:"author_name" parquet:"author_name"`
+	AuthorEmail  string   `json:"author_email" parquet:"author_email"`
+	AuthoredAt   string   `json:"authored_at" parquet:"authored_at"`
+	AuthoredUnix int64    `json:"authored_unix" parquet:"authored_unix"`
+	Date         string   `json:"date" parquet:"date"`
+	Type         string   `json:"type" parquet:"type"`
+	Subject      string   `json:"subject" parquet:"subject"`
+	Message      string   `json:"message" parquet:"message"`
+	Additions    int64    `json:"additions" parquet:"additions"`
+	Deletions    int64    `json:"deletions" parquet:"deletions"`
+	FilesChanged int64    `json:"files_changed" parquet:"files_changed"`
+	Files        []string `json:"files" parquet:"files,list"`
+}
+
+// Columns are the column names of CommitRow, in order.
+var Columns = []string{
+	"repo",
+	"repo_path",
+	"hash",
+	"author_name",
+	"author_email",
+	"authored_at",
+	"authored_unix",
+	"date",
+	"type",
+	"subject",
+	"message",
+	"additions",
+	"deletions",
+	"files_changed",
+	"files",
+}
+
+func NewCommitRow(record repo.CommitRecord) CommitRow {
+	files := record.Files
+	if files == nil {
+		files = []string{}
+	}
+
+	return CommitRow{
+		Repo:         filepath.Base(record.RepoPath),
+		RepoPath:     record.RepoPath,
+		Hash:         record.Hash,
+		AuthorName:   record.Author,
+		AuthorEmail:  record.Email,
+		AuthoredAt:   record.When.Format(time.RFC3339),
+		AuthoredUnix: record.When.Unix(),
+		Date:         record.When.Format(config_model.DateFormat),
+		Type:         record.ConventionalType(),
+		Subject:      record.Subject(),
+		Message:      record.Message,
+		Additions:    int64(record.Additions),
+		Deletions:    int64(record.Deletions),
+		FilesChanged: int64(record.FilesChanged()),
+		Files:        files,
+	}
+}
+
+func NewCommitRows(records []repo.CommitRecord) []CommitRow {
+	rows := make([]CommitRow, 0, len(records))
+	for _, record := range records {
+		rows = append(rows, NewCommitRow(record))
+	}
+	return rows
+}
+
+// CollectCommits reads the commits of the tracked author from every tracked
+// repository, oldest first.
+func CollectCommits(cfg *config_model.ConfigModel) ([]repo.CommitRecord, error) {
+	return repo.ReadTrackedCommits(cfg.TrackedRepos(), cfg.TrackedAuthor().Emails())
+}
+
+// FilterRepos keeps the records of the given repos, by path or alias. No
+// repos keeps every record.
+func FilterRepos(records []repo.CommitRecord, repos []string) []repo.CommitRecord {
+	if len(repos) == 0 {
+		return records
+	}
+
+	var filtered []repo.CommitRecord
+	for _, record := range records {
+		if slices.Contains(repos, record.RepoPath) || slices.Contains(repos, filepath.Base(record.RepoPath)) {
+			filtered = append(filtered, record)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,44 @@
+package data_export
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// FilesSeparator joins the changed files of a commit in the CSV files column.
+const FilesSeparator = ";"
+
+func writeCSV(w io.Writer, rows []CommitRow) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(Columns); err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		err := writer.Write([]string{
+			row.Repo,
+			row.RepoPath,
+			row.Hash,
+			row.AuthorName,
+			row.AuthorEmail,
+			row.AuthoredAt,
+			strconv.FormatInt(row.AuthoredUnix, 10),
+			row.Date,
+			row.Type,
+			row.Subject,
+			row.Message,
+			strconv.FormatInt(row.Additions, 10),
+			strconv.FormatInt(row.Deletions, 10),
+			strconv.FormatInt(row.FilesChanged, 10),
+			strings.Join(row.Files, FilesSeparator),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
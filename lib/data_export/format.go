@@ -0,0 +1,103 @@
+package data_export
+
+import (
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/HideyoshiNakazone/tracko/lib/config_model"
+	"github.com/HideyoshiNakazone/tracko/lib/heatmap"
+	"github.com/HideyoshiNakazone/tracko/lib/internal_errors"
+	"github.com/HideyoshiNakazone/tracko/lib/repo"
+)
+
+const (
+	FormatCSV     = "csv"
+	FormatJSON    = "json"
+	FormatNDJSON  = "ndjson"
+	FormatParquet = "parquet"
+	FormatICS     = "ics"
+	FormatSVG     = "svg"
+)
+
+// Options tune the formats that render a period of time, the zero value
+// renders the last 12 months with the default palette.
+type Options struct {
+	Range   heatmap.Range
+	Palette heatmap.Palette
+}
+
+func (o Options) heatmapRange() heatmap.Range {
+	if o.Range.From.IsZero() && o.Range.To.IsZero() {
+		return heatmap.RollingRange(now())
+	}
+	return o.Range
+}
+
+func (o Options) heatmapPalette() heatmap.Palette {
+	if o.Palette == (heatmap.Palette{}) {
+		return heatmap.DefaultPalette
+	}
+	return o.Palette
+}
+
+type formatWriter func(w io.Writer, cfg *config_model.ConfigModel, records []repo.CommitRecord, options Options) error
+
+// rowWriter adapts writers of the CommitRow schema to formatWriter.
+func rowWriter(write func(w io.Writer, rows []CommitRow) error) formatWriter {
+	return func(w io.Writer, _ *config_model.ConfigModel, records []repo.CommitRecord, _ Options) error {
+		return write(w, NewCommitRows(records))
+	}
+}
+
+var formatWriters = map[string]formatWriter{
+	FormatCSV:     rowWriter(writeCSV),
+	FormatJSON:    rowWriter(writeJSON),
+	FormatNDJSON:  rowWriter(writeNDJSON),
+	FormatParquet: rowWriter(writeParquet),
+	FormatICS:     writeICS,
+	FormatHTML:    writeHTML,
+	FormatSVG:     writeSVG,
+
+	FormatOpenMetrics: writeOpenMetrics,
+
+	FormatToggl:    timesheetWriter(TogglColumns, togglRow),
+	FormatClockify: timesheetWriter(ClockifyColumns, clockifyRow),
+	FormatHarvest:  timesheetWriter(HarvestColumns, harvestRow),
+}
+
+// Formats returns the supported structured export formats, sorted.
+func Formats() []string {
+	formats := make([]string, 0, len(formatWriters))
+	for format := range formatWriters {
+		formats = append(formats, format)
+	}
+	slices.Sort(formats)
+	return formats
+}
+
+// IsBinary reports whether a format can not be written to a terminal.
+func IsBinary(format string) bool {
+	return format == FormatParquet
+}
+
+// IsSite reports whether a format is written as a static site, to the
+// SiteIndex page of a directory.
+func IsSite(format string) bool {
+	return format == FormatHTML
+}
+
+// Write writes records to w in the given format. The csv, json, ndjson and
+// parquet formats write one CommitRow per record, ics writes one event per
+// work session and toggl, clockify and harvest write one time entry per work
+// session on a project. The html format writes a dashboard page and svg a
+// heatmap image, both of the range and palette of options. The openmetrics
+// format writes the metric families of MetricFamilies in the Prometheus text
+// format.
+func Write(format string, w io.Writer, cfg *config_model.ConfigModel, records []repo.CommitRecord, options Options) error {
+	writer, ok := formatWriters[format]
+	if !ok {
+		return fmt.Errorf("%w: %q, expected one of %v", internal_errors.ErrUnknownFormat, format, Formats())
+	}
+	return writer(w, cfg, records, options)
+}
@@ -0,0 +1,201 @@
+package data_export
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/HideyoshiNakazone/tracko/lib/internal_errors"
+	"github.com/HideyoshiNakazone/tracko/lib/repo"
+)
+
+func sampleRecords() []repo.CommitRecord {
+	return []repo.CommitRecord{
+		{
+			RepoPath:  "/home/user/projects/backend",
+			Hash:      "0123456789abcdef0123456789abcdef01234567",
+			Author:    "Test User",
+			Email:     "test@example.com",
+			When:      time.Date(2025, 1, 1, 23, 30, 0, 0, time.FixedZone("", -3*60*60)),
+			Message:   "feat(api): add users endpoint\n\nWith pagination, \"quoted\".\n",
+			Additions: 12,
+			Deletions: 3,
+			Files:     []string{"api/users.go", "api/users_test.go"},
+		},
+		{
+			RepoPath: "/home/user/projects/frontend",
+			Hash:     "89abcdef0123456789abcdef0123456789abcdef",
+			Author:   "Test User",
+			Email:    "test@example.com",
+			When:     time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC),
+			Message:  "update readme",
+		},
+	}
+}
+
+func expectedRows() []CommitRow {
+	return []CommitRow{
+		{
+			Repo:         "backend",
+			RepoPath:     "/home/user/projects/backend",
+			Hash:         "0123456789abcdef0123456789abcdef01234567",
+			AuthorName:   "Test User",
+			AuthorEmail:  "test@example.com",
+			AuthoredAt:   "2025-01-01T23:30:00-03:00",
+			AuthoredUnix: 1735785000,
+			Date:         "2025-01-01",
+			Type:         "feat",
+			Subject:      "feat(api): add users endpoint",
+			Message:      "feat(api): add users endpoint\n\nWith pagination, \"quoted\".\n",
+			Additions:    12,
+			Deletions:    3,
+			FilesChanged: 2,
+			Files:        []string{"api/users.go", "api/users_test.go"},
+		},
+		{
+			Repo:         "frontend",
+			RepoPath:     "/home/user/projects/frontend",
+			Hash:         "89abcdef0123456789abcdef0123456789abcdef",
+			AuthorName:   "Test User",
+			AuthorEmail:  "test@example.com",
+			AuthoredAt:   "2025-01-02T09:00:00Z",
+			AuthoredUnix: 1735808400,
+			Date:         "2025-01-02",
+			Subject:      "update readme",
+			Message:      "update readme",
+			Files:        []string{},
+		},
+	}
+}
+
+// Test_Columns locks the exported schema, columns can only be appended.
+func Test_Columns(t *testing.T) {
+	expected := []string{
+		"repo", "repo_path", "hash", "author_name", "author_email", "authored_at", "authored_unix",
+		"date", "type", "subject", "message", "additions", "deletions", "files_changed", "files",
+	}
+	if !reflect.DeepEqual(Columns, expected) {
+		t.Fatalf("Expected columns %v, got %v", expected, Columns)
+	}
+
+	rowType := reflect.TypeOf(CommitRow{})
+	if rowType.NumField() != len(Columns) {
+		t.Fatalf("Expected %d fields in CommitRow, got %d", len(Columns), rowType.NumField())
+	}
+	for i, column := range Columns {
+		field := rowType.Field(i)
+		if tag := field.Tag.Get("json"); tag != column {
+			t.Errorf("Expected json tag %q on %s, got %q", column, field.Name, tag)
+		}
+		if tag, _, _ := strings.Cut(field.Tag.Get("parquet"), ","); tag != column {
+			t.Errorf("Expected parquet tag %q on %s, got %q", column, field.Name, tag)
+		}
+	}
+}
+
+func Test_Write_CSV(t *testing.T) {
+	var buffer bytes.Buffer
+	if err := Write(FormatCSV, &buffer, nil, sampleRecords(), Options{}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	rows, err := csv.NewReader(&buffer).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read CSV: %v", err)
+	}
+
+	expected := [][]string{
+		Columns,
+		{
+			"backend", "/home/user/projects/backend", "0123456789abcdef0123456789abcdef01234567",
+			"Test User", "test@example.com", "2025-01-01T23:30:00-03:00", "1735785000", "2025-01-01", "feat",
+			"feat(api): add users endpoint", "feat(api): add users endpoint\n\nWith pagination, \"quoted\".\n",
+			"12", "3", "2", "api/users.go;api/users_test.go",
+		},
+		{
+			"frontend", "/home/user/projects/frontend", "89abcdef0123456789abcdef0123456789abcdef",
+			"Test User", "test@example.com", "2025-01-02T09:00:00Z", "1735808400", "2025-01-02", "",
+			"update readme", "update readme", "0", "0", "0", "",
+		},
+	}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Errorf("Expected rows %q, got %q", expected, rows)
+	}
+}
+
+func Test_Write_JSON(t *testing.T) {
+	var buffer bytes.Buffer
+	if err := Write(FormatJSON, &buffer, nil, sampleRecords(), Options{}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	var rows []CommitRow
+	if err := json.Unmarshal(buffer.Bytes(), &rows); err != nil {
+		t.Fatalf("Failed to parse JSON: %v", err)
+	}
+	if !reflect.DeepEqual(rows, expectedRows()) {
+		t.Errorf("Expected rows %+v, got %+v", expectedRows(), rows)
+	}
+}
+
+func Test_Write_NDJSON(t *testing.T) {
+	var buffer bytes.Buffer
+	if err := Write(FormatNDJSON, &buffer, nil, sampleRecords(), Options{}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	var rows []CommitRow
+	scanner := bufio.NewScanner(&buffer)
+	for scanner.Scan() {
+		var row CommitRow
+		if err := json.Unmarshal(scanner.Bytes(), &row); err != nil {
+			t.Fatalf("Failed to parse line %q: %v", scanner.Text(), err)
+		}
+		rows = append(rows, row)
+	}
+	if !reflect.DeepEqual(rows, expectedRows()) {
+		t.Errorf("Expected rows %+v, got %+v", expectedRows(), rows)
+	}
+}
+
+func Test_Write_Parquet(t *testing.T) {
+	var buffer bytes.Buffer
+	if err := Write(FormatParquet, &buffer, nil, sampleRecords(), Options{}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	file, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatalf("Failed to open parquet file: %v", err)
+	}
+
+	columns := []string{}
+	for _, field := range file.Schema().Fields() {
+		columns = append(columns, field.Name())
+	}
+	if !reflect.DeepEqual(columns, Columns) {
+		t.Errorf("Expected parquet columns %v, got %v", Columns, columns)
+	}
+
+	rows, err := parquet.Read[CommitRow](bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatalf("Failed to read parquet rows: %v", err)
+	}
+	if !reflect.DeepEqual(rows, expectedRows()) {
+		t.Errorf("Expected rows %+v, got %+v", expectedRows(), rows)
+	}
+}
+
+func Test_Write_UnknownFormat(t *testing.T) {
+	if err := Write("xml", &bytes.Buffer{}, nil, sampleRecords(), Options{}); !errors.Is(err, internal_errors.ErrUnknownFormat) {
+		t.Errorf("Expected ErrUnknownFormat, got %v", err)
+	}
+}
@@ -0,0 +1,122 @@
+package data_export
+
+import (
+	"bytes"
+	_ "embed"
+	"html/template"
+	"io"
+	"time"
+
+	"github.com/HideyoshiNakazone/tracko/lib/config_model"
+	"github.com/HideyoshiNakazone/tracko/lib/contribution_stats"
+	"github.com/HideyoshiNakazone/tracko/lib/heatmap"
+	"github.com/HideyoshiNakazone/tracko/lib/repo"
+)
+
+const FormatHTML = "html"
+
+// SiteIndex is the page of a site export, the site is a single page with
+// every style and image inlined so that it can be hosted anywhere.
+const SiteIndex = "index.html"
+
+//go:embed templates/dashboard.html.tmpl
+var dashboardTemplate string
+
+var dashboard = template.Must(template.New("dashboard").Parse(dashboardTemplate))
+
+// languageColors are cycled through in the language chart.
+var languageColors = []string{"#216e39", "#30a14e", "#40c463", "#0969da", "#54aeff", "#8250df", "#bf8700", "#cf222e"}
+
+// now is replaced in tests to pin the dashboard range.
+var now = time.Now
+
+type dashboardRepo struct {
+	contribution_stats.RepoStats
+	First string
+	Last  string
+	Share int
+}
+
+type dashboardLanguage struct {
+	contribution_stats.LanguageStats
+	Share int
+	Color string
+}
+
+type dashboardData struct {
+	Author     string
+	Commits    int
+	From       string
+	To         string
+	ActiveDays int
+	Streaks    contribution_stats.Streaks
+	Heatmap    template.HTML
+	RangeFrom  string
+	RangeTo    string
+	Repos      []dashboardRepo
+	Languages  []dashboardLanguage
+	Generated  string
+}
+
+// writeHTML writes a dashboard page with a heatmap, the streaks and a
+// breakdown of the commits per repo and language.
+func writeHTML(w io.Writer, cfg *config_model.ConfigModel, records []repo.CommitRecord, options Options) error {
+	today := now()
+	counts := contribution_stats.DailyCounts(records)
+
+	data := dashboardData{
+		Commits:   len(records),
+		Streaks:   contribution_stats.ComputeStreaks(counts, today),
+		Generated: today.Format(config_model.DateFormat),
+	}
+	if cfg != nil {
+		data.Author = cfg.TrackedAuthor().Name()
+	}
+	if len(records) > 0 {
+		data.From = records[0].When.Format(config_model.DateFormat)
+		data.To = records[len(records)-1].When.Format(config_model.DateFormat)
+	}
+	for _, count := range counts {
+		if count > 0 {
+			data.ActiveDays++
+		}
+	}
+
+	var svg bytes.Buffer
+	heatmapRange := options.heatmapRange()
+	if err := heatmap.Render(&svg, counts, heatmapRange.From, heatmapRange.To, options.heatmapPalette()); err != nil {
+		return err
+	}
+	data.Heatmap = template.HTML(svg.String())
+	data.RangeFrom = heatmapRange.From.Format(config_model.DateFormat)
+	data.RangeTo = heatmapRange.To.Format(config_model.DateFormat)
+
+	repos := contribution_stats.ByRepo(records)
+	for _, stats := range repos {
+		data.Repos = append(data.Repos, dashboardRepo{
+			RepoStats: stats,
+			First:     stats.First.Format(config_model.DateFormat),
+			Last:      stats.Last.Format(config_model.DateFormat),
+			Share:     share(stats.Commits, repos[0].Commits),
+		})
+	}
+
+	languages := contribution_stats.ByLanguage(records)
+	for i, stats := range languages {
+		data.Languages = append(data.Languages, dashboardLanguage{
+			LanguageStats: stats,
+			Share:         share(stats.FilesChanged, languages[0].FilesChanged),
+			Color:         languageColors[i%len(languageColors)],
+		})
+	}
+
+	return dashboard.Execute(w, data)
+}
+
+// share returns value as a percentage of the largest value.
+func share(value int, largest int) int {
+	if largest == 0 {
+		return 0
+	}
+	return value * 100 / largest
+}
@@ -0,0 +1,64 @@
+package data_export
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/HideyoshiNakazone/tracko/lib/config_model"
+	"github.com/HideyoshiNakazone/tracko/lib/repo"
+)
+
+func Test_Write_HTML(t *testing.T) {
+	today := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return today }
+	defer func() { now = time.Now }()
+
+	records := []repo.CommitRecord{
+		{RepoPath: "/src/backend", When: today.AddDate(0, 0, -2), Additions: 10, Files: []string{"main.go"}},
+		{RepoPath: "/src/backend", When: today.AddDate(0, 0, -1), Deletions: 3, Files: []string{"main.go", "README.md"}},
+		{RepoPath: "/src/<frontend>", When: today, Additions: 4, Files: []string{"src/app.ts"}},
+	}
+
+	cfg, err := config_model.NewConfigBuilder().
+		WithTrackedAuthor("Test <User>", []string{"test@example.com"}).
+		WithTargetRepo("target", "Test User", "test@personal.example.com").
+		Build()
+	if err != nil {
+		t.Fatalf("Failed to build config: %v", err)
+	}
+
+	var buffer bytes.Buffer
+	if err := Write(FormatHTML, &buffer, cfg, records, Options{}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	page := buffer.String()
+
+	for _, expected := range []string{
+		"<h1>Test &lt;User&gt;</h1>",
+		"3 commits from 2025-03-08 to 2025-03-10",
+		"<strong>3</strong><span class=\"muted\">day current streak</span>",
+		`<svg xmlns="http://www.w3.org/2000/svg"`,
+		`data-date="2025-03-10" data-count="1"`,
+		"<td>backend</td>",
+		"<td>&lt;frontend&gt;</td>",
+		"<span>Go</span>",
+		"<span>TypeScript</span>",
+	} {
+		if !strings.Contains(page, expected) {
+			t.Errorf("Expected the dashboard to contain %q", expected)
+		}
+	}
+
+	if strings.Contains(page, "ZgotmplZ") {
+		t.Errorf("Expected every template value to be allowed in its context")
+	}
+
+	// The site must not load anything from other hosts
+	external := regexp.MustCompile(`(?i)(src|href)\s*=\s*["']?(https?:)?//|@import|url\(`)
+	if match := external.FindString(page); match != "" {
+		t.Errorf("Expected no external assets, found %q", match)
+	}
+}
@@ -0,0 +1,118 @@
+package data_export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/HideyoshiNakazone/tracko/lib/config_model"
+	"github.com/HideyoshiNakazone/tracko/lib/repo"
+	"github.com/HideyoshiNakazone/tracko/lib/work_session"
+)
+
+// ICSProductID identifies tracko as the producer of the calendar.
+const ICSProductID = "-//tracko//Work Sessions//EN"
+
+const icsDateTimeFormat = "20060102T150405Z"
+
+// icsLineLimit is the maximum length of a content line in octets, without
+// the line break, longer lines are folded.
+const icsLineLimit = 75
+
+var icsTextEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// writeICS writes an RFC 5545 calendar with one event per work session. The
+// summary holds the repo aliases and the commit count, the description the
+// subject of every commit.
+func writeICS(w io.Writer, cfg *config_model.ConfigModel, records []repo.CommitRecord, _ Options) error {
+	sessions := config_model.NewDefaultSessionModel()
+	if cfg != nil {
+		sessions = cfg.Sessions()
+	}
+
+	writer := &icsWriter{w: bufio.NewWriter(w)}
+	writer.line("BEGIN", "VCALENDAR")
+	writer.line("VERSION", "2.0")
+	writer.line("PRODID", ICSProductID)
+	writer.line("CALSCALE", "GREGORIAN")
+
+	for _, session := range work_session.Cluster(records, sessions) {
+		writeICSEvent(writer, session)
+	}
+
+	writer.line("END", "VCALENDAR")
+	return writer.flush()
+}
+
+func writeICSEvent(writer *icsWriter, session work_session.Session) {
+	commits := fmt.Sprintf("%d commits", len(session.Commits))
+	if len(session.Commits) == 1 {
+		commits = "1 commit"
+	}
+
+	var description []string
+	for _, record := range session.Commits {
+		description = append(description, filepath.Base(record.RepoPath)+": "+record.Subject())
+	}
+
+	writer.line("BEGIN", "VEVENT")
+	// The first commit identifies the session, so importing again updates
+	// the events instead of duplicating them
+	writer.line("UID", session.Commits[0].Hash+"@tracko")
+	// Without a METHOD, DTSTAMP is the last time the event changed
+	writer.line("DTSTAMP", session.End.UTC().Format(icsDateTimeFormat))
+	writer.line("DTSTART", session.Start.UTC().Format(icsDateTimeFormat))
+	if session.End.After(session.Start) {
+		writer.line("DTEND", session.End.UTC().Format(icsDateTimeFormat))
+	}
+	writer.line("SUMMARY", icsText(strings.Join(session.Repos(), ", ")+": "+commits))
+	writer.line("DESCRIPTION", icsText(strings.Join(description, "\n")))
+	writer.line("END", "VEVENT")
+}
+
+func icsText(value string) string {
+	return icsTextEscaper.Replace(value)
+}
+
+// icsWriter writes content lines ended by CRLF, folding them at
+// icsLineLimit octets without splitting UTF-8 sequences.
+type icsWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (iw *icsWriter) line(name string, value string) {
+	if iw.err != nil {
+		return
+	}
+
+	content := name + ":" + value
+	limit := icsLineLimit
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+		iw.write(content[:cut] + "\r\n ")
+		content = content[cut:]
+		// Continuation lines start with a space, which counts to the limit
+		limit = icsLineLimit - 1
+	}
+	iw.write(content + "\r\n")
+}
+
+func (iw *icsWriter) write(s string) {
+	if iw.err == nil {
+		_, iw.err = iw.w.WriteString(s)
+	}
+}
+
+func (iw *icsWriter) flush() error {
+	if iw.err != nil {
+		return iw.err
+	}
+	return iw.w.Flush()
+}
@@ -0,0 +1,242 @@
+package data_export
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/HideyoshiNakazone/tracko/lib/config_model"
+	"github.com/HideyoshiNakazone/tracko/lib/repo"
+)
+
+type icsProperty struct {
+	name  string
+	value string
+}
+
+type icsComponent struct {
+	name       string
+	properties []icsProperty
+	children   []*icsComponent
+}
+
+func (c *icsComponent) values(name string) []string {
+	var values []string
+	for _, property := range c.properties {
+		if property.name == name {
+			values = append(values, property.value)
+		}
+	}
+	return values
+}
+
+var (
+	icsContentLine = regexp.MustCompile(`^([A-Za-z0-9-]+)((?:;[A-Za-z0-9-]+=[^:;]*)*):(.*)$`)
+	icsDateTime    = regexp.MustCompile(`^\d{8}T\d{6}Z$`)
+	icsEscape      = regexp.MustCompile(`\\.`)
+)
+
+// parseICS checks data against the syntax of RFC 5545 and returns the
+// VCALENDAR component.
+func parseICS(t *testing.T, data []byte) *icsComponent {
+	t.Helper()
+
+	if !utf8.Valid(data) {
+		t.Fatalf("Calendar is not valid UTF-8")
+	}
+	if !bytes.HasSuffix(data, []byte("\r\n")) {
+		t.Fatalf("Calendar must end with CRLF")
+	}
+
+	// 3.1: lines are delimited by CRLF, at most 75 octets, folded with a space
+	var lines []string
+	for _, physical := range strings.Split(strings.TrimSuffix(string(data), "\r\n"), "\r\n") {
+		if strings.ContainsAny(physical, "\r\n") {
+			t.Fatalf("Line %q contains a bare line break", physical)
+		}
+		if len(physical) > 75 {
+			t.Errorf("Line %q is longer than 75 octets", physical)
+		}
+		if strings.HasPrefix(physical, " ") || strings.HasPrefix(physical, "\t") {
+			if len(lines) == 0 {
+				t.Fatalf("Calendar starts with a continuation line")
+			}
+			lines[len(lines)-1] += physical[1:]
+			continue
+		}
+		lines = append(lines, physical)
+	}
+
+	var root *icsComponent
+	var stack []*icsComponent
+	for _, line := range lines {
+		match := icsContentLine.FindStringSubmatch(line)
+		if match == nil {
+			t.Fatalf("Invalid content line %q", line)
+		}
+		name, value := strings.ToUpper(match[1]), match[3]
+
+		switch name {
+		case "BEGIN":
+			component := &icsComponent{name: value}
+			if len(stack) == 0 {
+				if root != nil {
+					t.Fatalf("Expected a single top-level component, got another %s", value)
+				}
+				root = component
+			} else {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, component)
+			}
+			stack = append(stack, component)
+		case "END":
+			if len(stack) == 0 || stack[len(stack)-1].name != value {
+				t.Fatalf("Unbalanced END:%s", value)
+			}
+			stack = stack[:len(stack)-1]
+		default:
+			if len(stack) == 0 {
+				t.Fatalf("Property %s outside of a component", name)
+			}
+			component := stack[len(stack)-1]
+			component.properties = append(component.properties, icsProperty{name: name, value: value})
+		}
+	}
+	if len(stack) != 0 || root == nil || root.name != "VCALENDAR" {
+		t.Fatalf("Expected a single complete VCALENDAR")
+	}
+
+	// 3.6: PRODID and VERSION are required once
+	if values := root.values("PRODID"); len(values) != 1 {
+		t.Errorf("Expected one PRODID, got %v", values)
+	}
+	if values := root.values("VERSION"); len(values) != 1 || values[0] != "2.0" {
+		t.Errorf("Expected VERSION:2.0, got %v", values)
+	}
+
+	uids := map[string]bool{}
+	for _, event := range root.children {
+		if event.name != "VEVENT" {
+			continue
+		}
+
+		// 3.6.1: UID and DTSTAMP are required once, DTSTART without METHOD
+		for _, name := range []string{"UID", "DTSTAMP", "DTSTART"} {
+			if values := event.values(name); len(values) != 1 {
+				t.Errorf("Expected one %s in event, got %v", name, values)
+			}
+		}
+		uid := strings.Join(event.values("UID"), "")
+		if uids[uid] {
+			t.Errorf("Duplicated UID %q", uid)
+		}
+		uids[uid] = true
+
+		// 3.3.5: UTC date-times
+		times := map[string]time.Time{}
+		for _, name := range []string{"DTSTAMP", "DTSTART", "DTEND"} {
+			for _, value := range event.values(name) {
+				if !icsDateTime.MatchString(value) {
+					t.Errorf("Invalid %s %q", name, value)
+				}
+				times[name], _ = time.Parse("20060102T150405Z", value)
+			}
+		}
+		// 3.8.2.2: DTEND must be later than DTSTART
+		if end, ok := times["DTEND"]; ok && !end.After(times["DTSTART"]) {
+			t.Errorf("Expected DTEND after DTSTART, got %v and %v", times["DTEND"], times["DTSTART"])
+		}
+
+		// 3.3.11: commas, semicolons and backslashes in text are escaped
+		for _, name := range []string{"SUMMARY", "DESCRIPTION"} {
+			for _, value := range event.values(name) {
+				unescaped := icsEscape.ReplaceAllStringFunc(value, func(escape string) string {
+					if !strings.Contains(`\;,nN`, escape[1:]) {
+						t.Errorf("Invalid escape %q in %s", escape, name)
+					}
+					return ""
+				})
+				if strings.ContainsAny(unescaped, `\;,`) {
+					t.Errorf("Unescaped text in %s %q", name, value)
+				}
+			}
+		}
+	}
+
+	return root
+}
+
+func unescapeICSText(value string) string {
+	return strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n").Replace(value)
+}
+
+func Test_Write_ICS(t *testing.T) {
+	base := time.Date(2025, 1, 1, 9, 0, 0, 0, time.FixedZone("", 2*60*60))
+	records := []repo.CommitRecord{
+		{RepoPath: "/src/backend", Hash: "aaaa", When: base, Message: "feat: add users, roles; and a\\ path"},
+		{RepoPath: "/src/frontend", Hash: "bbbb", When: base.Add(time.Hour), Message: "fix: çà va très bien, " + strings.Repeat("long subject ", 10) + "end"},
+		{RepoPath: "/src/backend", Hash: "cccc", When: base.Add(6 * time.Hour), Message: "docs: readme"},
+	}
+
+	cfg, err := config_model.NewConfigBuilder().
+		WithTrackedAuthor("Test User", []string{"test@example.com"}).
+		WithTargetRepo("target", "Test User", "test@personal.example.com").
+		WithSessions(2*time.Hour, 0).
+		Build()
+	if err != nil {
+		t.Fatalf("Failed to build config: %v", err)
+	}
+
+	var buffer bytes.Buffer
+	if err := Write(FormatICS, &buffer, cfg, records, Options{}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	calendar := parseICS(t, buffer.Bytes())
+	if len(calendar.children) != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(calendar.children))
+	}
+
+	expected := []map[string]string{
+		{
+			"UID":         "aaaa@tracko",
+			"DTSTART":     "20250101T070000Z",
+			"DTEND":       "20250101T080000Z",
+			"SUMMARY":     "backend, frontend: 2 commits",
+			"DESCRIPTION": "backend: feat: add users, roles; and a\\ path\nfrontend: " + records[1].Message,
+		},
+		{
+			"UID":         "cccc@tracko",
+			"DTSTART":     "20250101T130000Z",
+			"SUMMARY":     "backend: 1 commit",
+			"DESCRIPTION": "backend: docs: readme",
+		},
+	}
+	for i, event := range calendar.children {
+		for name, want := range expected[i] {
+			got := unescapeICSText(strings.Join(event.values(name), ""))
+			if got != want {
+				t.Errorf("Expected event %d %s %q, got %q", i, name, want, got)
+			}
+		}
+	}
+
+	// A single commit session without lead has no duration
+	if values := calendar.children[1].values("DTEND"); len(values) != 0 {
+		t.Errorf("Expected no DTEND for an instant session, got %v", values)
+	}
+}
+
+func Test_Write_ICS_Empty(t *testing.T) {
+	var buffer bytes.Buffer
+	if err := Write(FormatICS, &buffer, nil, nil, Options{}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	if calendar := parseICS(t, buffer.Bytes()); len(calendar.children) != 0 {
+		t.Errorf("Expected no events, got %d", len(calendar.children))
+	}
+}
@@ -0,0 +1,24 @@
+package data_export
+
+import (
+	"encoding/json"
+	"io"
+)
+
+func writeJSON(w io.Writer, rows []CommitRow) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(rows)
+}
+
+// writeNDJSON writes one JSON object per line, so that large histories can
+// be streamed line by line.
+func writeNDJSON(w io.Writer, rows []CommitRow) error {
+	encoder := json.NewEncoder(w)
+	for _, row := range rows {
+		if err := encoder.Encode(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
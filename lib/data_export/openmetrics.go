@@ -0,0 +1,140 @@
+package data_export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/HideyoshiNakazone/tracko/lib/config_model"
+	"github.com/HideyoshiNakazone/tracko/lib/contribution_stats"
+	"github.com/HideyoshiNakazone/tracko/lib/repo"
+)
+
+const FormatOpenMetrics = "openmetrics"
+
+// MetricFamily describes a metric family of the openmetrics format. Names,
+// types and labels are part of the format, they are never changed so that
+// dashboards and alerts keep working, new families are only added.
+type MetricFamily struct {
+	Name   string
+	Type   string
+	Help   string
+	Labels []string
+}
+
+// ExposedName is the name the family is written with, counters take the
+// _total suffix.
+func (f MetricFamily) ExposedName() string {
+	if f.Type == "counter" {
+		return f.Name + "_total"
+	}
+	return f.Name
+}
+
+// Metric families of the openmetrics format, in order.
+var (
+	MetricCommits = MetricFamily{
+		Name: "tracko_commits", Type: "counter", Labels: []string{"repo"},
+		Help: "Commits of the tracked author in the repo.",
+	}
+	MetricLinesAdded = MetricFamily{
+		Name: "tracko_lines_added", Type: "counter", Labels: []string{"repo"},
+		Help: "Lines added by the commits of the tracked author in the repo.",
+	}
+	MetricLinesDeleted = MetricFamily{
+		Name: "tracko_lines_deleted", Type: "counter", Labels: []string{"repo"},
+		Help: "Lines deleted by the commits of the tracked author in the repo.",
+	}
+	MetricFilesChanged = MetricFamily{
+		Name: "tracko_files_changed", Type: "counter", Labels: []string{"repo"},
+		Help: "Files changed by the commits of the tracked author in the repo.",
+	}
+	MetricLastCommit = MetricFamily{
+		Name: "tracko_last_commit_timestamp_seconds", Type: "gauge", Labels: []string{"repo"},
+		Help: "Unix time of the last commit of the tracked author in the repo.",
+	}
+	MetricDaysSinceLastCommit = MetricFamily{
+		Name: "tracko_days_since_last_commit", Type: "gauge", Labels: []string{"repo"},
+		Help: "Whole days since the last commit of the tracked author in the repo.",
+	}
+	MetricStreakDays = MetricFamily{
+		Name: "tracko_streak_days", Type: "gauge", Labels: []string{"streak"},
+		Help: "Days in a row with commits, the current and the longest streak.",
+	}
+)
+
+var MetricFamilies = []MetricFamily{
+	MetricCommits,
+	MetricLinesAdded,
+	MetricLinesDeleted,
+	MetricFilesChanged,
+	MetricLastCommit,
+	MetricDaysSinceLastCommit,
+	MetricStreakDays,
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+type metricSample struct {
+	labels []string
+	value  string
+}
+
+// writeOpenMetrics writes the Prometheus text format, version 0.0.4, which is
+// what node_exporter's textfile collector parses. Samples have no timestamps
+// and counters are typed under their _total name, there are no UNIT or EOF
+// lines, which only the OpenMetrics parser understands.
+func writeOpenMetrics(w io.Writer, _ *config_model.ConfigModel, records []repo.CommitRecord, _ Options) error {
+	today := now()
+
+	repos := contribution_stats.ByRepo(records)
+	sort.Slice(repos, func(i, j int) bool {
+		return repos[i].Repo < repos[j].Repo
+	})
+
+	perRepo := func(value func(stats contribution_stats.RepoStats) int64) []metricSample {
+		samples := make([]metricSample, 0, len(repos))
+		for _, stats := range repos {
+			samples = append(samples, metricSample{[]string{stats.Repo}, strconv.FormatInt(value(stats), 10)})
+		}
+		return samples
+	}
+
+	streaks := contribution_stats.ComputeStreaks(contribution_stats.DailyCounts(records), today)
+
+	samples := map[string][]metricSample{
+		MetricCommits.Name:      perRepo(func(s contribution_stats.RepoStats) int64 { return int64(s.Commits) }),
+		MetricLinesAdded.Name:   perRepo(func(s contribution_stats.RepoStats) int64 { return int64(s.Additions) }),
+		MetricLinesDeleted.Name: perRepo(func(s contribution_stats.RepoStats) int64 { return int64(s.Deletions) }),
+		MetricFilesChanged.Name: perRepo(func(s contribution_stats.RepoStats) int64 { return int64(s.FilesChanged) }),
+		MetricLastCommit.Name:   perRepo(func(s contribution_stats.RepoStats) int64 { return s.Last.Unix() }),
+		MetricDaysSinceLastCommit.Name: perRepo(func(s contribution_stats.RepoStats) int64 {
+			return int64(max(0, today.Sub(s.Last)/(24*time.Hour)))
+		}),
+		MetricStreakDays.Name: {
+			{[]string{"current"}, strconv.Itoa(streaks.Current)},
+			{[]string{"longest"}, strconv.Itoa(streaks.Longest)},
+		},
+	}
+
+	b := bufio.NewWriter(w)
+	for _, family := range MetricFamilies {
+		name := family.ExposedName()
+		fmt.Fprintf(b, "# HELP %s %s\n", name, family.Help)
+		fmt.Fprintf(b, "# TYPE %s %s\n", name, family.Type)
+
+		for _, sample := range samples[family.Name] {
+			labels := make([]string, len(family.Labels))
+			for i, label := range family.Labels {
+				labels[i] = fmt.Sprintf(`%s="%s"`, label, labelEscaper.Replace(sample.labels[i]))
+			}
+			fmt.Fprintf(b, "%s{%s} %s\n", name, strings.Join(labels, ","), sample.value)
+		}
+	}
+
+	return b.Flush()
+}
@@ -0,0 +1,129 @@
+package data_export
+
+import (
+	"bytes"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/expfmt"
+
+	"github.com/HideyoshiNakazone/tracko/lib/repo"
+)
+
+// Test_MetricFamilies locks the names, types and labels of the exposed
+// metrics, families can only be appended.
+func Test_MetricFamilies(t *testing.T) {
+	type family struct {
+		Name   string
+		Type   string
+		Labels []string
+	}
+	expected := []family{
+		{"tracko_commits", "counter", []string{"repo"}},
+		{"tracko_lines_added", "counter", []string{"repo"}},
+		{"tracko_lines_deleted", "counter", []string{"repo"}},
+		{"tracko_files_changed", "counter", []string{"repo"}},
+		{"tracko_last_commit_timestamp_seconds", "gauge", []string{"repo"}},
+		{"tracko_days_since_last_commit", "gauge", []string{"repo"}},
+		{"tracko_streak_days", "gauge", []string{"streak"}},
+	}
+
+	var got []family
+	for _, metric := range MetricFamilies {
+		got = append(got, family{metric.Name, metric.Type, metric.Labels})
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected metric families %+v, got %+v", expected, got)
+	}
+}
+
+func Test_Write_OpenMetrics(t *testing.T) {
+	today := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return today }
+	defer func() { now = time.Now }()
+
+	records := []repo.CommitRecord{
+		{RepoPath: "/src/backend", When: time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC), Additions: 10, Deletions: 2, Files: []string{"a.go", "b.go"}},
+		{RepoPath: "/src/backend", When: time.Date(2025, 1, 8, 9, 0, 0, 0, time.UTC), Additions: 5, Files: []string{"a.go"}},
+		{RepoPath: `/src/odd "name"`, When: time.Date(2025, 1, 9, 18, 0, 0, 0, time.UTC), Deletions: 7, Files: []string{"c.go"}},
+		{RepoPath: `/src/odd "name"`, When: time.Date(2025, 1, 10, 8, 0, 0, 0, time.UTC), Additions: 1, Files: []string{"c.go"}},
+	}
+
+	var buffer bytes.Buffer
+	if err := Write(FormatOpenMetrics, &buffer, nil, records, Options{}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	expected := `# HELP tracko_commits_total Commits of the tracked author in the repo.
+# TYPE tracko_commits_total counter
+tracko_commits_total{repo="backend"} 2
+tracko_commits_total{repo="odd \"name\""} 2
+# HELP tracko_lines_added_total Lines added by the commits of the tracked author in the repo.
+# TYPE tracko_lines_added_total counter
+tracko_lines_added_total{repo="backend"} 15
+tracko_lines_added_total{repo="odd \"name\""} 1
+# HELP tracko_lines_deleted_total Lines deleted by the commits of the tracked author in the repo.
+# TYPE tracko_lines_deleted_total counter
+tracko_lines_deleted_total{repo="backend"} 2
+tracko_lines_deleted_total{repo="odd \"name\""} 7
+# HELP tracko_files_changed_total Files changed by the commits of the tracked author in the repo.
+# TYPE tracko_files_changed_total counter
+tracko_files_changed_total{repo="backend"} 3
+tracko_files_changed_total{repo="odd \"name\""} 2
+# HELP tracko_last_commit_timestamp_seconds Unix time of the last commit of the tracked author in the repo.
+# TYPE tracko_last_commit_timestamp_seconds gauge
+tracko_last_commit_timestamp_seconds{repo="backend"} 1736326800
+tracko_last_commit_timestamp_seconds{repo="odd \"name\""} 1736496000
+# HELP tracko_days_since_last_commit Whole days since the last commit of the tracked author in the repo.
+# TYPE tracko_days_since_last_commit gauge
+tracko_days_since_last_commit{repo="backend"} 2
+tracko_days_since_last_commit{repo="odd \"name\""} 0
+# HELP tracko_streak_days Days in a row with commits, the current and the longest streak.
+# TYPE tracko_streak_days gauge
+tracko_streak_days{streak="current"} 3
+tracko_streak_days{streak="longest"} 3
+`
+	if buffer.String() != expected {
+		t.Errorf("Expected metrics:\n%s\ngot:\n%s", expected, buffer.String())
+	}
+}
+
+// Test_Write_OpenMetrics_TextParser reads the output the way node_exporter's
+// textfile collector does, every family must keep its type and help.
+func Test_Write_OpenMetrics_TextParser(t *testing.T) {
+	records := []repo.CommitRecord{
+		{RepoPath: "/src/backend", When: time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC), Additions: 10, Files: []string{"a.go"}},
+	}
+
+	var buffer bytes.Buffer
+	if err := Write(FormatOpenMetrics, &buffer, nil, records, Options{}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(&buffer)
+	if err != nil {
+		t.Fatalf("TextToMetricFamilies() error = %v", err)
+	}
+	if len(families) != len(MetricFamilies) {
+		t.Errorf("Expected %d families, got %d", len(MetricFamilies), len(families))
+	}
+
+	for _, family := range MetricFamilies {
+		parsed, ok := families[family.ExposedName()]
+		if !ok {
+			t.Errorf("Expected family %s, got %v", family.ExposedName(), slices.Collect(maps.Keys(families)))
+			continue
+		}
+		if got := strings.ToLower(parsed.GetType().String()); got != family.Type {
+			t.Errorf("Expected %s to be a %s, got %s", family.ExposedName(), family.Type, got)
+		}
+		if parsed.GetHelp() != family.Help {
+			t.Errorf("Expected %s to keep its help, got %q", family.ExposedName(), parsed.GetHelp())
+		}
+	}
+}
@@ -0,0 +1,15 @@
+package data_export
+
+import (
+	"io"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+func writeParquet(w io.Writer, rows []CommitRow) error {
+	writer := parquet.NewGenericWriter[CommitRow](w)
+	if _, err := writer.Write(rows); err != nil {
+		return err
+	}
+	return writer.Close()
+}
@@ -0,0 +1,16 @@
+package data_export
+
+import (
+	"io"
+
+	"github.com/HideyoshiNakazone/tracko/lib/config_model"
+	"github.com/HideyoshiNakazone/tracko/lib/contribution_stats"
+	"github.com/HideyoshiNakazone/tracko/lib/heatmap"
+	"github.com/HideyoshiNakazone/tracko/lib/repo"
+)
+
+// writeSVG writes a standalone heatmap image of the commits per day.
+func writeSVG(w io.Writer, _ *config_model.ConfigModel, records []repo.CommitRecord, options Options) error {
+	heatmapRange := options.heatmapRange()
+	return heatmap.Render(w, contribution_stats.DailyCounts(records), heatmapRange.From, heatmapRange.To, options.heatmapPalette())
+}
@@ -0,0 +1,136 @@
+package data_export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/HideyoshiNakazone/tracko/lib/config_model"
+	"github.com/HideyoshiNakazone/tracko/lib/repo"
+	"github.com/HideyoshiNakazone/tracko/lib/work_session"
+)
+
+const (
+	FormatToggl    = "toggl"
+	FormatClockify = "clockify"
+	FormatHarvest  = "harvest"
+)
+
+// DefaultHarvestTask is the task of Harvest entries for projects mapped
+// without task, Harvest requires one on every entry.
+const DefaultHarvestTask = "Development"
+
+// Columns of the CSV import format of each time tracking tool, in order.
+var (
+	TogglColumns    = []string{"Email", "Start date", "Start time", "Duration", "Project", "Client", "Task", "Description"}
+	ClockifyColumns = []string{"Project", "Client", "Description", "Task", "Email", "Start Date", "Start Time", "End Date", "End Time", "Duration (h)"}
+	HarvestColumns  = []string{"Date", "Client", "Project", "Task", "Notes", "Hours", "First name", "Last name"}
+)
+
+type timesheetEntry struct {
+	session     work_session.ProjectSession
+	email       string
+	firstName   string
+	lastName    string
+	description string
+}
+
+// timesheetWriter adapts a row builder of a time tracking tool to
+// formatWriter. Entries are work sessions clustered by project, with the
+// times in the UTC offset of their commits.
+func timesheetWriter(columns []string, row func(entry timesheetEntry) []string) formatWriter {
+	return func(w io.Writer, cfg *config_model.ConfigModel, records []repo.CommitRecord, _ Options) error {
+		if cfg == nil {
+			return fmt.Errorf("timesheet exports require a config")
+		}
+
+		author := cfg.TrackedAuthor()
+		email := ""
+		if len(author.Emails()) > 0 {
+			email = author.Emails()[0]
+		}
+		firstName, lastName, _ := strings.Cut(author.Name(), " ")
+
+		writer := csv.NewWriter(w)
+		if err := writer.Write(columns); err != nil {
+			return err
+		}
+
+		for _, session := range work_session.ClusterByProject(records, cfg) {
+			var subjects []string
+			for _, record := range session.Commits {
+				subjects = append(subjects, record.Subject())
+			}
+
+			err := writer.Write(row(timesheetEntry{
+				session:     session,
+				email:       email,
+				firstName:   firstName,
+				lastName:    lastName,
+				description: strings.Join(subjects, "; "),
+			}))
+			if err != nil {
+				return err
+			}
+		}
+
+		writer.Flush()
+		return writer.Error()
+	}
+}
+
+func togglRow(entry timesheetEntry) []string {
+	start := entry.session.Start
+	return []string{
+		entry.email,
+		start.Format(config_model.DateFormat),
+		start.Format(time.TimeOnly),
+		clockDuration(entry.session.Duration()),
+		entry.session.Project.Project(),
+		entry.session.Project.Client(),
+		entry.session.Project.Task(),
+		entry.description,
+	}
+}
+
+func clockifyRow(entry timesheetEntry) []string {
+	start, end := entry.session.Start, entry.session.End
+	return []string{
+		entry.session.Project.Project(),
+		entry.session.Project.Client(),
+		entry.description,
+		entry.session.Project.Task(),
+		entry.email,
+		start.Format(config_model.DateFormat),
+		start.Format(time.TimeOnly),
+		end.Format(config_model.DateFormat),
+		end.Format(time.TimeOnly),
+		clockDuration(entry.session.Duration()),
+	}
+}
+
+func harvestRow(entry timesheetEntry) []string {
+	task := entry.session.Project.Task()
+	if task == "" {
+		task = DefaultHarvestTask
+	}
+	return []string{
+		entry.session.Start.Format(config_model.DateFormat),
+		entry.session.Project.Client(),
+		entry.session.Project.Project(),
+		task,
+		entry.description,
+		strconv.FormatFloat(entry.session.Duration().Hours(), 'f', 2, 64),
+		entry.firstName,
+		entry.lastName,
+	}
+}
+
+// clockDuration formats d as HH:MM:SS, hours can go past 24.
+func clockDuration(d time.Duration) string {
+	seconds := int64(d.Round(time.Second) / time.Second)
+	return fmt.Sprintf("%02d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+}
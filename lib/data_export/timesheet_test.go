@@ -0,0 +1,98 @@
+package data_export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/HideyoshiNakazone/tracko/lib/config_model"
+	"github.com/HideyoshiNakazone/tracko/lib/repo"
+)
+
+func Test_Write_Timesheets(t *testing.T) {
+	zone := time.FixedZone("", -3*60*60)
+	base := time.Date(2025, 1, 1, 9, 0, 0, 0, zone)
+	records := []repo.CommitRecord{
+		{RepoPath: "/src/backend", Hash: "a", When: base, Message: "feat: users"},
+		{RepoPath: "/src/frontend", Hash: "b", When: base.Add(40 * time.Minute), Message: "feat: users page"},
+		{RepoPath: "/src/backend", Hash: "c", When: base.Add(75*time.Minute + 30*time.Second), Message: "fix: users, roles"},
+		{RepoPath: "/src/blog", Hash: "d", When: base.Add(2 * time.Hour), Message: "docs: post"},
+	}
+
+	cfg, err := config_model.NewConfigBuilder().
+		WithTrackedAuthor("Test User", []string{"test@example.com", "test@work.com"}).
+		WithTargetRepo("target", "Test User", "test@personal.example.com").
+		WithSessions(time.Hour, 30*time.Minute).
+		WithProject("backend", "Acme", "Platform", "Backend").
+		WithProject("frontend", "Acme", "Platform", "Backend").
+		Build()
+	if err != nil {
+		t.Fatalf("Failed to build config: %v", err)
+	}
+
+	tests := []struct {
+		format   string
+		expected [][]string
+	}{
+		{
+			FormatToggl,
+			[][]string{
+				TogglColumns,
+				{"test@example.com", "2025-01-01", "08:30:00", "01:45:30", "Platform", "Acme", "Backend", "feat: users; feat: users page; fix: users, roles"},
+				{"test@example.com", "2025-01-01", "10:30:00", "00:30:00", "blog", "", "", "docs: post"},
+			},
+		},
+		{
+			FormatClockify,
+			[][]string{
+				ClockifyColumns,
+				{"Platform", "Acme", "feat: users; feat: users page; fix: users, roles", "Backend", "test@example.com", "2025-01-01", "08:30:00", "2025-01-01", "10:15:30", "01:45:30"},
+				{"blog", "", "docs: post", "", "test@example.com", "2025-01-01", "10:30:00", "2025-01-01", "11:00:00", "00:30:00"},
+			},
+		},
+		{
+			FormatHarvest,
+			[][]string{
+				HarvestColumns,
+				{"2025-01-01", "Acme", "Platform", "Backend", "feat: users; feat: users page; fix: users, roles", "1.76", "Test", "User"},
+				{"2025-01-01", "", "blog", DefaultHarvestTask, "docs: post", "0.50", "Test", "User"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var buffer bytes.Buffer
+			if err := Write(tt.format, &buffer, cfg, records, Options{}); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+
+			rows, err := csv.NewReader(&buffer).ReadAll()
+			if err != nil {
+				t.Fatalf("Failed to read CSV: %v", err)
+			}
+			if !reflect.DeepEqual(rows, tt.expected) {
+				t.Errorf("Expected rows %q, got %q", tt.expected, rows)
+			}
+		})
+	}
+}
+
+func Test_ClockDuration(t *testing.T) {
+	tests := []struct {
+		duration time.Duration
+		want     string
+	}{
+		{0, "00:00:00"},
+		{90*time.Minute + 500*time.Millisecond, "01:30:01"},
+		{26*time.Hour + 5*time.Second, "26:00:05"},
+	}
+
+	for _, tt := range tests {
+		if got := clockDuration(tt.duration); got != tt.want {
+			t.Errorf("clockDuration(%v) = %q, want %q", tt.duration, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,262 @@
+package export_handler
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v6"
+	"github.com/go-git/go-git/v6/plumbing"
+	"github.com/go-git/go-git/v6/plumbing/object"
+
+	"github.com/HideyoshiNakazone/tracko/lib/config_model"
+	"github.com/HideyoshiNakazone/tracko/lib/internal_errors"
+	"github.com/HideyoshiNakazone/tracko/lib/repo"
+)
+
+// Leak kinds, what a finding of the audit gives away.
+const (
+	LeakSourceHash = "source-hash"
+	LeakRepoPath   = "repo-path"
+	LeakEmail      = "email"
+	LeakTerm       = "term"
+)
+
+// Leak locations inside a target commit, files are reported as "file <path>".
+const (
+	LeakInMessage   = "message"
+	LeakInTrailer   = "trailer"
+	LeakInAuthor    = "author"
+	LeakInCommitter = "committer"
+)
+
+// binarySniffLen is how much of a file is looked at for a NUL byte, like git
+// does to tell binary files apart.
+const binarySniffLen = 8000
+
+var (
+	hexTokenPattern = regexp.MustCompile(`\b[0-9a-f]{7,40}\b`)
+	trailerPattern  = regexp.MustCompile(`^[A-Za-z0-9-]+: `)
+)
+
+// Leak is something found in the target history that points back at the
+// tracked repositories, Commit is the target commit it was found in.
+type Leak struct {
+	Commit   string
+	Location string
+	Kind     string
+	Match    string
+}
+
+// AuditResult is the outcome of auditing the history of a target branch.
+type AuditResult struct {
+	Target  string
+	Commits int
+	Files   int
+	Leaks   []Leak
+}
+
+// auditRules is what the audit looks for, built once from the config.
+type auditRules struct {
+	sourceHashes []string
+	repoPaths    []string
+	emails       []string
+	terms        []string
+}
+
+// Audit scans the whole history of the target branch, as it would be pushed,
+// for anything that leaks the tracked repositories: hashes of their commits,
+// their paths on disk, the tracked emails and the sensitive terms of the
+// config. Commit messages, trailers, authors and the content of every text
+// file are scanned, a file is reported once, in the oldest commit holding it.
+func Audit(cfg *config_model.ConfigModel, target config_model.ConfigTargetModel) (*AuditResult, error) {
+	if target.Layout().Partitioned() {
+		return nil, internal_errors.ErrPartitionedTarget
+	}
+
+	r, err := git.PlainOpen(target.LocalPath())
+	if err != nil {
+		return nil, internal_errors.ErrInvalidTargetRepo
+	}
+
+	rules, err := newAuditRules(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &AuditResult{Target: target.Name(), Leaks: []Leak{}}
+
+	head, err := branchStart(r, target)
+	if err != nil {
+		return nil, err
+	}
+	if head == "" {
+		return result, nil
+	}
+
+	commits, err := branchCommits(r, head)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[plumbing.Hash]bool{}
+	for _, c := range commits {
+		result.Commits++
+		hash := c.Hash.String()
+
+		message, trailers := splitTrailers(c.Message)
+		result.Leaks = append(result.Leaks, rules.scan(hash, LeakInMessage, message)...)
+		result.Leaks = append(result.Leaks, rules.scan(hash, LeakInTrailer, trailers)...)
+		result.Leaks = append(result.Leaks, rules.scan(hash, LeakInAuthor, c.Author.Name+" <"+c.Author.Email+">")...)
+		result.Leaks = append(result.Leaks, rules.scan(hash, LeakInCommitter, c.Committer.Name+" <"+c.Committer.Email+">")...)
+
+		tree, err := c.Tree()
+		if err != nil {
+			return nil, err
+		}
+		err = tree.Files().ForEach(func(f *object.File) error {
+			if seen[f.Hash] {
+				return nil
+			}
+			seen[f.Hash] = true
+
+			content, binary, err := readText(f)
+			if err != nil || binary {
+				return err
+			}
+			result.Files++
+			result.Leaks = append(result.Leaks, rules.scan(hash, "file "+f.Name, content)...)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+func newAuditRules(cfg *config_model.ConfigModel) (*auditRules, error) {
+	rules := &auditRules{}
+
+	for _, repoPath := range cfg.TrackedRepos() {
+		hashes, err := repo.ReadCommitHashes(os.ExpandEnv(repoPath))
+		if err != nil {
+			return nil, err
+		}
+		rules.sourceHashes = append(rules.sourceHashes, hashes...)
+
+		// Relative paths are usually bare aliases, which are fine to publish
+		for _, path := range []string{repoPath, os.ExpandEnv(repoPath), filepath.Clean(os.ExpandEnv(repoPath))} {
+			if filepath.IsAbs(path) && !slices.Contains(rules.repoPaths, path) {
+				rules.repoPaths = append(rules.repoPaths, path)
+			}
+		}
+	}
+	sort.Strings(rules.sourceHashes)
+
+	for _, email := range cfg.TrackedAuthor().Emails() {
+		rules.emails = append(rules.emails, strings.ToLower(email))
+	}
+	for _, term := range cfg.SensitiveTerms() {
+		rules.terms = append(rules.terms, strings.ToLower(term))
+	}
+
+	return rules, nil
+}
+
+func (a *auditRules) scan(commit string, location string, text string) []Leak {
+	leaks := []Leak{}
+	if text == "" {
+		return leaks
+	}
+	found := func(kind string, match string) {
+		leaks = append(leaks, Leak{Commit: commit, Location: location, Kind: kind, Match: match})
+	}
+
+	lower := strings.ToLower(text)
+	for _, token := range hexTokenPattern.FindAllString(lower, -1) {
+		i := sort.SearchStrings(a.sourceHashes, token)
+		if i < len(a.sourceHashes) && strings.HasPrefix(a.sourceHashes[i], token) {
+			found(LeakSourceHash, token)
+		}
+	}
+	for _, path := range a.repoPaths {
+		if strings.Contains(text, path) {
+			found(LeakRepoPath, path)
+		}
+	}
+	for _, email := range a.emails {
+		if strings.Contains(lower, email) {
+			found(LeakEmail, email)
+		}
+	}
+	for _, term := range a.terms {
+		if strings.Contains(lower, term) {
+			found(LeakTerm, term)
+		}
+	}
+	return leaks
+}
+
+// branchCommits returns the commits reachable from head, oldest first.
+func branchCommits(r *git.Repository, head string) ([]*object.Commit, error) {
+	iter, err := r.Log(&git.LogOptions{From: plumbing.NewHash(head)})
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	commits := []*object.Commit{}
+	err = iter.ForEach(func(c *object.Commit) error {
+		commits = append(commits, c)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.Reverse(commits)
+	return commits, nil
+}
+
+// splitTrailers splits a commit message from its trailer block, the last
+// paragraph when every line of it is a "Key: value" trailer.
+func splitTrailers(message string) (string, string) {
+	message = strings.TrimRight(message, "\n")
+	i := strings.LastIndex(message, "\n\n")
+	if i == -1 {
+		return message, ""
+	}
+
+	for _, line := range strings.Split(message[i+2:], "\n") {
+		if !trailerPattern.MatchString(line) {
+			return message, ""
+		}
+	}
+	return message[:i], message[i+2:]
+}
+
+// readText returns the content of a file, or reports it as binary when it
+// holds a NUL byte early on.
+func readText(f *object.File) (string, bool, error) {
+	reader, err := f.Reader()
+	if err != nil {
+		return "", false, err
+	}
+	defer reader.Close()
+
+	content, err := io.ReadAll(reader)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", false, err
+	}
+	if bytes.IndexByte(content[:min(len(content), binarySniffLen)], 0) != -1 {
+		return "", true, nil
+	}
+	return string(content), false, nil
+}
@@ -0,0 +1,135 @@
+package export_handler
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/go-git/go-git/v6"
+	"github.com/go-git/go-git/v6/plumbing/object"
+
+	"github.com/HideyoshiNakazone/tracko/lib/config_model"
+	"github.com/HideyoshiNakazone/tracko/lib/internal_errors"
+)
+
+func Test_Audit(t *testing.T) {
+	sourcePath := prepareStrategyFixture(t)
+	targetPath := prepareTargetRepository(t)
+
+	cfg, err := config_model.NewConfigBuilder().
+		WithDBPath("/tmp/test.db").
+		WithTrackedAuthor("Test User", []string{"Test@Example.com"}).
+		WithTrackedRepos([]string{sourcePath}).
+		WithSensitiveTerms([]string{"Acme"}).
+		WithTarget(config_model.NewTargetBuilder(config_model.DefaultTargetName, targetPath).
+			WithAuthor("Personal", "me@personal.dev").
+			WithStrategy(config_model.StrategyDaily, 0).
+			WithContent(config_model.ContentJSONLedger)).
+		Build()
+	if err != nil {
+		t.Fatalf("Failed to build config: %v", err)
+	}
+	target := cfg.Targets()[0]
+
+	if _, err := ExportTarget(cfg, target); err != nil {
+		t.Fatalf("ExportTarget() error = %v", err)
+	}
+
+	result, err := Audit(cfg, target)
+	if err != nil {
+		t.Fatalf("Audit() error = %v", err)
+	}
+	if result.Commits != 3 || result.Files == 0 || len(result.Leaks) != 0 {
+		t.Fatalf("Expected a clean export, got %+v", result)
+	}
+
+	source, err := git.PlainOpen(sourcePath)
+	if err != nil {
+		t.Fatalf("Failed to open source repository: %v", err)
+	}
+	sourceHead, err := source.Head()
+	if err != nil {
+		t.Fatalf("Failed to read source head: %v", err)
+	}
+	shortHash := sourceHead.Hash().String()[:10]
+
+	leaky := commitManually(t, targetPath, "NOTES.md", "Ported from "+shortHash+" in "+sourcePath+"\nAsk test@example.com about ACME.\n")
+	// The same content again is reported once, where it was introduced
+	commitManually(t, targetPath, "COPY.md", "Ported from "+shortHash+" in "+sourcePath+"\nAsk test@example.com about ACME.\n")
+
+	result, err = Audit(cfg, target)
+	if err != nil {
+		t.Fatalf("Audit() error = %v", err)
+	}
+	expected := []Leak{
+		{Commit: leaky.String(), Location: "file NOTES.md", Kind: LeakSourceHash, Match: shortHash},
+		{Commit: leaky.String(), Location: "file NOTES.md", Kind: LeakRepoPath, Match: sourcePath},
+		{Commit: leaky.String(), Location: "file NOTES.md", Kind: LeakEmail, Match: "test@example.com"},
+		{Commit: leaky.String(), Location: "file NOTES.md", Kind: LeakTerm, Match: "acme"},
+	}
+	if result.Commits != 5 || !reflect.DeepEqual(result.Leaks, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, result.Leaks)
+	}
+}
+
+func Test_Audit_MessagesAndAuthors(t *testing.T) {
+	sourcePath := prepareStrategyFixture(t)
+	targetPath := prepareTargetRepository(t)
+
+	// A target authored as a tracked email leaks it in every commit
+	cfg := buildConfig(t, []string{sourcePath},
+		config_model.NewTargetBuilder(config_model.DefaultTargetName, targetPath).
+			WithAuthor("Test User", "test@example.com").
+			WithMessageTemplate("{{.Type}} in "+sourcePath).
+			WithStrategy(config_model.StrategyDaily, 0),
+	)
+	if _, err := ExportTarget(cfg, cfg.Targets()[0]); err != nil {
+		t.Fatalf("ExportTarget() error = %v", err)
+	}
+
+	r, err := git.PlainOpen(targetPath)
+	if err != nil {
+		t.Fatalf("Failed to open repository: %v", err)
+	}
+	wt, err := r.Worktree()
+	if err != nil {
+		t.Fatalf("Failed to open worktree: %v", err)
+	}
+	signature := &object.Signature{Name: "Maintainer", Email: "maintainer@example.com", When: fixtureBase.AddDate(0, 1, 0)}
+	message := "docs: credit\n\nCo-authored-by: Test User <test@example.com>\n"
+	if _, err := wt.Commit(message, &git.CommitOptions{Author: signature, Committer: signature, AllowEmptyCommits: true}); err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+
+	result, err := Audit(cfg, cfg.Targets()[0])
+	if err != nil {
+		t.Fatalf("Audit() error = %v", err)
+	}
+
+	locations := map[string]int{}
+	for _, leak := range result.Leaks {
+		locations[leak.Location+" "+leak.Kind]++
+	}
+	expected := map[string]int{
+		LeakInMessage + " " + LeakRepoPath: 3,
+		LeakInTrailer + " " + LeakEmail:    1,
+		LeakInAuthor + " " + LeakEmail:     3,
+		LeakInCommitter + " " + LeakEmail:  3,
+	}
+	if !reflect.DeepEqual(locations, expected) {
+		t.Errorf("Expected leaks %v, got %v", expected, locations)
+	}
+}
+
+func Test_Audit_PartitionedTarget(t *testing.T) {
+	sourcePath := prepareStrategyFixture(t)
+	cfg := buildConfig(t, []string{sourcePath},
+		config_model.NewTargetBuilder("github", prepareTargetRepository(t)).
+			WithAuthor("Test User", "test@personal.example.com").
+			WithLayout(config_model.LayoutSplitYear, config_model.LayoutIntoBranch),
+	)
+
+	if _, err := Audit(cfg, cfg.Targets()[0]); !errors.Is(err, internal_errors.ErrPartitionedTarget) {
+		t.Errorf("Expected ErrPartitionedTarget, got %v", err)
+	}
+}
@@ -0,0 +1,81 @@
+package export_handler
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/go-git/go-git/v6"
+	"github.com/go-git/go-git/v6/plumbing/transport"
+
+	"github.com/HideyoshiNakazone/tracko/lib/config_model"
+	"github.com/HideyoshiNakazone/tracko/lib/internal_errors"
+	"github.com/HideyoshiNakazone/tracko/lib/push_handler"
+)
+
+// OpenTarget opens the target repository, bootstrapping it when it is
+// missing: targets with a URL are cloned from it, with the push remote as
+// the remote name, and targets with create set get an empty repository.
+func OpenTarget(target config_model.ConfigTargetModel) (*git.Repository, error) {
+	targetPath := target.LocalPath()
+
+	r, err := git.PlainOpen(targetPath)
+	if err == nil {
+		return r, nil
+	}
+	if !errors.Is(err, git.ErrRepositoryNotExists) {
+		return nil, internal_errors.ErrInvalidTargetRepo
+	}
+
+	switch {
+	case target.URL() != "":
+		return cloneTarget(target, targetPath)
+	case target.Create():
+		if err := os.MkdirAll(targetPath, 0o755); err != nil {
+			return nil, err
+		}
+		return git.PlainInit(targetPath, false)
+	default:
+		return nil, internal_errors.ErrInvalidTargetRepo
+	}
+}
+
+func cloneTarget(target config_model.ConfigTargetModel, targetPath string) (*git.Repository, error) {
+	endpoint, err := transport.NewEndpoint(target.URL())
+	if err != nil {
+		return nil, fmt.Errorf("invalid url for target %q: %w", target.Name(), err)
+	}
+
+	auth, credential, err := push_handler.ResolveAuth(target.Push(), endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	_, statErr := os.Stat(targetPath)
+	existed := statErr == nil
+
+	r, err := git.PlainClone(targetPath, &git.CloneOptions{
+		URL:        target.URL(),
+		RemoteName: target.Push().Remote(),
+		Auth:       auth,
+	})
+	switch {
+	case errors.Is(err, transport.ErrEmptyRemoteRepository):
+		// A new remote without commits, the first export creates its branch
+	case err != nil:
+		if credential != nil && (errors.Is(err, transport.ErrAuthenticationRequired) || errors.Is(err, transport.ErrAuthorizationFailed)) {
+			credential.Reject()
+		}
+		// A failed clone leaves a partial repository behind, which would be
+		// opened as is by the next export
+		if !existed {
+			os.RemoveAll(targetPath)
+		}
+		return nil, fmt.Errorf("failed to clone %s: %w", target.URL(), err)
+	}
+
+	if credential != nil {
+		credential.Approve()
+	}
+	return r, nil
+}
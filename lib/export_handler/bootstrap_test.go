@@ -0,0 +1,203 @@
+package export_handler
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/go-git/go-git/v6"
+	"github.com/go-git/go-git/v6/plumbing"
+
+	"github.com/HideyoshiNakazone/tracko/lib/config_model"
+	"github.com/HideyoshiNakazone/tracko/lib/internal_errors"
+)
+
+func Test_ExportTarget_CreatesMissingTarget(t *testing.T) {
+	sourcePath := prepareStrategyFixture(t)
+	targetPath := filepath.Join(t.TempDir(), "mirror")
+
+	missing := buildConfig(t, []string{sourcePath}, config_model.NewTargetBuilder(config_model.DefaultTargetName, targetPath).WithAuthor("Test User", "test@personal.example.com"))
+	if _, err := ExportTarget(missing, missing.Targets()[0]); !errors.Is(err, internal_errors.ErrInvalidTargetRepo) {
+		t.Fatalf("Expected ErrInvalidTargetRepo without create, got %v", err)
+	}
+	if _, err := os.Stat(targetPath); !os.IsNotExist(err) {
+		t.Errorf("Expected the target to stay missing, got %v", err)
+	}
+
+	cfg := buildConfig(t, []string{sourcePath},
+		config_model.NewTargetBuilder(config_model.DefaultTargetName, targetPath).
+			WithAuthor("Test User", "test@personal.example.com").
+			WithCreate(true).
+			WithStrategy(config_model.StrategyDaily, 0),
+	)
+
+	// Planning a target that would be created writes nothing either
+	plan, err := PlanTarget(cfg, cfg.Targets()[0])
+	if err != nil {
+		t.Fatalf("PlanTarget() error = %v", err)
+	}
+	if plan.Commits != 3 {
+		t.Errorf("Expected 3 planned commits, got %+v", plan)
+	}
+	if _, err := os.Stat(targetPath); !os.IsNotExist(err) {
+		t.Errorf("Expected the plan to leave the target missing, got %v", err)
+	}
+
+	result, err := ExportTarget(cfg, cfg.Targets()[0])
+	if err != nil {
+		t.Fatalf("ExportTarget() error = %v", err)
+	}
+	if result.Commits != 3 || len(logSubjects(t, targetPath, "")) != 3 {
+		t.Errorf("Expected 3 commits in the created target, got %+v", result)
+	}
+}
+
+func Test_ExportTarget_ClonesFromURL(t *testing.T) {
+	sourcePath := prepareStrategyFixture(t)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	// The remote already holds an export on the mirror branch
+	upstreamPath := prepareTargetRepository(t)
+	upstream := buildConfig(t, []string{sourcePath},
+		config_model.NewTargetBuilder("upstream", upstreamPath).
+			WithAuthor("Test User", "test@personal.example.com").
+			WithBranch("mirror").
+			WithStrategy(config_model.StrategyDaily, 0).
+			WithDateRange(fixtureBase, fixtureBase),
+	)
+	if _, err := ExportTarget(upstream, upstream.Targets()[0]); err != nil {
+		t.Fatalf("ExportTarget() error = %v", err)
+	}
+
+	cfg := buildConfig(t, []string{sourcePath},
+		config_model.NewTargetBuilder("github", "").
+			WithAuthor("Test User", "test@personal.example.com").
+			WithURL(upstreamPath).
+			WithBranch("mirror").
+			WithStrategy(config_model.StrategyDaily, 0).
+			WithPushAuth(config_model.PushAuthNone),
+	)
+	target := cfg.Targets()[0]
+	if expected := filepath.Join(config_model.TargetCacheDir(), "github"); target.LocalPath() != expected {
+		t.Errorf("Expected the target in %s, got %s", expected, target.LocalPath())
+	}
+
+	if _, err := PlanTarget(cfg, target); !errors.Is(err, internal_errors.ErrInvalidTargetRepo) {
+		t.Errorf("Expected planning an uncloned target to fail, got %v", err)
+	}
+
+	result, err := ExportTarget(cfg, target)
+	if err != nil {
+		t.Fatalf("ExportTarget() error = %v", err)
+	}
+
+	// The local branch continues the remote one instead of starting over
+	if result.Skipped != 4 || result.Commits != 2 {
+		t.Errorf("Expected 4 skipped and 2 new commits, got %+v", result)
+	}
+	if subjects := logSubjects(t, target.LocalPath(), "mirror"); len(subjects) != 3 {
+		t.Errorf("Expected 3 commits on the mirror branch, got %v", subjects)
+	}
+
+	r, err := git.PlainOpen(target.LocalPath())
+	if err != nil {
+		t.Fatalf("Failed to open the clone: %v", err)
+	}
+	remote, err := r.Remote(config_model.DefaultPushRemote)
+	if err != nil || !reflect.DeepEqual(remote.Config().URLs, []string{upstreamPath}) {
+		t.Errorf("Expected the clone to push to %s, got %v (%v)", upstreamPath, remote, err)
+	}
+}
+
+func Test_ExportTarget_ClonesEmptyRemote(t *testing.T) {
+	sourcePath := prepareStrategyFixture(t)
+	barePath := t.TempDir()
+	if _, err := git.PlainInit(barePath, true); err != nil {
+		t.Fatalf("Failed to init remote repository: %v", err)
+	}
+
+	targetPath := filepath.Join(t.TempDir(), "mirror")
+	cfg := buildConfig(t, []string{sourcePath},
+		config_model.NewTargetBuilder(config_model.DefaultTargetName, targetPath).
+			WithAuthor("Test User", "test@personal.example.com").
+			WithURL(barePath).
+			WithStrategy(config_model.StrategyDaily, 0).
+			WithPushAuth(config_model.PushAuthNone),
+	)
+
+	result, err := ExportTarget(cfg, cfg.Targets()[0])
+	if err != nil {
+		t.Fatalf("ExportTarget() error = %v", err)
+	}
+	if result.Commits != 3 || len(logSubjects(t, targetPath, "")) != 3 {
+		t.Errorf("Expected 3 commits in the cloned target, got %+v", result)
+	}
+}
+
+func Test_ExportTarget_OrphanBranch(t *testing.T) {
+	sourcePath := prepareStrategyFixture(t)
+
+	for _, orphan := range []bool{false, true} {
+		targetPath := prepareTargetRepository(t)
+		commitManually(t, targetPath, "README.md", "# Mirror\n")
+		os.MkdirAll(filepath.Join(targetPath, "docs"), 0o755)
+		commitManually(t, targetPath, "docs/index.md", "# Docs\n")
+
+		cfg := buildConfig(t, []string{sourcePath},
+			config_model.NewTargetBuilder(config_model.DefaultTargetName, targetPath).
+				WithAuthor("Test User", "test@personal.example.com").
+				WithBranch("mirror").
+				WithOrphanBranch(orphan).
+				WithStrategy(config_model.StrategyDaily, 0).
+				WithContent(config_model.ContentJSONLedger),
+		)
+
+		plan, err := PlanTarget(cfg, cfg.Targets()[0])
+		if err != nil {
+			t.Fatalf("PlanTarget() error = %v", err)
+		}
+		if _, err := ExportTarget(cfg, cfg.Targets()[0]); err != nil {
+			t.Fatalf("ExportTarget() error = %v", err)
+		}
+
+		commits := len(logSubjects(t, targetPath, "mirror"))
+		files := headFiles(t, targetPath)
+		_, hasReadme := files["README.md"]
+		_, hasDocs := files["docs/index.md"]
+		_, hasLedger := files[JSONLedgerPath]
+
+		if orphan {
+			if commits != 3 || hasReadme || hasDocs || !hasLedger || len(plan.Existing) != 0 {
+				t.Errorf("Expected an orphan branch with only the ledger, got %d commits and files %v", commits, files)
+			}
+			if _, err := os.Stat(filepath.Join(targetPath, "docs")); !os.IsNotExist(err) {
+				t.Errorf("Expected the docs directory to be removed, got %v", err)
+			}
+		} else if commits != 5 || !hasReadme || !hasDocs || !hasLedger || len(plan.Existing) != 2 {
+			t.Errorf("Expected a branch from the current head, got %d commits and files %v", commits, files)
+		}
+	}
+}
+
+func Test_ExportTarget_DirtyWorktree(t *testing.T) {
+	cfg, targetPath := prepareExportFixture(t)
+	commitManually(t, targetPath, "README.md", "# Mirror\n")
+
+	if err := os.WriteFile(filepath.Join(targetPath, "README.md"), []byte("# Changed\n"), 0o644); err != nil {
+		t.Fatalf("Failed to change README.md: %v", err)
+	}
+
+	if _, err := ExportTarget(cfg, cfg.Targets()[0]); !errors.Is(err, internal_errors.ErrDirtyTargetRepo) {
+		t.Fatalf("Expected ErrDirtyTargetRepo, got %v", err)
+	}
+	if subjects := logSubjects(t, targetPath, ""); len(subjects) != 1 {
+		t.Errorf("Expected the target to be left untouched, got %v", subjects)
+	}
+
+	r, _ := git.PlainOpen(targetPath)
+	if head, err := r.Head(); err != nil || head.Name() != plumbing.Master {
+		t.Errorf("Expected the target to stay on master, got %v (%v)", head, err)
+	}
+}
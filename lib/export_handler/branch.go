@@ -0,0 +1,113 @@
+package export_handler
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/go-git/go-git/v6"
+	"github.com/go-git/go-git/v6/plumbing"
+	"github.com/go-git/go-git/v6/plumbing/format/index"
+
+	"github.com/HideyoshiNakazone/tracko/lib/config_model"
+)
+
+// checkoutBranch switches the target repository to the target branch. A
+// missing branch is created from the branch of the same name on the push
+// remote when there is one, otherwise without history for orphan targets or
+// from the current head. An empty branch keeps the branch that is currently
+// checked out.
+func checkoutBranch(r *git.Repository, wt *git.Worktree, target config_model.ConfigTargetModel) error {
+	branch := target.Branch()
+	if branch == "" {
+		return nil
+	}
+	branchRef := plumbing.NewBranchReferenceName(branch)
+
+	head, err := r.Head()
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		// Empty repository, the first commit creates the branch
+		return r.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, branchRef))
+	}
+	if err != nil {
+		return err
+	}
+
+	if head.Name() == branchRef {
+		return nil
+	}
+
+	_, err = r.Reference(branchRef, false)
+	switch {
+	case errors.Is(err, plumbing.ErrReferenceNotFound):
+	case err != nil:
+		return err
+	default:
+		return wt.Checkout(&git.CheckoutOptions{Branch: branchRef})
+	}
+
+	remoteRef, err := r.Reference(plumbing.NewRemoteReferenceName(target.Push().Remote(), branch), true)
+	switch {
+	case errors.Is(err, plumbing.ErrReferenceNotFound):
+	case err != nil:
+		return err
+	default:
+		return wt.Checkout(&git.CheckoutOptions{Branch: branchRef, Hash: remoteRef.Hash(), Create: true})
+	}
+
+	if target.Orphan() {
+		return checkoutOrphan(r, wt, branchRef)
+	}
+	return wt.Checkout(&git.CheckoutOptions{Branch: branchRef, Create: true})
+}
+
+// checkoutOrphan points HEAD at a branch without commits and empties the
+// index and the worktree, so that the first commit of the branch starts from
+// an empty tree. Only tracked files are removed, the worktree is clean when
+// this is called.
+func checkoutOrphan(r *git.Repository, wt *git.Worktree, branchRef plumbing.ReferenceName) error {
+	idx, err := r.Storer.Index()
+	if err != nil {
+		return err
+	}
+
+	root := wt.Filesystem.Root()
+	for _, entry := range idx.Entries {
+		if err := os.Remove(filepath.Join(root, filepath.FromSlash(entry.Name))); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		removeEmptyParents(root, filepath.Dir(filepath.Join(root, filepath.FromSlash(entry.Name))))
+	}
+
+	if err := r.Storer.SetIndex(&index.Index{Version: idx.Version}); err != nil {
+		return err
+	}
+	return r.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, branchRef))
+}
+
+// branchStart returns the commit the target branch is at once checkoutBranch
+// ran, without checking it out. It is empty when the branch has no history.
+func branchStart(r *git.Repository, target config_model.ConfigTargetModel) (string, error) {
+	branch := target.Branch()
+	if branch == "" {
+		return headHash(r)
+	}
+
+	for _, name := range []plumbing.ReferenceName{
+		plumbing.NewBranchReferenceName(branch),
+		plumbing.NewRemoteReferenceName(target.Push().Remote(), branch),
+	} {
+		ref, err := r.Reference(name, true)
+		if err == nil {
+			return ref.Hash().String(), nil
+		}
+		if !errors.Is(err, plumbing.ErrReferenceNotFound) {
+			return "", err
+		}
+	}
+
+	if target.Orphan() {
+		return "", nil
+	}
+	return headHash(r)
+}
@@ -0,0 +1,197 @@
+package export_handler
+
+import (
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v6"
+
+	"github.com/HideyoshiNakazone/tracko/lib/config_model"
+)
+
+// Files written by the content strategies, relative to the target root.
+const (
+	ActivityLogDir = "activity"
+	JSONLedgerPath = "ledger.json"
+	CSVLedgerPath  = "ledger.csv"
+)
+
+// LedgerColumns are the columns of the CSV ledger, in order. They match the
+// keys of the JSON ledger entries.
+var LedgerColumns = []string{"date", "repo", "type", "additions", "deletions", "files_changed", "source_id"}
+
+// LedgerEntry is a single target commit in the ledger, it only holds
+// aggregate numbers and never any source content. Like the target commits
+// it follows the strategy, the number of source commits is never written.
+type LedgerEntry struct {
+	Date         string `json:"date"`
+	Repo         string `json:"repo"`
+	Type         string `json:"type"`
+	Additions    int    `json:"additions"`
+	Deletions    int    `json:"deletions"`
+	FilesChanged int    `json:"files_changed"`
+	SourceID     string `json:"source_id"`
+}
+
+func newLedgerEntry(group exportGroup, repoNames bool) LedgerEntry {
+	data := group.templateData()
+
+	labels := []string{}
+	for _, repoGroup := range group.byRepo() {
+		labels = append(labels, repoLabel(repoGroup.last().RepoPath, repoNames))
+	}
+
+	return LedgerEntry{
+		Date:         data.Date.Format(time.RFC3339),
+		Repo:         strings.Join(labels, ", "),
+		Type:         data.Type,
+		Additions:    data.Additions,
+		Deletions:    data.Deletions,
+		FilesChanged: data.FilesChanged,
+		SourceID:     data.SourceID,
+	}
+}
+
+func (e LedgerEntry) row() []string {
+	return []string{
+		e.Date,
+		e.Repo,
+		e.Type,
+		strconv.Itoa(e.Additions),
+		strconv.Itoa(e.Deletions),
+		strconv.Itoa(e.FilesChanged),
+		e.SourceID,
+	}
+}
+
+// repoLabel names a source repo in the content files, by its alias when
+// repoNames is set and otherwise by an opaque label derived from its path.
+func repoLabel(repoPath string, repoNames bool) string {
+	if repoNames {
+		return filepath.Base(repoPath)
+	}
+	sum := sha256.Sum256([]byte("tracko-repo:" + repoPath))
+	return "repo-" + hex.EncodeToString(sum[:4])
+}
+
+// writeContent updates the files of the target worktree for a group about to
+// be mirrored and stages them, according to the content strategy of target.
+func writeContent(wt *git.Worktree, targetPath string, target config_model.ConfigTargetModel, group exportGroup) error {
+	var paths []string
+	var err error
+
+	switch target.Content() {
+	case config_model.ContentActivityLog:
+		paths, err = appendActivityLog(targetPath, group, target.RepoNames())
+	case config_model.ContentJSONLedger:
+		paths, err = []string{JSONLedgerPath}, appendJSONLedger(filepath.Join(targetPath, JSONLedgerPath), newLedgerEntry(group, target.RepoNames()))
+	case config_model.ContentCSVLedger:
+		paths, err = []string{CSVLedgerPath}, appendCSVLedger(filepath.Join(targetPath, CSVLedgerPath), newLedgerEntry(group, target.RepoNames()))
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, path := range paths {
+		if _, err := wt.Add(filepath.ToSlash(path)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// appendActivityLog appends a line to the activity log of every repo in the
+// group, a group spanning several repos writes one line per repo.
+func appendActivityLog(targetPath string, group exportGroup, repoNames bool) ([]string, error) {
+	var paths []string
+	for _, repoGroup := range group.byRepo() {
+		data := repoGroup.templateData()
+		path := filepath.Join(ActivityLogDir, repoLabel(repoGroup.last().RepoPath, repoNames)+".log")
+		line := fmt.Sprintf("%s +%d -%d %d files\n",
+			data.Date.Format(time.RFC3339), data.Additions, data.Deletions, data.FilesChanged)
+
+		if err := appendFile(filepath.Join(targetPath, path), []byte(line)); err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+func appendJSONLedger(path string, entry LedgerEntry) error {
+	entries := []LedgerEntry{}
+
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &entries); err != nil {
+			return fmt.Errorf("invalid ledger %s: %w", path, err)
+		}
+	}
+
+	data, err = encodeJSONLedger(append(entries, entry))
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+func encodeJSONLedger(entries []LedgerEntry) ([]byte, error) {
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+func appendCSVLedger(path string, entry LedgerEntry) error {
+	_, err := os.Stat(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	rows := [][]string{entry.row()}
+	if errors.Is(err, os.ErrNotExist) {
+		rows = append([][]string{LedgerColumns}, rows...)
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	if err := writer.WriteAll(rows); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+func appendFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err := file.Write(data); err != nil {
+		return err
+	}
+	return file.Close()
+}
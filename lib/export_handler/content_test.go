@@ -0,0 +1,223 @@
+package export_handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v6"
+	"github.com/go-git/go-git/v6/plumbing/object"
+
+	"github.com/HideyoshiNakazone/tracko/lib/config_model"
+	"github.com/HideyoshiNakazone/tracko/lib/repo"
+)
+
+func prepareContentFixture(t *testing.T) (string, string) {
+	t.Helper()
+
+	backendPath := prepareSourceRepository(t, []repo.TestCommit{
+		{Author: "Test User", Email: "test@example.com", Message: "feat: api", When: fixtureBase, Files: map[string]string{
+			"main.go": "package main\n",
+		}},
+		{Author: "Test User", Email: "test@example.com", Message: "fix: api", When: fixtureBase.Add(time.Hour), Files: map[string]string{
+			"main.go": "package main\n\nfunc main() {}\n",
+		}},
+	})
+	frontendPath := prepareSourceRepository(t, []repo.TestCommit{
+		{Author: "Test User", Email: "test@example.com", Message: "feat: page", When: fixtureBase.Add(2 * time.Hour)},
+	})
+	return backendPath, frontendPath
+}
+
+// headFiles returns the content of every file in the head tree of the target
+// and checks that the export left the worktree clean.
+func headFiles(t *testing.T, repoPath string) map[string]string {
+	t.Helper()
+
+	r, err := git.PlainOpen(repoPath)
+	if err != nil {
+		t.Fatalf("Failed to open repository: %v", err)
+	}
+
+	wt, err := r.Worktree()
+	if err != nil {
+		t.Fatalf("Failed to open worktree: %v", err)
+	}
+	status, err := wt.Status()
+	if err != nil {
+		t.Fatalf("Failed to read status: %v", err)
+	}
+	if !status.IsClean() {
+		t.Errorf("Expected a clean worktree, got %v", status)
+	}
+
+	ref, err := r.Head()
+	if err != nil {
+		t.Fatalf("Failed to read head: %v", err)
+	}
+	commit, err := r.CommitObject(ref.Hash())
+	if err != nil {
+		t.Fatalf("Failed to read head commit: %v", err)
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		t.Fatalf("Failed to read head tree: %v", err)
+	}
+
+	files := map[string]string{}
+	err = tree.Files().ForEach(func(file *object.File) error {
+		content, err := file.Contents()
+		if err != nil {
+			return err
+		}
+		files[file.Name] = content
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to read head files: %v", err)
+	}
+	return files
+}
+
+func exportContent(t *testing.T, content string, strategy string, repoNames bool, repos ...string) string {
+	t.Helper()
+
+	targetPath := prepareTargetRepository(t)
+	cfg := buildConfig(t, repos,
+		config_model.NewTargetBuilder(config_model.DefaultTargetName, targetPath).
+			WithAuthor("Test User", "test@personal.example.com").
+			WithStrategy(strategy, 0).
+			WithContent(content).
+			WithRepoNames(repoNames),
+	)
+
+	if _, err := ExportTarget(cfg, cfg.Targets()[0]); err != nil {
+		t.Fatalf("ExportTarget() error = %v", err)
+	}
+	return targetPath
+}
+
+func Test_ExportTarget_EmptyContent(t *testing.T) {
+	backendPath, frontendPath := prepareContentFixture(t)
+	targetPath := exportContent(t, config_model.ContentEmpty, config_model.StrategyOneToOne, false, backendPath, frontendPath)
+
+	if files := headFiles(t, targetPath); len(files) != 0 {
+		t.Errorf("Expected no files in target, got %v", files)
+	}
+}
+
+func Test_ExportTarget_ActivityLogContent(t *testing.T) {
+	backendPath, frontendPath := prepareContentFixture(t)
+	activityLog := func(label string) string {
+		return filepath.ToSlash(filepath.Join(ActivityLogDir, label+".log"))
+	}
+	backendLog, frontendLog := activityLog(repoLabel(backendPath, false)), activityLog(repoLabel(frontendPath, false))
+
+	tests := []struct {
+		name      string
+		strategy  string
+		repoNames bool
+		expected  map[string]string
+	}{
+		{
+			name:     "One to one",
+			strategy: config_model.StrategyOneToOne,
+			expected: map[string]string{
+				backendLog: "2025-01-01T10:00:00Z +1 -0 1 files\n" +
+					"2025-01-01T11:00:00Z +2 -0 1 files\n",
+				frontendLog: "2025-01-01T12:00:00Z +0 -0 0 files\n",
+			},
+		},
+		{
+			name:     "Daily spanning repos",
+			strategy: config_model.StrategyDaily,
+			expected: map[string]string{
+				backendLog:  "2025-01-01T11:00:00Z +3 -0 2 files\n",
+				frontendLog: "2025-01-01T12:00:00Z +0 -0 0 files\n",
+			},
+		},
+		{
+			name:      "Repo names",
+			strategy:  config_model.StrategyDaily,
+			repoNames: true,
+			expected: map[string]string{
+				activityLog(filepath.Base(backendPath)):  "2025-01-01T11:00:00Z +3 -0 2 files\n",
+				activityLog(filepath.Base(frontendPath)): "2025-01-01T12:00:00Z +0 -0 0 files\n",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			targetPath := exportContent(t, config_model.ContentActivityLog, tt.strategy, tt.repoNames, backendPath, frontendPath)
+
+			if files := headFiles(t, targetPath); !reflect.DeepEqual(files, tt.expected) {
+				t.Errorf("Expected files %v, got %v", tt.expected, files)
+			}
+		})
+	}
+}
+
+func Test_ExportTarget_JSONLedgerContent(t *testing.T) {
+	backendPath, frontendPath := prepareContentFixture(t)
+	targetPath := exportContent(t, config_model.ContentJSONLedger, config_model.StrategyOneToOne, false, backendPath, frontendPath)
+
+	files := headFiles(t, targetPath)
+	if len(files) != 1 {
+		t.Fatalf("Expected only the ledger in target, got %v", files)
+	}
+
+	var entries []LedgerEntry
+	if err := json.Unmarshal([]byte(files[JSONLedgerPath]), &entries); err != nil {
+		t.Fatalf("Failed to parse ledger: %v", err)
+	}
+
+	expected := []LedgerEntry{
+		{Date: "2025-01-01T10:00:00Z", Repo: repoLabel(backendPath, false), Type: "feat", Additions: 1, FilesChanged: 1},
+		{Date: "2025-01-01T11:00:00Z", Repo: repoLabel(backendPath, false), Type: "fix", Additions: 2, FilesChanged: 1},
+		{Date: "2025-01-01T12:00:00Z", Repo: repoLabel(frontendPath, false), Type: "feat"},
+	}
+	if len(entries) != len(expected) {
+		t.Fatalf("Expected %d ledger entries, got %d", len(expected), len(entries))
+	}
+	for i := range expected {
+		if entries[i].SourceID == "" {
+			t.Errorf("At index %d: expected a source id", i)
+		}
+		entries[i].SourceID = ""
+		if entries[i] != expected[i] {
+			t.Errorf("At index %d: expected %+v, got %+v", i, expected[i], entries[i])
+		}
+	}
+}
+
+func Test_ExportTarget_CSVLedgerContent(t *testing.T) {
+	backendPath, frontendPath := prepareContentFixture(t)
+	targetPath := exportContent(t, config_model.ContentCSVLedger, config_model.StrategyDaily, true, backendPath, frontendPath)
+
+	files := headFiles(t, targetPath)
+	rows, err := csv.NewReader(strings.NewReader(files[CSVLedgerPath])).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to parse ledger: %v", err)
+	}
+
+	if len(rows) != 2 {
+		t.Fatalf("Expected a header and 1 row, got %v", rows)
+	}
+	if !reflect.DeepEqual(rows[0], LedgerColumns) {
+		t.Errorf("Expected header %v, got %v", LedgerColumns, rows[0])
+	}
+
+	expected := []string{
+		"2025-01-01T12:00:00Z",
+		filepath.Base(backendPath) + ", " + filepath.Base(frontendPath),
+		"", "3", "0", "2",
+	}
+	if got := rows[1][:len(expected)]; !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected row %v, got %v", expected, got)
+	}
+}
@@ -0,0 +1,209 @@
+package export_handler
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"text/template"
+
+	"github.com/go-git/go-git/v6"
+	"github.com/go-git/go-git/v6/plumbing"
+	"github.com/go-git/go-git/v6/plumbing/object"
+
+	"github.com/HideyoshiNakazone/tracko/lib/commit_message"
+	"github.com/HideyoshiNakazone/tracko/lib/config_model"
+	"github.com/HideyoshiNakazone/tracko/lib/internal_errors"
+	"github.com/HideyoshiNakazone/tracko/lib/repo"
+	"github.com/HideyoshiNakazone/tracko/lib/sign_handler"
+)
+
+// ExportResult counts source commits, Mirrored and Skipped, and the target
+// commits they were written as, which differ for aggregating strategies.
+// Readme reports whether the readme and badge were regenerated.
+type ExportResult struct {
+	Target   string
+	Mirrored int
+	Skipped  int
+	Commits  int
+	Readme   bool
+}
+
+// Export mirrors the commits of the tracked author into every configured
+// target, or into every partition of partitioned targets.
+func Export(cfg *config_model.ConfigModel) ([]*ExportResult, error) {
+	var results []*ExportResult
+	for _, target := range cfg.Targets() {
+		partitions, err := Partitions(cfg, target)
+		if err != nil {
+			return results, fmt.Errorf("target %q: %w", target.Name(), err)
+		}
+
+		for _, partition := range partitions {
+			result, err := ExportTarget(cfg, partition)
+			if err != nil {
+				return results, fmt.Errorf("target %q: %w", partition.Name(), err)
+			}
+			results = append(results, result)
+		}
+	}
+	return results, nil
+}
+
+// ExportTarget mirrors the commits of the tracked author that pass the target
+// filters into the target repository, grouped by the target strategy. Commits
+// that were already mirrored are skipped, so running it again is safe. A
+// missing target repository is bootstrapped by OpenTarget, and a target with
+// uncommitted changes is left untouched.
+func ExportTarget(cfg *config_model.ConfigModel, target config_model.ConfigTargetModel) (*ExportResult, error) {
+	if target.Layout().Partitioned() {
+		return nil, internal_errors.ErrPartitionedTarget
+	}
+	targetPath := target.LocalPath()
+
+	r, err := OpenTarget(target)
+	if err != nil {
+		return nil, err
+	}
+
+	wt, err := r.Worktree()
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := wt.Status()
+	if err != nil {
+		return nil, err
+	}
+	if !status.IsClean() {
+		return nil, internal_errors.ErrDirtyTargetRepo
+	}
+
+	if err := checkoutBranch(r, wt, target); err != nil {
+		return nil, err
+	}
+
+	messageTemplate, err := commit_message.Parse(target.MessageTemplate())
+	if err != nil {
+		return nil, err
+	}
+
+	signer, err := sign_handler.NewSigner(target.Signing())
+	if err != nil {
+		return nil, err
+	}
+
+	mappingPath := MappingTablePath(targetPath, target.Name())
+	table, err := LoadMappingTable(r, mappingPath)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := collectCommits(cfg, target)
+	if err != nil {
+		return nil, err
+	}
+	table.ResolveGroups(records, target.DailyCap())
+
+	groups, skipped := planGroups(records, table, target.DailyCap())
+
+	result := &ExportResult{Target: target.Name(), Skipped: skipped}
+	for _, group := range groups {
+		targetHash := group.existing
+		if targetHash == "" {
+			hash, err := mirrorGroup(wt, targetPath, target, messageTemplate, signer, group)
+			if err != nil {
+				return nil, errors.Join(err, table.Save(mappingPath))
+			}
+
+			targetHash = hash.String()
+			table.Head = targetHash
+			result.Commits++
+		}
+
+		for _, sourceID := range group.sourceIDs() {
+			table.Record(sourceID, targetHash)
+		}
+		result.Mirrored += len(group.records)
+	}
+
+	if target.Readme().Enabled() {
+		mirrored, commits := mirroredRecords(records, table)
+		result.Readme, err = updateReadme(wt, targetPath, target, signer, mirrored, commits)
+		if err != nil {
+			return nil, errors.Join(err, table.Save(mappingPath))
+		}
+		if result.Readme {
+			if table.Head, err = headHash(r); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := table.Save(mappingPath); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func collectCommits(cfg *config_model.ConfigModel, target config_model.ConfigTargetModel) ([]repo.CommitRecord, error) {
+	var records []repo.CommitRecord
+	for _, repoPath := range cfg.TrackedRepos() {
+		if !target.IncludesRepo(repoPath) {
+			continue
+		}
+
+		repoRecords, err := repo.ReadAuthorCommits(os.ExpandEnv(repoPath), cfg.TrackedAuthor().Emails())
+		if err != nil {
+			return nil, err
+		}
+
+		for _, record := range repoRecords {
+			// Normalised first, so that date filters and per day strategies
+			// see the same day the mirrored commit is written on
+			record.When = target.InTimezone(record.When)
+			if target.IncludesDate(record.When) {
+				records = append(records, record)
+			}
+		}
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].When.Before(records[j].When)
+	})
+
+	return records, nil
+}
+
+func mirrorGroup(wt *git.Worktree, targetPath string, target config_model.ConfigTargetModel, messageTemplate *template.Template, signer git.Signer, group exportGroup) (plumbing.Hash, error) {
+	if err := writeContent(wt, targetPath, target, group); err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	subject, err := commit_message.Render(messageTemplate, group.templateData())
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	// The trailer is always appended, it is what keeps exports idempotent
+	message := subject + "\n\n" + FormatSourceTrailer(GroupID(group.sourceIDs())) + "\n"
+
+	signature := commitSignature(target, group.last())
+	return wt.Commit(message, &git.CommitOptions{
+		Author:            signature,
+		Committer:         signature,
+		AllowEmptyCommits: true,
+		Signer:            signer,
+	})
+}
+
+// commitSignature returns who a commit written for record is authored and
+// committed as, the target author dated like record.
+func commitSignature(target config_model.ConfigTargetModel, record repo.CommitRecord) *object.Signature {
+	return &object.Signature{
+		Name:  target.Author().Name(),
+		Email: target.Author().Email(),
+		When:  record.When,
+	}
+}
@@ -0,0 +1,632 @@
+package export_handler
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v6"
+	"github.com/go-git/go-git/v6/plumbing"
+	"github.com/go-git/go-git/v6/plumbing/object"
+
+	"github.com/HideyoshiNakazone/tracko/lib/config_model"
+	"github.com/HideyoshiNakazone/tracko/lib/internal_errors"
+	"github.com/HideyoshiNakazone/tracko/lib/repo"
+)
+
+var fixtureBase = time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+
+func prepareSourceRepository(t *testing.T, commits []repo.TestCommit) string {
+	t.Helper()
+
+	sourcePath, cleanup, err := repo.PrepareTestRepository(commits)
+	if err != nil {
+		t.Fatalf("Failed to prepare source repository: %v", err)
+	}
+	t.Cleanup(*cleanup)
+
+	return sourcePath
+}
+
+func prepareTargetRepository(t *testing.T) string {
+	t.Helper()
+
+	targetPath, cleanup, err := repo.PrepareTestRepository(nil)
+	if err != nil {
+		t.Fatalf("Failed to prepare target repository: %v", err)
+	}
+	t.Cleanup(*cleanup)
+
+	return targetPath
+}
+
+func buildConfig(t *testing.T, trackedRepos []string, targets ...*config_model.ConfigTargetBuilder) *config_model.ConfigModel {
+	t.Helper()
+
+	builder := config_model.NewConfigBuilder().
+		WithDBPath("/tmp/test.db").
+		WithTrackedAuthor("Test User", []string{"test@example.com"}).
+		WithTrackedRepos(trackedRepos)
+	for _, target := range targets {
+		builder.WithTarget(target)
+	}
+
+	cfg, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Failed to build config: %v", err)
+	}
+	return cfg
+}
+
+func prepareExportFixture(t *testing.T) (*config_model.ConfigModel, string) {
+	t.Helper()
+
+	sourcePath := prepareSourceRepository(t, []repo.TestCommit{
+		{Author: "Test User", Email: "test@example.com", Message: "feat: first", When: fixtureBase},
+		{Author: "Other User", Email: "other@example.com", Message: "feat: other", When: fixtureBase.Add(time.Hour)},
+		{Author: "Test User", Email: "test@example.com", Message: "fix: second", When: fixtureBase.Add(2 * time.Hour)},
+	})
+	targetPath := prepareTargetRepository(t)
+
+	cfg := buildConfig(t, []string{sourcePath}, config_model.NewTargetBuilder(config_model.DefaultTargetName, targetPath).WithAuthor("Test User", "test@personal.example.com"))
+	return cfg, targetPath
+}
+
+func logSubjects(t *testing.T, repoPath string, branch string) []string {
+	t.Helper()
+
+	r, err := git.PlainOpen(repoPath)
+	if err != nil {
+		t.Fatalf("Failed to open repository: %v", err)
+	}
+
+	ref, err := r.Head()
+	if branch != "" {
+		ref, err = r.Reference(plumbing.NewBranchReferenceName(branch), true)
+	}
+	if err != nil {
+		return []string{}
+	}
+
+	iter, err := r.Log(&git.LogOptions{From: ref.Hash()})
+	if err != nil {
+		t.Fatalf("Failed to read log: %v", err)
+	}
+
+	subjects := []string{}
+	iter.ForEach(func(c *object.Commit) error {
+		subject, _, _ := strings.Cut(c.Message, "\n")
+		subjects = append([]string{subject}, subjects...)
+		return nil
+	})
+	return subjects
+}
+
+func Test_Export(t *testing.T) {
+	cfg, targetPath := prepareExportFixture(t)
+
+	results, err := Export(cfg)
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	if len(results) != 1 || results[0].Mirrored != 2 || results[0].Skipped != 0 {
+		t.Errorf("Expected 2 mirrored and 0 skipped, got %+v", results[0])
+	}
+
+	if count := len(logSubjects(t, targetPath, "")); count != 2 {
+		t.Errorf("Expected 2 commits in target, got %d", count)
+	}
+}
+
+func Test_Export_Idempotent(t *testing.T) {
+	cfg, targetPath := prepareExportFixture(t)
+
+	if _, err := Export(cfg); err != nil {
+		t.Fatalf("First Export() error = %v", err)
+	}
+
+	results, err := Export(cfg)
+	if err != nil {
+		t.Fatalf("Second Export() error = %v", err)
+	}
+
+	if results[0].Mirrored != 0 || results[0].Skipped != 2 {
+		t.Errorf("Expected 0 mirrored and 2 skipped, got %+v", results[0])
+	}
+
+	if count := len(logSubjects(t, targetPath, "")); count != 2 {
+		t.Errorf("Expected 2 commits in target, got %d", count)
+	}
+}
+
+func Test_Export_RebuildsMappingFromTrailers(t *testing.T) {
+	cfg, targetPath := prepareExportFixture(t)
+
+	if _, err := Export(cfg); err != nil {
+		t.Fatalf("First Export() error = %v", err)
+	}
+
+	// Simulates a fresh clone of the target repository
+	if err := os.RemoveAll(MappingTablePath(targetPath, config_model.DefaultTargetName)); err != nil {
+		t.Fatalf("Failed to remove mapping table: %v", err)
+	}
+
+	results, err := Export(cfg)
+	if err != nil {
+		t.Fatalf("Second Export() error = %v", err)
+	}
+
+	if results[0].Mirrored != 0 {
+		t.Errorf("Expected 0 mirrored commits, got %d", results[0].Mirrored)
+	}
+
+	if count := len(logSubjects(t, targetPath, "")); count != 2 {
+		t.Errorf("Expected 2 commits in target, got %d", count)
+	}
+}
+
+func Test_Export_InvalidTarget(t *testing.T) {
+	cfg := buildConfig(t, []string{}, config_model.NewTargetBuilder(config_model.DefaultTargetName, "/path/to/invalid/repo").WithAuthor("Test User", "test@personal.example.com"))
+
+	if _, err := Export(cfg); !errors.Is(err, internal_errors.ErrInvalidTargetRepo) {
+		t.Errorf("Expected ErrInvalidTargetRepo, got %v", err)
+	}
+}
+
+func Test_Export_MessageTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		expected []string
+	}{
+		{"Default template", "", []string{"Mirrored contribution", "Mirrored contribution"}},
+		{"Custom template", "{{.Type}} on {{.Date.Format \"2006-01-02\"}}", []string{"feat on 2025-01-01", "fix on 2025-01-01"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fixture, targetPath := prepareExportFixture(t)
+
+			cfg := buildConfig(t, fixture.TrackedRepos(),
+				config_model.NewTargetBuilder(config_model.DefaultTargetName, targetPath).
+					WithAuthor("Test User", "test@personal.example.com").
+					WithMessageTemplate(tt.template),
+			)
+
+			if _, err := Export(cfg); err != nil {
+				t.Fatalf("Export() error = %v", err)
+			}
+
+			if subjects := logSubjects(t, targetPath, ""); !reflect.DeepEqual(subjects, tt.expected) {
+				t.Errorf("Expected subjects %v, got %v", tt.expected, subjects)
+			}
+		})
+	}
+}
+
+func Test_ExportTarget_Filters(t *testing.T) {
+	backendPath := prepareSourceRepository(t, []repo.TestCommit{
+		{Author: "Test User", Email: "test@example.com", Message: "feat: backend 2024", When: fixtureBase.AddDate(-1, 0, 0)},
+		{Author: "Test User", Email: "test@example.com", Message: "feat: backend 2025", When: fixtureBase},
+	})
+	frontendPath := prepareSourceRepository(t, []repo.TestCommit{
+		{Author: "Test User", Email: "test@example.com", Message: "feat: frontend 2025", When: fixtureBase.Add(time.Hour)},
+	})
+
+	template := "{{.Type}} {{.Date.Format \"2006\"}}"
+	tests := []struct {
+		name     string
+		target   func(targetPath string) *config_model.ConfigTargetBuilder
+		expected []string
+	}{
+		{
+			name:     "No filters",
+			target: func(targetPath string) *config_model.ConfigTargetBuilder {
+				return config_model.NewTargetBuilder("all", targetPath).WithAuthor("Test User", "test@personal.example.com")
+			},
+			expected: []string{"feat 2024", "feat 2025", "feat 2025"},
+		},
+		{
+			name:     "Repo filter by path",
+			target: func(targetPath string) *config_model.ConfigTargetBuilder {
+				return config_model.NewTargetBuilder("frontend", targetPath).WithAuthor("Test User", "test@personal.example.com").WithRepos([]string{frontendPath})
+			},
+			expected: []string{"feat 2025"},
+		},
+		{
+			name: "Date range",
+			target: func(targetPath string) *config_model.ConfigTargetBuilder {
+				return config_model.NewTargetBuilder("recent", targetPath).
+					WithAuthor("Test User", "test@personal.example.com").
+					WithDateRange(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Time{})
+			},
+			expected: []string{"feat 2025", "feat 2025"},
+		},
+		{
+			name: "Repo filter by alias and date range",
+			target: func(targetPath string) *config_model.ConfigTargetBuilder {
+				return config_model.NewTargetBuilder("old-backend", targetPath).
+					WithAuthor("Test User", "test@personal.example.com").
+					WithRepos([]string{filepath.Base(backendPath)}).
+					WithDateRange(time.Time{}, time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC))
+			},
+			expected: []string{"feat 2024"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			targetPath := prepareTargetRepository(t)
+			cfg := buildConfig(t, []string{backendPath, frontendPath}, tt.target(targetPath).WithMessageTemplate(template))
+
+			if _, err := ExportTarget(cfg, cfg.Targets()[0]); err != nil {
+				t.Fatalf("ExportTarget() error = %v", err)
+			}
+
+			if subjects := logSubjects(t, targetPath, ""); !reflect.DeepEqual(subjects, tt.expected) {
+				t.Errorf("Expected subjects %v, got %v", tt.expected, subjects)
+			}
+		})
+	}
+}
+
+func Test_Export_MultipleTargets(t *testing.T) {
+	sourcePath := prepareSourceRepository(t, []repo.TestCommit{
+		{Author: "Test User", Email: "test@example.com", Message: "feat: first", When: fixtureBase},
+	})
+	githubPath := prepareTargetRepository(t)
+	gitlabPath := prepareTargetRepository(t)
+
+	cfg := buildConfig(t, []string{sourcePath},
+		config_model.NewTargetBuilder("github", githubPath).WithAuthor("Test User", "test@personal.example.com"),
+		config_model.NewTargetBuilder("gitlab", gitlabPath).WithAuthor("Test User", "test@personal.example.com").WithBranch("mirror"),
+	)
+
+	results, err := Export(cfg)
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	if len(results) != 2 || results[0].Target != "github" || results[1].Target != "gitlab" {
+		t.Fatalf("Expected results for github and gitlab, got %+v", results)
+	}
+
+	if count := len(logSubjects(t, githubPath, "")); count != 1 {
+		t.Errorf("Expected 1 commit in github target, got %d", count)
+	}
+	if count := len(logSubjects(t, gitlabPath, "mirror")); count != 1 {
+		t.Errorf("Expected 1 commit on the gitlab mirror branch, got %d", count)
+	}
+}
+
+func prepareStrategyFixture(t *testing.T) string {
+	t.Helper()
+
+	day := 24 * time.Hour
+	return prepareSourceRepository(t, []repo.TestCommit{
+		{Author: "Test User", Email: "test@example.com", Message: "feat: one", When: fixtureBase},
+		{Author: "Test User", Email: "test@example.com", Message: "feat: two", When: fixtureBase.Add(time.Hour)},
+		{Author: "Test User", Email: "test@example.com", Message: "fix: three", When: fixtureBase.Add(2 * time.Hour)},
+		{Author: "Test User", Email: "test@example.com", Message: "fix: four", When: fixtureBase.Add(3 * time.Hour)},
+		{Author: "Test User", Email: "test@example.com", Message: "feat: five", When: fixtureBase.Add(day)},
+		{Author: "Test User", Email: "test@example.com", Message: "feat: six", When: fixtureBase.Add(2 * day)},
+		{Author: "Test User", Email: "test@example.com", Message: "feat: seven", When: fixtureBase.Add(2*day + time.Hour)},
+		{Author: "Test User", Email: "test@example.com", Message: "feat: eight", When: fixtureBase.Add(2*day + 2*time.Hour)},
+	})
+}
+
+func Test_ExportTarget_Strategies(t *testing.T) {
+	sourcePath := prepareStrategyFixture(t)
+
+	template := "{{.Date.Format \"2006-01-02\"}} {{.Commits}} {{.Type}}"
+	tests := []struct {
+		name      string
+		strategy  string
+		maxPerDay int
+		expected  []string
+	}{
+		{
+			name:     "One to one",
+			strategy: config_model.StrategyOneToOne,
+			expected: []string{
+				"2025-01-01 1 feat", "2025-01-01 1 feat", "2025-01-01 1 fix", "2025-01-01 1 fix",
+				"2025-01-02 1 feat",
+				"2025-01-03 1 feat", "2025-01-03 1 feat", "2025-01-03 1 feat",
+			},
+		},
+		{
+			name:     "Daily",
+			strategy: config_model.StrategyDaily,
+			expected: []string{"2025-01-01 4", "2025-01-02 1 feat", "2025-01-03 3 feat"},
+		},
+		{
+			name:      "Capped",
+			strategy:  config_model.StrategyCapped,
+			maxPerDay: 2,
+			expected:  []string{"2025-01-01 2 feat", "2025-01-01 2 fix", "2025-01-02 1 feat", "2025-01-03 2 feat", "2025-01-03 1 feat"},
+		},
+		{
+			name:      "Capped above activity",
+			strategy:  config_model.StrategyCapped,
+			maxPerDay: 10,
+			expected: []string{
+				"2025-01-01 1 feat", "2025-01-01 1 feat", "2025-01-01 1 fix", "2025-01-01 1 fix",
+				"2025-01-02 1 feat",
+				"2025-01-03 1 feat", "2025-01-03 1 feat", "2025-01-03 1 feat",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			targetPath := prepareTargetRepository(t)
+			cfg := buildConfig(t, []string{sourcePath},
+				config_model.NewTargetBuilder(config_model.DefaultTargetName, targetPath).
+					WithAuthor("Test User", "test@personal.example.com").
+					WithStrategy(tt.strategy, tt.maxPerDay).
+					WithMessageTemplate(template),
+			)
+
+			result, err := ExportTarget(cfg, cfg.Targets()[0])
+			if err != nil {
+				t.Fatalf("ExportTarget() error = %v", err)
+			}
+
+			if result.Mirrored != 8 || result.Commits != len(tt.expected) {
+				t.Errorf("Expected 8 mirrored as %d commits, got %+v", len(tt.expected), result)
+			}
+
+			if subjects := logSubjects(t, targetPath, ""); !reflect.DeepEqual(subjects, tt.expected) {
+				t.Errorf("Expected subjects %v, got %v", tt.expected, subjects)
+			}
+
+			// Aggregated commits carry a single group id, so the number of
+			// source commits they stand for cannot be counted from them
+			for _, c := range targetCommits(t, targetPath) {
+				if trailers := ParseSourceTrailers(c.Message); len(trailers) != 1 {
+					t.Errorf("Expected a single trailer in %q, got %v", c.Message, trailers)
+				}
+			}
+
+			// Exporting again, with or without the stored mapping table, writes nothing
+			for _, resetTable := range []bool{false, true} {
+				if resetTable {
+					os.RemoveAll(MappingTablePath(targetPath, config_model.DefaultTargetName))
+				}
+
+				result, err := ExportTarget(cfg, cfg.Targets()[0])
+				if err != nil {
+					t.Fatalf("ExportTarget() error = %v", err)
+				}
+				if result.Mirrored != 0 || result.Skipped != 8 {
+					t.Errorf("Expected 0 mirrored and 8 skipped, got %+v", result)
+				}
+			}
+		})
+	}
+}
+
+func Test_ExportTarget_StrategyKeepsCapAcrossExports(t *testing.T) {
+	earlyPath := prepareSourceRepository(t, []repo.TestCommit{
+		{Author: "Test User", Email: "test@example.com", Message: "feat: early", When: fixtureBase},
+	})
+	latePath := prepareSourceRepository(t, []repo.TestCommit{
+		{Author: "Test User", Email: "test@example.com", Message: "feat: late one", When: fixtureBase.Add(time.Hour)},
+		{Author: "Test User", Email: "test@example.com", Message: "feat: late two", When: fixtureBase.Add(2 * time.Hour)},
+		{Author: "Test User", Email: "test@example.com", Message: "feat: late three", When: fixtureBase.Add(3 * time.Hour)},
+	})
+
+	tests := []struct {
+		name      string
+		strategy  string
+		maxPerDay int
+		commits   int
+	}{
+		{"Daily", config_model.StrategyDaily, 0, 1},
+		{"Capped", config_model.StrategyCapped, 2, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			targetPath := prepareTargetRepository(t)
+			target := config_model.NewTargetBuilder(config_model.DefaultTargetName, targetPath).
+				WithAuthor("Test User", "test@personal.example.com").
+				WithStrategy(tt.strategy, tt.maxPerDay)
+
+			// The first export only sees part of the day
+			early := buildConfig(t, []string{earlyPath}, target)
+			if _, err := ExportTarget(early, early.Targets()[0]); err != nil {
+				t.Fatalf("First ExportTarget() error = %v", err)
+			}
+
+			full := buildConfig(t, []string{earlyPath, latePath}, target)
+			result, err := ExportTarget(full, full.Targets()[0])
+			if err != nil {
+				t.Fatalf("Second ExportTarget() error = %v", err)
+			}
+			if result.Mirrored != 3 || result.Skipped != 1 {
+				t.Errorf("Expected 3 mirrored and 1 skipped, got %+v", result)
+			}
+
+			if count := len(logSubjects(t, targetPath, "")); count != tt.commits {
+				t.Errorf("Expected %d commits in target, got %d", tt.commits, count)
+			}
+
+			// Folded commits have no trailer of their own, a rebuilt table must
+			// fold them again instead of exceeding the cap
+			os.RemoveAll(MappingTablePath(targetPath, config_model.DefaultTargetName))
+			if _, err := ExportTarget(full, full.Targets()[0]); err != nil {
+				t.Fatalf("Third ExportTarget() error = %v", err)
+			}
+			if count := len(logSubjects(t, targetPath, "")); count != tt.commits {
+				t.Errorf("Expected %d commits in target after rebuild, got %d", tt.commits, count)
+			}
+		})
+	}
+}
+
+func readTargetSignatures(t *testing.T, repoPath string) []object.Signature {
+	t.Helper()
+
+	r, err := git.PlainOpen(repoPath)
+	if err != nil {
+		t.Fatalf("Failed to open repository: %v", err)
+	}
+
+	ref, err := r.Head()
+	if err != nil {
+		t.Fatalf("Failed to read head: %v", err)
+	}
+
+	iter, err := r.Log(&git.LogOptions{From: ref.Hash()})
+	if err != nil {
+		t.Fatalf("Failed to read log: %v", err)
+	}
+
+	signatures := []object.Signature{}
+	iter.ForEach(func(c *object.Commit) error {
+		if !c.Author.When.Equal(c.Committer.When) {
+			t.Errorf("Expected committer date %v to match author date %v", c.Committer.When, c.Author.When)
+		}
+		signatures = append([]object.Signature{c.Author}, signatures...)
+		return nil
+	})
+	return signatures
+}
+
+func Test_ExportTarget_Timezones(t *testing.T) {
+	tokyo := time.FixedZone("", 9*60*60)
+	newYork := time.FixedZone("", -5*60*60)
+
+	// Late night work on both sides of the date line, each is still
+	// 2025-01-01 in the timezone it was made in
+	sourcePath := prepareSourceRepository(t, []repo.TestCommit{
+		{Author: "Test User", Email: "test@example.com", Message: "feat: tokyo", When: time.Date(2025, 1, 1, 0, 30, 0, 0, tokyo)},
+		{Author: "Test User", Email: "test@example.com", Message: "feat: new york", When: time.Date(2025, 1, 1, 23, 30, 0, 0, newYork)},
+	})
+
+	tests := []struct {
+		name     string
+		timezone string
+		expected []string
+	}{
+		{"Original offsets", "", []string{"2025-01-01T00:30:00+09:00", "2025-01-01T23:30:00-05:00"}},
+		{"Home timezone", "America/Sao_Paulo", []string{"2024-12-31T12:30:00-03:00", "2025-01-02T01:30:00-03:00"}},
+		{"UTC", "UTC", []string{"2024-12-31T15:30:00Z", "2025-01-02T04:30:00Z"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			targetPath := prepareTargetRepository(t)
+			cfg := buildConfig(t, []string{sourcePath},
+				config_model.NewTargetBuilder(config_model.DefaultTargetName, targetPath).
+					WithAuthor("Test User", "test@personal.example.com").
+					WithTimezone(tt.timezone).
+					WithMessageTemplate("{{.Date.Format \"2006-01-02\"}}"),
+			)
+
+			if _, err := ExportTarget(cfg, cfg.Targets()[0]); err != nil {
+				t.Fatalf("ExportTarget() error = %v", err)
+			}
+
+			signatures := readTargetSignatures(t, targetPath)
+			if len(signatures) != len(tt.expected) {
+				t.Fatalf("Expected %d commits, got %d", len(tt.expected), len(signatures))
+			}
+
+			subjects := logSubjects(t, targetPath, "")
+			for i, expected := range tt.expected {
+				if got := signatures[i].When.Format(time.RFC3339); got != expected {
+					t.Errorf("At index %d: expected date %s, got %s", i, expected, got)
+				}
+				if day := expected[:len(config_model.DateFormat)]; subjects[i] != day {
+					t.Errorf("At index %d: expected subject %s, got %s", i, day, subjects[i])
+				}
+			}
+		})
+	}
+}
+
+func Test_ExportTarget_TimezoneDailyStrategy(t *testing.T) {
+	// 23:30 and 00:30 in Sao Paulo are on different days, but both are
+	// 2025-01-02 in UTC
+	saoPaulo := time.FixedZone("", -3*60*60)
+	sourcePath := prepareSourceRepository(t, []repo.TestCommit{
+		{Author: "Test User", Email: "test@example.com", Message: "feat: late", When: time.Date(2025, 1, 1, 23, 30, 0, 0, saoPaulo)},
+		{Author: "Test User", Email: "test@example.com", Message: "feat: early", When: time.Date(2025, 1, 2, 0, 30, 0, 0, saoPaulo)},
+	})
+
+	tests := []struct {
+		name     string
+		timezone string
+		expected []string
+	}{
+		{"Original offsets", "", []string{"2025-01-01 1", "2025-01-02 1"}},
+		{"Home timezone", "UTC", []string{"2025-01-02 2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			targetPath := prepareTargetRepository(t)
+			cfg := buildConfig(t, []string{sourcePath},
+				config_model.NewTargetBuilder(config_model.DefaultTargetName, targetPath).
+					WithAuthor("Test User", "test@personal.example.com").
+					WithStrategy(config_model.StrategyDaily, 0).
+					WithTimezone(tt.timezone).
+					WithMessageTemplate("{{.Date.Format \"2006-01-02\"}} {{.Commits}}"),
+			)
+
+			if _, err := ExportTarget(cfg, cfg.Targets()[0]); err != nil {
+				t.Fatalf("ExportTarget() error = %v", err)
+			}
+
+			if subjects := logSubjects(t, targetPath, ""); !reflect.DeepEqual(subjects, tt.expected) {
+				t.Errorf("Expected subjects %v, got %v", tt.expected, subjects)
+			}
+		})
+	}
+}
+
+func Test_ExportTarget_Author(t *testing.T) {
+	sourcePath := prepareStrategyFixture(t)
+
+	tests := []struct {
+		name     string
+		author   object.Signature
+		expected object.Signature
+	}{
+		{"Target author", object.Signature{Name: "Personal", Email: "me@personal.dev"}, object.Signature{Name: "Personal", Email: "me@personal.dev"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			targetPath := prepareTargetRepository(t)
+			cfg := buildConfig(t, []string{sourcePath},
+				config_model.NewTargetBuilder(config_model.DefaultTargetName, targetPath).
+					WithAuthor(tt.author.Name, tt.author.Email).
+					WithStrategy(config_model.StrategyDaily, 0),
+			)
+
+			if _, err := ExportTarget(cfg, cfg.Targets()[0]); err != nil {
+				t.Fatalf("ExportTarget() error = %v", err)
+			}
+
+			commits := targetCommits(t, targetPath)
+			if len(commits) != 3 {
+				t.Fatalf("Expected 3 commits, got %d", len(commits))
+			}
+			for _, c := range commits {
+				for _, signature := range []object.Signature{c.Author, c.Committer} {
+					if signature.Name != tt.expected.Name || signature.Email != tt.expected.Email {
+						t.Errorf("Expected commits by %s <%s>, got %s <%s>", tt.expected.Name, tt.expected.Email, signature.Name, signature.Email)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package export_handler
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/go-git/go-git/v6"
+	"github.com/go-git/go-git/v6/plumbing"
+	"github.com/go-git/go-git/v6/plumbing/object"
+
+	"github.com/HideyoshiNakazone/tracko/lib/repo"
+)
+
+// MappingTable records which source commit produced which target commit.
+// Head is the target branch head the table was last saved against, a
+// mismatch means the target repository changed behind our back.
+type MappingTable struct {
+	Head    string            `json:"head"`
+	Entries map[string]string `json:"entries"`
+}
+
+func NewMappingTable() *MappingTable {
+	return &MappingTable{
+		Entries: map[string]string{},
+	}
+}
+
+// MappingTablePath returns where the mapping table of a target is stored,
+// inside the git directory so that a fresh clone starts without one.
+func MappingTablePath(targetPath string, targetName string) string {
+	return filepath.Join(targetPath, ".git", "tracko", targetName+".json")
+}
+
+func (m *MappingTable) Contains(sourceID string) bool {
+	_, ok := m.Entries[sourceID]
+	return ok
+}
+
+func (m *MappingTable) Record(sourceID string, targetHash string) {
+	m.Entries[sourceID] = targetHash
+}
+
+func (m *MappingTable) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0o644)
+}
+
+// LoadMappingTable reads the mapping table stored for the target repository.
+// If the stored table is missing or was saved against another head (the
+// target was re-cloned or reset) it is rebuilt from the commit trailers.
+func LoadMappingTable(r *git.Repository, path string) (*MappingTable, error) {
+	head, err := headHash(r)
+	if err != nil {
+		return nil, err
+	}
+	return loadMappingTableAt(r, path, head)
+}
+
+func loadMappingTableAt(r *git.Repository, path string, head string) (*MappingTable, error) {
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	if err == nil {
+		table := NewMappingTable()
+		if err := json.Unmarshal(data, table); err == nil && table.Head == head {
+			return table, nil
+		}
+	}
+
+	return rebuildMappingTableFrom(r, head)
+}
+
+// RebuildMappingTable scans the history of the target repository for source
+// id trailers. Aggregated commits only carry the id of their group, the
+// source commits they stand for are mapped by ResolveGroups.
+func RebuildMappingTable(r *git.Repository) (*MappingTable, error) {
+	head, err := headHash(r)
+	if err != nil {
+		return nil, err
+	}
+	return rebuildMappingTableFrom(r, head)
+}
+
+func rebuildMappingTableFrom(r *git.Repository, head string) (*MappingTable, error) {
+	table := NewMappingTable()
+	if head == "" {
+		return table, nil
+	}
+	table.Head = head
+
+	iter, err := r.Log(&git.LogOptions{From: plumbing.NewHash(head)})
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	err = iter.ForEach(func(c *object.Commit) error {
+		for _, sourceID := range ParseSourceTrailers(c.Message) {
+			table.Record(sourceID, c.Hash.String())
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return table, nil
+}
+
+// ResolveGroups maps the source commits of aggregated target commits, which
+// carry a single group id trailer, by planning the groups of records again
+// with dailyCap and looking their group id up in the table. Days that got new
+// commits after they were exported no longer split the same way, runs of
+// consecutive commits of those days are looked up instead.
+func (m *MappingTable) ResolveGroups(records []repo.CommitRecord, dailyCap int) {
+	if dailyCap == 0 {
+		return
+	}
+
+	groups, _ := planGroups(records, NewMappingTable(), dailyCap)
+	for _, group := range groups {
+		m.resolveGroup(group)
+	}
+
+	for _, day := range groupByDay(records) {
+		for i := 0; i < len(day); i++ {
+			if m.Contains(SourceID(day[i].Hash)) {
+				continue
+			}
+			for j := len(day); j > i+1; j-- {
+				if m.resolveGroup(exportGroup{records: day[i:j]}) {
+					i = j - 1
+					break
+				}
+			}
+		}
+	}
+}
+
+func (m *MappingTable) resolveGroup(group exportGroup) bool {
+	sourceIDs := group.sourceIDs()
+	targetHash, ok := m.Entries[GroupID(sourceIDs)]
+	if !ok {
+		return false
+	}
+	for _, sourceID := range sourceIDs {
+		m.Record(sourceID, targetHash)
+	}
+	return true
+}
+
+func headHash(r *git.Repository) (string, error) {
+	head, err := r.Head()
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return head.Hash().String(), nil
+}
@@ -0,0 +1,136 @@
+package export_handler
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v6"
+
+	"github.com/HideyoshiNakazone/tracko/lib/repo"
+)
+
+func prepareMappedTarget(t *testing.T) (*git.Repository, string) {
+	t.Helper()
+
+	base := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	targetPath, cleanup, err := repo.PrepareTestRepository([]repo.TestCommit{
+		{Author: "Test User", Email: "test@example.com", Message: "Mirrored contribution\n\n" + FormatSourceTrailer("id1") + "\n", When: base},
+		{Author: "Test User", Email: "test@example.com", Message: "Manual commit\n", When: base.Add(time.Hour)},
+		{Author: "Test User", Email: "test@example.com", Message: "Mirrored contribution\n\n" + FormatSourceTrailer("id2") + "\n", When: base.Add(2 * time.Hour)},
+	})
+	if err != nil {
+		t.Fatalf("Failed to prepare target repository: %v", err)
+	}
+	t.Cleanup(*cleanup)
+
+	r, err := git.PlainOpen(targetPath)
+	if err != nil {
+		t.Fatalf("Failed to open target repository: %v", err)
+	}
+	return r, targetPath
+}
+
+func Test_RebuildMappingTable(t *testing.T) {
+	r, _ := prepareMappedTarget(t)
+
+	table, err := RebuildMappingTable(r)
+	if err != nil {
+		t.Fatalf("RebuildMappingTable() error = %v", err)
+	}
+
+	if len(table.Entries) != 2 || !table.Contains("id1") || !table.Contains("id2") {
+		t.Errorf("Expected entries for id1 and id2, got %v", table.Entries)
+	}
+
+	head, _ := r.Head()
+	if table.Head != head.Hash().String() {
+		t.Errorf("Expected head %s, got %s", head.Hash(), table.Head)
+	}
+}
+
+func Test_LoadMappingTable(t *testing.T) {
+	tests := []struct {
+		name     string
+		stale    bool
+		expected []string
+	}{
+		{"Up to date table is kept", false, []string{"id1", "id2", "id3"}},
+		{"Stale table is rebuilt", true, []string{"id1", "id2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, targetPath := prepareMappedTarget(t)
+			head, _ := r.Head()
+
+			stored := NewMappingTable()
+			stored.Head = head.Hash().String()
+			if tt.stale {
+				stored.Head = "0000000000000000000000000000000000000000"
+			}
+			stored.Record("id1", "hash1")
+			stored.Record("id2", "hash2")
+			stored.Record("id3", "hash3")
+
+			path := filepath.Join(targetPath, ".git", "tracko", "mapping.json")
+			if err := stored.Save(path); err != nil {
+				t.Fatalf("Save() error = %v", err)
+			}
+
+			table, err := LoadMappingTable(r, path)
+			if err != nil {
+				t.Fatalf("LoadMappingTable() error = %v", err)
+			}
+
+			if len(table.Entries) != len(tt.expected) {
+				t.Errorf("Expected %d entries, got %v", len(tt.expected), table.Entries)
+			}
+			for _, sourceID := range tt.expected {
+				if !table.Contains(sourceID) {
+					t.Errorf("Expected table to contain %q", sourceID)
+				}
+			}
+		})
+	}
+}
+
+func Test_MappingTable_ResolveGroups(t *testing.T) {
+	base := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	records := []repo.CommitRecord{}
+	for i, hash := range []string{"a1", "b2", "c3", "d4"} {
+		records = append(records, repo.CommitRecord{Hash: hash, When: base.Add(time.Duration(i) * time.Hour)})
+	}
+	groupID := func(records ...repo.CommitRecord) string {
+		return GroupID(exportGroup{records: records}.sourceIDs())
+	}
+
+	tests := []struct {
+		name     string
+		entries  map[string]string
+		dailyCap int
+		expected []string
+	}{
+		{"Same plan", map[string]string{groupID(records...): "t1"}, 1, []string{"t1", "t1", "t1", "t1"}},
+		{"Day exported early", map[string]string{groupID(records[:2]...): "t1"}, 1, []string{"t1", "t1", "", ""}},
+		{"Capped", map[string]string{groupID(records[:2]...): "t1", groupID(records[2:]...): "t2"}, 2, []string{"t1", "t1", "t2", "t2"}},
+		{"One to one", map[string]string{groupID(records[:2]...): "t1"}, 0, []string{"", "", "", ""}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table := NewMappingTable()
+			for groupID, targetHash := range tt.entries {
+				table.Record(groupID, targetHash)
+			}
+
+			table.ResolveGroups(records, tt.dailyCap)
+
+			for i, record := range records {
+				if got := table.Entries[SourceID(record.Hash)]; got != tt.expected[i] {
+					t.Errorf("Expected %s to map to %q, got %q", record.Hash, tt.expected[i], got)
+				}
+			}
+		})
+	}
+}
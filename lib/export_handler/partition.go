@@ -0,0 +1,40 @@
+package export_handler
+
+import (
+	"slices"
+
+	"github.com/HideyoshiNakazone/tracko/lib/config_model"
+)
+
+// Partitions returns the targets the export of target is split into by its
+// layout, or the target itself when it is not partitioned. Year partitions
+// are listed for every year with commits to export, oldest first.
+func Partitions(cfg *config_model.ConfigModel, target config_model.ConfigTargetModel) ([]config_model.ConfigTargetModel, error) {
+	switch target.Layout().Split() {
+	case config_model.LayoutSplitYear:
+		records, err := collectCommits(cfg, target)
+		if err != nil {
+			return nil, err
+		}
+
+		years := []int{}
+		for _, record := range records {
+			if !slices.Contains(years, record.When.Year()) {
+				years = append(years, record.When.Year())
+			}
+		}
+		slices.Sort(years)
+
+		partitions := []config_model.ConfigTargetModel{}
+		for _, year := range years {
+			partitions = append(partitions, target.YearPartition(year))
+		}
+		return partitions, nil
+
+	case config_model.LayoutSplitRepoGroup:
+		return target.GroupPartitions(), nil
+
+	default:
+		return []config_model.ConfigTargetModel{target}, nil
+	}
+}
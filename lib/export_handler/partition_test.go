@@ -0,0 +1,135 @@
+package export_handler
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/HideyoshiNakazone/tracko/lib/config_model"
+	"github.com/HideyoshiNakazone/tracko/lib/internal_errors"
+	"github.com/HideyoshiNakazone/tracko/lib/repo"
+)
+
+func preparePartitionFixture(t *testing.T) (string, string) {
+	t.Helper()
+
+	backendPath := prepareSourceRepository(t, []repo.TestCommit{
+		{Author: "Test User", Email: "test@example.com", Message: "feat: 2023 backend", When: fixtureBase.AddDate(-2, 0, 0)},
+		{Author: "Test User", Email: "test@example.com", Message: "feat: 2024 backend", When: fixtureBase.AddDate(-1, 0, 0)},
+		{Author: "Test User", Email: "test@example.com", Message: "fix: 2025 backend", When: fixtureBase},
+	})
+	frontendPath := prepareSourceRepository(t, []repo.TestCommit{
+		{Author: "Test User", Email: "test@example.com", Message: "feat: 2025 frontend", When: fixtureBase.Add(time.Hour)},
+	})
+	return backendPath, frontendPath
+}
+
+func Test_Partitions_ByYearIntoBranches(t *testing.T) {
+	backendPath, frontendPath := preparePartitionFixture(t)
+	targetPath := prepareTargetRepository(t)
+
+	cfg := buildConfig(t, []string{backendPath, frontendPath},
+		config_model.NewTargetBuilder("github", targetPath).
+			WithAuthor("Test User", "test@personal.example.com").
+			WithMessageTemplate("{{.Date.Format \"2006\"}} {{.RepoAlias}}").
+			WithDateRange(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Time{}).
+			WithLayout(config_model.LayoutSplitYear, config_model.LayoutIntoBranch),
+	)
+	target := cfg.Targets()[0]
+
+	if _, err := ExportTarget(cfg, target); !errors.Is(err, internal_errors.ErrPartitionedTarget) {
+		t.Fatalf("Expected ErrPartitionedTarget, got %v", err)
+	}
+
+	partitions, err := Partitions(cfg, target)
+	if err != nil {
+		t.Fatalf("Partitions() error = %v", err)
+	}
+	names := []string{}
+	for _, partition := range partitions {
+		names = append(names, partition.Name())
+	}
+	if !reflect.DeepEqual(names, []string{"github-2024", "github-2025"}) {
+		t.Fatalf("Expected the 2024 and 2025 partitions, got %v", names)
+	}
+
+	results, err := Export(cfg)
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if len(results) != 2 || results[0].Commits != 1 || results[1].Commits != 2 {
+		t.Errorf("Expected 1 and 2 commits, got %+v and %+v", results[0], results[1])
+	}
+
+	backend, frontend := filepath.Base(backendPath), filepath.Base(frontendPath)
+	expected := map[string][]string{
+		"tracko-2024": {"2024 " + backend},
+		"tracko-2025": {"2025 " + backend, "2025 " + frontend},
+	}
+	for branch, subjects := range expected {
+		if got := logSubjects(t, targetPath, branch); !reflect.DeepEqual(got, subjects) {
+			t.Errorf("Expected %v on %s, got %v", subjects, branch, got)
+		}
+	}
+
+	// Every partition has its own mapping table, so one can be rebuilt alone
+	for _, partition := range partitions {
+		if _, err := os.Stat(MappingTablePath(targetPath, partition.Name())); err != nil {
+			t.Errorf("Expected a mapping table for %s, got %v", partition.Name(), err)
+		}
+	}
+
+	result, err := Rebuild(cfg, partitions[1])
+	if err != nil {
+		t.Fatalf("Rebuild() error = %v", err)
+	}
+	if result.Removed != 2 || result.Export.Commits != 2 {
+		t.Errorf("Expected the 2025 partition rebuilt, got %+v", result)
+	}
+	if got := logSubjects(t, targetPath, "tracko-2024"); !reflect.DeepEqual(got, expected["tracko-2024"]) {
+		t.Errorf("Expected the 2024 partition untouched, got %v", got)
+	}
+
+	results, err = Export(cfg)
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if results[0].Skipped != 1 || results[1].Skipped != 2 || results[0].Commits+results[1].Commits != 0 {
+		t.Errorf("Expected nothing new to export, got %+v and %+v", results[0], results[1])
+	}
+}
+
+func Test_Partitions_ByRepoGroupIntoRepos(t *testing.T) {
+	backendPath, frontendPath := preparePartitionFixture(t)
+	targetPath := filepath.Join(t.TempDir(), "mirror")
+
+	cfg := buildConfig(t, []string{backendPath, frontendPath},
+		config_model.NewTargetBuilder("github", targetPath).
+			WithAuthor("Test User", "test@personal.example.com").
+			WithCreate(true).
+			WithLayout(config_model.LayoutSplitRepoGroup, config_model.LayoutIntoRepo).
+			WithRepoGroup("server", []string{filepath.Base(backendPath)}).
+			WithRepoGroup("client", []string{frontendPath}),
+	)
+
+	results, err := Export(cfg)
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if len(results) != 2 || results[0].Target != "github-server" || results[1].Target != "github-client" {
+		t.Fatalf("Expected the server and client partitions, got %+v", results)
+	}
+
+	if count := len(logSubjects(t, targetPath+"-server", "")); count != 3 {
+		t.Errorf("Expected 3 commits in the server repository, got %d", count)
+	}
+	if count := len(logSubjects(t, targetPath+"-client", "")); count != 1 {
+		t.Errorf("Expected 1 commit in the client repository, got %d", count)
+	}
+	if _, err := os.Stat(targetPath); !os.IsNotExist(err) {
+		t.Errorf("Expected no repository at the target path itself, got %v", err)
+	}
+}
@@ -0,0 +1,217 @@
+package export_handler
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-git/go-git/v6"
+	"github.com/go-git/go-git/v6/plumbing"
+	"github.com/go-git/go-git/v6/plumbing/object"
+	"github.com/go-git/go-git/v6/storage/memory"
+
+	"github.com/HideyoshiNakazone/tracko/lib/commit_message"
+	"github.com/HideyoshiNakazone/tracko/lib/config_model"
+	"github.com/HideyoshiNakazone/tracko/lib/internal_errors"
+	"github.com/HideyoshiNakazone/tracko/lib/repo"
+)
+
+// PlannedCommit is a target commit an export would write, Sources is the
+// number of source commits it mirrors, none for the readme commit.
+type PlannedCommit struct {
+	When    time.Time
+	Subject string
+	Sources int
+}
+
+// ExportPlan is what ExportTarget would do to a target. Existing holds the
+// author dates of the commits already on the target branch.
+type ExportPlan struct {
+	ExportResult
+	Planned  []PlannedCommit
+	Existing []time.Time
+}
+
+// DailyCounts returns the target commits per day, keyed by
+// config_model.DateFormat, before and after the planned export.
+func (p *ExportPlan) DailyCounts() (map[string]int, map[string]int) {
+	before := map[string]int{}
+	after := map[string]int{}
+	for _, when := range p.Existing {
+		before[dayKey(when)]++
+		after[dayKey(when)]++
+	}
+	for _, commit := range p.Planned {
+		after[dayKey(commit.When)]++
+	}
+	return before, after
+}
+
+// PlanTarget works out the target commits ExportTarget would write, without
+// touching the target repository: the branch is not checked out and neither
+// commits nor the mapping table are written. A missing target that export
+// would create is planned as empty, one that would be cloned is not planned
+// since its history is unknown until then.
+func PlanTarget(cfg *config_model.ConfigModel, target config_model.ConfigTargetModel) (*ExportPlan, error) {
+	if target.Layout().Partitioned() {
+		return nil, internal_errors.ErrPartitionedTarget
+	}
+	targetPath := target.LocalPath()
+
+	r, err := git.PlainOpen(targetPath)
+	switch {
+	case errors.Is(err, git.ErrRepositoryNotExists) && target.URL() != "":
+		return nil, fmt.Errorf("%w: %s is not cloned from %s yet", internal_errors.ErrInvalidTargetRepo, targetPath, target.URL())
+	case errors.Is(err, git.ErrRepositoryNotExists) && target.Create():
+		if r, err = git.Init(memory.NewStorage()); err != nil {
+			return nil, err
+		}
+	case err != nil:
+		return nil, internal_errors.ErrInvalidTargetRepo
+	}
+
+	messageTemplate, err := commit_message.Parse(target.MessageTemplate())
+	if err != nil {
+		return nil, err
+	}
+
+	head, err := branchStart(r, target)
+	if err != nil {
+		return nil, err
+	}
+
+	table, err := loadMappingTableAt(r, MappingTablePath(targetPath, target.Name()), head)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := commitDates(r, head)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := collectCommits(cfg, target)
+	if err != nil {
+		return nil, err
+	}
+
+	table.ResolveGroups(records, target.DailyCap())
+
+	groups, skipped := planGroups(records, table, target.DailyCap())
+
+	plan := &ExportPlan{
+		ExportResult: ExportResult{Target: target.Name(), Skipped: skipped},
+		Planned:      []PlannedCommit{},
+		Existing:     existing,
+	}
+	for _, group := range groups {
+		plan.Mirrored += len(group.records)
+		if group.existing != "" {
+			continue
+		}
+
+		subject, err := commit_message.Render(messageTemplate, group.templateData())
+		if err != nil {
+			return nil, err
+		}
+		plan.Planned = append(plan.Planned, PlannedCommit{
+			When:    group.last().When,
+			Subject: subject,
+			Sources: len(group.records),
+		})
+		plan.Commits++
+	}
+
+	if target.Readme().Enabled() {
+		// The table is never saved here, recording the planned groups only
+		// lets the readme be rendered from what the export would mirror. New
+		// groups get a placeholder per group so that they count as one
+		// target commit each
+		for _, group := range groups {
+			targetHash := group.existing
+			if targetHash == "" {
+				targetHash = "planned:" + GroupID(group.sourceIDs())
+			}
+			for _, sourceID := range group.sourceIDs() {
+				table.Record(sourceID, targetHash)
+			}
+		}
+
+		mirrored, commits := mirroredRecords(records, table)
+		plan.Readme, err = readmeChanged(r, head, target, mirrored, commits)
+		if err != nil {
+			return nil, err
+		}
+		if plan.Readme {
+			plan.Planned = append(plan.Planned, PlannedCommit{
+				When:    mirrored[len(mirrored)-1].When,
+				Subject: ReadmeSubject,
+			})
+		}
+	}
+
+	return plan, nil
+}
+
+// readmeChanged reports whether the readme or badge rendered from records,
+// mirrored as commits target commits, differ from the ones committed at head.
+func readmeChanged(r *git.Repository, head string, target config_model.ConfigTargetModel, records []repo.CommitRecord, commits int) (bool, error) {
+	if len(records) == 0 {
+		return false, nil
+	}
+
+	files, err := renderReadme(target, records, commits)
+	if err != nil {
+		return false, err
+	}
+	if head == "" {
+		return true, nil
+	}
+
+	commit, err := r.CommitObject(plumbing.NewHash(head))
+	if err != nil {
+		return false, err
+	}
+	for path, content := range files {
+		file, err := commit.File(path)
+		if errors.Is(err, object.ErrFileNotFound) {
+			return true, nil
+		}
+		if err != nil {
+			return false, err
+		}
+
+		current, err := file.Contents()
+		if err != nil {
+			return false, err
+		}
+		if current != string(content) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// commitDates returns the author dates of every commit reachable from head,
+// newest first.
+func commitDates(r *git.Repository, head string) ([]time.Time, error) {
+	dates := []time.Time{}
+	if head == "" {
+		return dates, nil
+	}
+
+	iter, err := r.Log(&git.LogOptions{From: plumbing.NewHash(head)})
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	err = iter.ForEach(func(c *object.Commit) error {
+		dates = append(dates, c.Author.When)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return dates, nil
+}
@@ -0,0 +1,117 @@
+package export_handler
+
+import (
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v6"
+
+	"github.com/HideyoshiNakazone/tracko/lib/config_model"
+)
+
+func Test_PlanTarget(t *testing.T) {
+	sourcePath := prepareStrategyFixture(t)
+	targetPath := prepareTargetRepository(t)
+	cfg := buildConfig(t, []string{sourcePath},
+		config_model.NewTargetBuilder(config_model.DefaultTargetName, targetPath).
+			WithAuthor("Test User", "test@personal.example.com").
+			WithBranch("tracko").
+			WithStrategy(config_model.StrategyCapped, 2).
+			WithMessageTemplate("{{.Date.Format \"2006-01-02\"}} {{.Commits}} {{.Type}}"),
+	)
+	target := cfg.Targets()[0]
+
+	plan, err := PlanTarget(cfg, target)
+	if err != nil {
+		t.Fatalf("PlanTarget() error = %v", err)
+	}
+
+	expected := []string{"2025-01-01 2 feat", "2025-01-01 2 fix", "2025-01-02 1 feat", "2025-01-03 2 feat", "2025-01-03 1 feat"}
+	subjects := []string{}
+	for _, commit := range plan.Planned {
+		subjects = append(subjects, commit.Subject)
+	}
+	if !reflect.DeepEqual(subjects, expected) {
+		t.Errorf("Expected planned subjects %v, got %v", expected, subjects)
+	}
+	if plan.Mirrored != 8 || plan.Commits != 5 || plan.Skipped != 0 || len(plan.Existing) != 0 {
+		t.Errorf("Expected 8 mirrored as 5 commits on an empty branch, got %+v", plan)
+	}
+
+	// Nothing was written, the branch was not even created
+	r, err := git.PlainOpen(targetPath)
+	if err != nil {
+		t.Fatalf("Failed to open target repository: %v", err)
+	}
+	if _, err := r.Head(); err == nil {
+		t.Errorf("Expected the target to have no commits")
+	}
+	if _, err := os.Stat(MappingTablePath(targetPath, target.Name())); !os.IsNotExist(err) {
+		t.Errorf("Expected no mapping table, got %v", err)
+	}
+
+	// The plan matches what the export writes
+	result, err := ExportTarget(cfg, target)
+	if err != nil {
+		t.Fatalf("ExportTarget() error = %v", err)
+	}
+	if !reflect.DeepEqual(logSubjects(t, targetPath, "tracko"), expected) || result.Commits != plan.Commits {
+		t.Errorf("Expected the export to write the plan, got %+v", result)
+	}
+
+	plan, err = PlanTarget(cfg, target)
+	if err != nil {
+		t.Fatalf("PlanTarget() error = %v", err)
+	}
+	if plan.Mirrored != 0 || plan.Skipped != 8 || len(plan.Planned) != 0 || len(plan.Existing) != 5 {
+		t.Errorf("Expected nothing planned over 5 existing commits, got %+v", plan)
+	}
+
+	before, after := plan.DailyCounts()
+	if !reflect.DeepEqual(before, after) || before["2025-01-01"] != 2 || before["2025-01-03"] != 2 {
+		t.Errorf("Expected unchanged daily counts, got %v and %v", before, after)
+	}
+}
+
+func Test_PlanTarget_Readme(t *testing.T) {
+	sourcePath := prepareReadmeFixture(t)
+	targetPath := prepareTargetRepository(t)
+	cfg := buildConfig(t, []string{sourcePath},
+		config_model.NewTargetBuilder("github", targetPath).
+			WithAuthor("Test User", "test@personal.example.com").
+			WithStrategy(config_model.StrategyDaily, 0).
+			WithReadme(""),
+	)
+	target := cfg.Targets()[0]
+
+	plan, err := PlanTarget(cfg, target)
+	if err != nil {
+		t.Fatalf("PlanTarget() error = %v", err)
+	}
+	if !plan.Readme || plan.Commits != 2 || len(plan.Planned) != 3 {
+		t.Fatalf("Expected 2 commits and the readme planned, got %+v", plan)
+	}
+	readme := plan.Planned[2]
+	if readme.Subject != ReadmeSubject || readme.Sources != 0 || !readme.When.Equal(fixtureBase.Add(24*time.Hour)) {
+		t.Errorf("Expected the readme planned last, dated like the last commit, got %+v", readme)
+	}
+
+	// The plan matches what the export writes
+	result, err := ExportTarget(cfg, target)
+	if err != nil {
+		t.Fatalf("ExportTarget() error = %v", err)
+	}
+	if !result.Readme || len(targetCommits(t, targetPath)) != len(plan.Planned) {
+		t.Errorf("Expected the export to write the plan, got %+v", result)
+	}
+
+	plan, err = PlanTarget(cfg, target)
+	if err != nil {
+		t.Fatalf("PlanTarget() error = %v", err)
+	}
+	if plan.Readme || len(plan.Planned) != 0 {
+		t.Errorf("Expected the unchanged readme not to be planned, got %+v", plan)
+	}
+}
@@ -0,0 +1,153 @@
+package export_handler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/go-git/go-git/v6"
+
+	"github.com/HideyoshiNakazone/tracko/lib/config_model"
+	"github.com/HideyoshiNakazone/tracko/lib/contribution_stats"
+	"github.com/HideyoshiNakazone/tracko/lib/internal_errors"
+	"github.com/HideyoshiNakazone/tracko/lib/readme_template"
+	"github.com/HideyoshiNakazone/tracko/lib/repo"
+)
+
+// Files written by the readme, relative to the target root.
+const (
+	ReadmePath = "README.md"
+	BadgePath  = "badge.svg"
+)
+
+// ReadmeSubject is the message of the commits updating the readme, they carry
+// no source id trailer and are kept by revert and rebuild like any other.
+const ReadmeSubject = "Update README"
+
+func newReadmeData(target config_model.ConfigTargetModel, records []repo.CommitRecord, commits int) readme_template.TemplateData {
+	data := readme_template.TemplateData{
+		Target:     target.Name(),
+		Author:     target.Author().Name(),
+		Commits:    commits,
+		ActiveDays: len(contribution_stats.DailyCounts(records)),
+		From:       records[0].When.Format(config_model.DateFormat),
+		To:         records[len(records)-1].When.Format(config_model.DateFormat),
+		Languages:  []readme_template.Language{},
+		Badge:      BadgePath,
+	}
+
+	languages := contribution_stats.ByLanguage(records)
+	total := 0
+	for _, language := range languages {
+		total += language.FilesChanged
+	}
+	for _, language := range languages {
+		data.Languages = append(data.Languages, readme_template.Language{
+			Language: language.Language,
+			Share:    (language.FilesChanged*100 + total/2) / total,
+		})
+	}
+	return data
+}
+
+// renderBadge returns a flat badge with the number of mirrored commits, its
+// width estimated from the text since no font is at hand to measure it.
+func renderBadge(commits int) string {
+	label := "mirrored commits"
+	value := fmt.Sprint(commits)
+	labelWidth := 7*len(label) + 10
+	valueWidth := 7*len(value) + 10
+	width := labelWidth + valueWidth
+
+	return fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%[1]d" height="20" role="img" aria-label="%[4]s: %[5]s">
+<title>%[4]s: %[5]s</title>
+<rect width="%[2]d" height="20" rx="3" fill="#555"/>
+<rect x="%[2]d" width="%[3]d" height="20" rx="3" fill="#40c463"/>
+<rect x="%[2]d" width="4" height="20" fill="#40c463"/>
+<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">
+<text x="%[6]d" y="14">%[4]s</text>
+<text x="%[7]d" y="14">%[5]s</text>
+</g>
+</svg>
+`, width, labelWidth, valueWidth, label, value, labelWidth/2, labelWidth+valueWidth/2)
+}
+
+// mirroredRecords returns the records the table maps to a target commit, and
+// the number of distinct target commits they are mirrored as.
+func mirroredRecords(records []repo.CommitRecord, table *MappingTable) ([]repo.CommitRecord, int) {
+	mirrored := []repo.CommitRecord{}
+	targetHashes := map[string]bool{}
+	for _, record := range records {
+		if targetHash, ok := table.Entries[SourceID(record.Hash)]; ok {
+			mirrored = append(mirrored, record)
+			targetHashes[targetHash] = true
+		}
+	}
+	return mirrored, len(targetHashes)
+}
+
+// renderReadme returns the readme and badge of the target, keyed by their
+// path relative to the target root. They show the target commits rather than
+// the source ones, which aggregating strategies keep private.
+func renderReadme(target config_model.ConfigTargetModel, records []repo.CommitRecord, commits int) (map[string][]byte, error) {
+	tmpl, err := readme_template.Parse(target.Readme().Template())
+	if err != nil {
+		return nil, fmt.Errorf("%w: readme template: %v", internal_errors.ErrInvalidConfig, err)
+	}
+
+	readme, err := readme_template.Render(tmpl, newReadmeData(target, records, commits))
+	if err != nil {
+		return nil, fmt.Errorf("%w: readme template: %v", internal_errors.ErrInvalidConfig, err)
+	}
+
+	return map[string][]byte{
+		ReadmePath: readme,
+		BadgePath:  []byte(renderBadge(commits)),
+	}, nil
+}
+
+// updateReadme regenerates the readme and badge of the target from the
+// records it mirrors, and commits them when they changed. The commit is
+// dated like the last mirrored commit so it adds no day to the calendar.
+func updateReadme(wt *git.Worktree, targetPath string, target config_model.ConfigTargetModel, signer git.Signer, records []repo.CommitRecord, commits int) (bool, error) {
+	if len(records) == 0 {
+		return false, nil
+	}
+
+	files, err := renderReadme(target, records, commits)
+	if err != nil {
+		return false, err
+	}
+
+	changed := false
+	for path, content := range files {
+		current, err := os.ReadFile(filepath.Join(targetPath, path))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return false, err
+		}
+		if err == nil && bytes.Equal(current, content) {
+			continue
+		}
+
+		if err := os.WriteFile(filepath.Join(targetPath, path), content, 0o644); err != nil {
+			return false, err
+		}
+		if _, err := wt.Add(path); err != nil {
+			return false, err
+		}
+		changed = true
+	}
+	if !changed {
+		return false, nil
+	}
+
+	signature := commitSignature(target, records[len(records)-1])
+	_, err = wt.Commit(ReadmeSubject+"\n", &git.CommitOptions{
+		Author:    signature,
+		Committer: signature,
+		Signer:    signer,
+	})
+	return err == nil, err
+}
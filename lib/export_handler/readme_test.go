@@ -0,0 +1,133 @@
+package export_handler
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/HideyoshiNakazone/tracko/lib/config_model"
+	"github.com/HideyoshiNakazone/tracko/lib/internal_errors"
+	"github.com/HideyoshiNakazone/tracko/lib/repo"
+)
+
+func prepareReadmeFixture(t *testing.T) string {
+	t.Helper()
+
+	return prepareSourceRepository(t, []repo.TestCommit{
+		{Author: "Test User", Email: "test@example.com", Message: "feat: one", When: fixtureBase, Files: map[string]string{"main.go": "package main\n", "README.md": "# Source\n"}},
+		{Author: "Test User", Email: "test@example.com", Message: "feat: two", When: fixtureBase.Add(time.Hour), Files: map[string]string{"main.go": "package main\n\nfunc main() {}\n"}},
+		{Author: "Test User", Email: "test@example.com", Message: "feat: three", When: fixtureBase.Add(24 * time.Hour), Files: map[string]string{"app.py": "print()\n"}},
+	})
+}
+
+func Test_ExportTarget_Readme(t *testing.T) {
+	sourcePath := prepareReadmeFixture(t)
+	targetPath := prepareTargetRepository(t)
+
+	cfg := buildConfig(t, []string{sourcePath},
+		config_model.NewTargetBuilder("github", targetPath).
+			WithAuthor("Personal", "me@personal.dev").
+			WithStrategy(config_model.StrategyDaily, 0).
+			WithReadme(""),
+	)
+
+	result, err := ExportTarget(cfg, cfg.Targets()[0])
+	if err != nil {
+		t.Fatalf("ExportTarget() error = %v", err)
+	}
+	if !result.Readme || result.Commits != 2 {
+		t.Errorf("Expected 2 commits and the readme, got %+v", result)
+	}
+
+	commits := targetCommits(t, targetPath)
+	if len(commits) != 3 || commits[0].Message != ReadmeSubject+"\n" {
+		t.Fatalf("Expected the readme committed last, got %d commits", len(commits))
+	}
+	if !commits[0].Author.When.Equal(fixtureBase.Add(24*time.Hour)) || commits[0].Author.Email != "me@personal.dev" {
+		t.Errorf("Expected the readme dated and authored like the last commit, got %v", commits[0].Author)
+	}
+
+	files := headFiles(t, targetPath)
+	for _, expected := range []string{
+		"# github\n",
+		"the commit activity of Personal,",
+		"| Mirrored commits | 2 |",
+		"| Active days | 2 |",
+		"| Period | 2025-01-01 to 2025-01-02 |",
+		"| Go | 50% |",
+		"| Python | 25% |",
+	} {
+		if !strings.Contains(files[ReadmePath], expected) {
+			t.Errorf("Expected the readme to contain %q, got %q", expected, files[ReadmePath])
+		}
+	}
+	// The daily strategy wrote 2 target commits for the 3 source ones, the
+	// source count stays private
+	if !strings.Contains(files[BadgePath], "mirrored commits: 2") {
+		t.Errorf("Expected a badge with 2 commits, got %q", files[BadgePath])
+	}
+
+	// Nothing new, the readme is unchanged and not committed again
+	result, err = ExportTarget(cfg, cfg.Targets()[0])
+	if err != nil {
+		t.Fatalf("ExportTarget() error = %v", err)
+	}
+	if result.Readme || result.Skipped != 3 || len(targetCommits(t, targetPath)) != 3 {
+		t.Errorf("Expected nothing to change, got %+v", result)
+	}
+}
+
+func Test_ExportTarget_ReadmeTemplate(t *testing.T) {
+	sourcePath := prepareReadmeFixture(t)
+
+	templatePath := filepath.Join(t.TempDir(), "README.md.tmpl")
+	if err := os.WriteFile(templatePath, []byte("{{.Target}}: {{.Commits}} commits on {{.ActiveDays}} days\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write template: %v", err)
+	}
+	brokenPath := filepath.Join(t.TempDir(), "broken.tmpl")
+	if err := os.WriteFile(brokenPath, []byte("{{.Unknown}}\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write template: %v", err)
+	}
+
+	targetPath := prepareTargetRepository(t)
+	cfg := buildConfig(t, []string{sourcePath}, config_model.NewTargetBuilder("github", targetPath).WithAuthor("Test User", "test@personal.example.com").WithReadme(templatePath))
+	if _, err := ExportTarget(cfg, cfg.Targets()[0]); err != nil {
+		t.Fatalf("ExportTarget() error = %v", err)
+	}
+	if readme := headFiles(t, targetPath)[ReadmePath]; readme != "github: 3 commits on 2 days\n" {
+		t.Errorf("Expected the custom readme, got %q", readme)
+	}
+
+	// Broken templates are refused with the config, before anything is
+	// mirrored
+	for _, path := range []string{brokenPath, filepath.Join(t.TempDir(), "missing.tmpl")} {
+		_, err := config_model.NewConfigBuilder().
+			WithDBPath("/tmp/test.db").
+			WithTrackedAuthor("Test User", []string{"test@example.com"}).
+			WithTrackedRepos([]string{sourcePath}).
+			WithTarget(config_model.NewTargetBuilder("github", prepareTargetRepository(t)).
+				WithAuthor("Test User", "test@personal.example.com").
+				WithReadme(path)).
+			Build()
+		if !errors.Is(err, internal_errors.ErrInvalidConfig) {
+			t.Errorf("Expected ErrInvalidConfig for %s, got %v", path, err)
+		}
+	}
+}
+
+func Test_ExportTarget_NoReadme(t *testing.T) {
+	cfg, targetPath := prepareExportFixture(t)
+
+	result, err := ExportTarget(cfg, cfg.Targets()[0])
+	if err != nil {
+		t.Fatalf("ExportTarget() error = %v", err)
+	}
+	files := headFiles(t, targetPath)
+	if result.Readme || !reflect.DeepEqual(files, map[string]string{}) {
+		t.Errorf("Expected no readme by default, got %v", files)
+	}
+}
@@ -0,0 +1,318 @@
+package export_handler
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-git/go-git/v6"
+	"github.com/go-git/go-git/v6/plumbing"
+	"github.com/go-git/go-git/v6/plumbing/format/index"
+	"github.com/go-git/go-git/v6/plumbing/object"
+
+	"github.com/HideyoshiNakazone/tracko/lib/config_model"
+	"github.com/HideyoshiNakazone/tracko/lib/internal_errors"
+	"github.com/HideyoshiNakazone/tracko/lib/sign_handler"
+)
+
+// BackupRefPrefix is where the previous head of a target branch is kept
+// before it is rewritten.
+const BackupRefPrefix = "refs/tracko/backup/"
+
+// RewriteResult counts the commits removed from and replayed on the target
+// branch. Backup is the ref holding the previous head, empty when nothing was
+// rewritten.
+type RewriteResult struct {
+	Target  string
+	Removed int
+	Kept    int
+	Backup  string
+	Export  *ExportResult
+}
+
+// Revert removes the commits mirrored on or after since from the target
+// branch, a zero since removes every mirrored commit. Only commits listed in
+// the mapping table are removed, any other commit after the first removed
+// one is replayed with a new parent. The previous head is kept under a backup
+// ref.
+func Revert(target config_model.ConfigTargetModel, since time.Time) (*RewriteResult, error) {
+	if target.Layout().Partitioned() {
+		return nil, internal_errors.ErrPartitionedTarget
+	}
+	targetPath := target.LocalPath()
+
+	r, err := git.PlainOpen(targetPath)
+	if err != nil {
+		return nil, internal_errors.ErrInvalidTargetRepo
+	}
+
+	wt, err := r.Worktree()
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := wt.Status()
+	if err != nil {
+		return nil, err
+	}
+	if !status.IsClean() {
+		return nil, internal_errors.ErrDirtyTargetRepo
+	}
+
+	if err := checkoutBranch(r, wt, target); err != nil {
+		return nil, err
+	}
+
+	signer, err := sign_handler.NewSigner(target.Signing())
+	if err != nil {
+		return nil, err
+	}
+
+	mappingPath := MappingTablePath(targetPath, target.Name())
+	table, err := LoadMappingTable(r, mappingPath)
+	if err != nil {
+		return nil, err
+	}
+
+	mirrored := map[string]bool{}
+	for _, targetHash := range table.Entries {
+		mirrored[targetHash] = true
+	}
+
+	history, err := firstParentHistory(r)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RewriteResult{Target: target.Name()}
+
+	first := -1
+	for i, c := range history {
+		if isReverted(c, mirrored, since) {
+			first = i
+			break
+		}
+	}
+	if first == -1 {
+		return result, nil
+	}
+
+	newHead := plumbing.ZeroHash
+	if first > 0 {
+		newHead = history[first-1].Hash
+	}
+
+	for _, c := range history[first:] {
+		if isReverted(c, mirrored, since) {
+			result.Removed++
+			continue
+		}
+		if c.NumParents() > 1 {
+			return nil, fmt.Errorf("%w: cannot replay merge commit %s", internal_errors.ErrNonLinearTarget, c.Hash)
+		}
+
+		newHead, err = replayCommit(r, c, newHead, signer)
+		if err != nil {
+			return nil, err
+		}
+		result.Kept++
+	}
+
+	oldHead := history[len(history)-1]
+	if result.Backup, err = createBackupRef(r, target.Name(), oldHead.Hash); err != nil {
+		return nil, err
+	}
+
+	if err := resetBranch(r, wt, targetPath, oldHead, newHead); err != nil {
+		return nil, err
+	}
+
+	table, err = RebuildMappingTable(r)
+	if err != nil {
+		return nil, err
+	}
+	if err := table.Save(mappingPath); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Rebuild removes every mirrored commit from the target branch and exports
+// again with the current config, the previous head is kept under a backup ref.
+func Rebuild(cfg *config_model.ConfigModel, target config_model.ConfigTargetModel) (*RewriteResult, error) {
+	result, err := Revert(target, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Export, err = ExportTarget(cfg, target); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func isReverted(c *object.Commit, mirrored map[string]bool, since time.Time) bool {
+	if !mirrored[c.Hash.String()] {
+		return false
+	}
+	return since.IsZero() || c.Author.When.Format(config_model.DateFormat) >= since.Format(config_model.DateFormat)
+}
+
+// firstParentHistory returns the first parent chain of HEAD, oldest first.
+func firstParentHistory(r *git.Repository) ([]*object.Commit, error) {
+	head, err := headHash(r)
+	if err != nil || head == "" {
+		return nil, err
+	}
+
+	c, err := r.CommitObject(plumbing.NewHash(head))
+	if err != nil {
+		return nil, err
+	}
+
+	history := []*object.Commit{c}
+	for c.NumParents() > 0 {
+		if c, err = c.Parent(0); err != nil {
+			return nil, err
+		}
+		history = append(history, c)
+	}
+
+	for i, j := 0, len(history)-1; i < j; i, j = i+1, j-1 {
+		history[i], history[j] = history[j], history[i]
+	}
+	return history, nil
+}
+
+// replayCommit recreates c on top of parent, keeping its message and
+// signatures. The signature of the original commit no longer applies, the
+// replayed commit is signed again when the target has a signer.
+func replayCommit(r *git.Repository, c *object.Commit, parent plumbing.Hash, signer git.Signer) (plumbing.Hash, error) {
+	oldParentTree := plumbing.ZeroHash
+	if c.NumParents() > 0 {
+		oldParent, err := c.Parent(0)
+		if err != nil {
+			return plumbing.ZeroHash, err
+		}
+		oldParentTree = oldParent.TreeHash
+	}
+
+	newParentTree := plumbing.ZeroHash
+	var parents []plumbing.Hash
+	if !parent.IsZero() {
+		newParent, err := r.CommitObject(parent)
+		if err != nil {
+			return plumbing.ZeroHash, err
+		}
+		newParentTree = newParent.TreeHash
+		parents = []plumbing.Hash{parent}
+	}
+
+	trees := make([]flatTree, 3)
+	for i, hash := range []plumbing.Hash{oldParentTree, c.TreeHash, newParentTree} {
+		tree, err := readFlatTree(r, hash)
+		if err != nil {
+			return plumbing.ZeroHash, err
+		}
+		trees[i] = tree
+	}
+
+	files, err := replayTree(r, trees[0], trees[1], trees[2])
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	treeHash, err := writeFlatTree(r, files)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	replayed := &object.Commit{
+		Author:       c.Author,
+		Committer:    c.Committer,
+		Message:      c.Message,
+		TreeHash:     treeHash,
+		ParentHashes: parents,
+	}
+
+	if signer != nil {
+		unsigned := &plumbing.MemoryObject{}
+		if err := replayed.EncodeWithoutSignature(unsigned); err != nil {
+			return plumbing.ZeroHash, err
+		}
+		reader, err := unsigned.Reader()
+		if err != nil {
+			return plumbing.ZeroHash, err
+		}
+		signature, err := signer.Sign(reader)
+		if err != nil {
+			return plumbing.ZeroHash, err
+		}
+		replayed.PGPSignature = string(signature)
+	}
+
+	obj := r.Storer.NewEncodedObject()
+	if err := replayed.Encode(obj); err != nil {
+		return plumbing.ZeroHash, err
+	}
+	return r.Storer.SetEncodedObject(obj)
+}
+
+// createBackupRef points a new ref under BackupRefPrefix at head, named after
+// the target and the current time.
+func createBackupRef(r *git.Repository, targetName string, head plumbing.Hash) (string, error) {
+	base := BackupRefPrefix + targetName + "/" + time.Now().UTC().Format("20060102T150405Z")
+
+	name := plumbing.ReferenceName(base)
+	for i := 1; ; i++ {
+		if _, err := r.Reference(name, false); err != nil {
+			break
+		}
+		name = plumbing.ReferenceName(fmt.Sprintf("%s-%d", base, i))
+	}
+
+	if err := r.Storer.SetReference(plumbing.NewHashReference(name, head)); err != nil {
+		return "", err
+	}
+	return name.String(), nil
+}
+
+// resetBranch moves the checked out branch to newHead and updates the
+// worktree to match. A zero newHead leaves the branch without commits.
+func resetBranch(r *git.Repository, wt *git.Worktree, targetPath string, oldHead *object.Commit, newHead plumbing.Hash) error {
+	if !newHead.IsZero() {
+		return wt.Reset(&git.ResetOptions{Commit: newHead, Mode: git.HardReset})
+	}
+
+	head, err := r.Storer.Reference(plumbing.HEAD)
+	if err != nil {
+		return err
+	}
+	if err := r.Storer.RemoveReference(head.Target()); err != nil {
+		return err
+	}
+
+	files, err := readFlatTree(r, oldHead.TreeHash)
+	if err != nil {
+		return err
+	}
+	for filePath := range files {
+		if err := os.Remove(filepath.Join(targetPath, filepath.FromSlash(filePath))); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		removeEmptyParents(targetPath, filepath.Dir(filepath.Join(targetPath, filepath.FromSlash(filePath))))
+	}
+
+	return r.Storer.SetIndex(&index.Index{Version: 2})
+}
+
+func removeEmptyParents(root string, dir string) {
+	for dir != root && len(dir) > len(root) {
+		if err := os.Remove(dir); err != nil {
+			return
+		}
+		dir = filepath.Dir(dir)
+	}
+}
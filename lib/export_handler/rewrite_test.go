@@ -0,0 +1,308 @@
+package export_handler
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v6"
+	"github.com/go-git/go-git/v6/plumbing"
+	"github.com/go-git/go-git/v6/plumbing/object"
+
+	"github.com/HideyoshiNakazone/tracko/lib/config_model"
+	"github.com/HideyoshiNakazone/tracko/lib/internal_errors"
+	"github.com/HideyoshiNakazone/tracko/lib/repo"
+)
+
+const dateTemplate = "{{.Date.Format \"2006-01-02\"}}"
+
+func commitManually(t *testing.T, targetPath string, name string, content string) plumbing.Hash {
+	t.Helper()
+
+	r, err := git.PlainOpen(targetPath)
+	if err != nil {
+		t.Fatalf("Failed to open repository: %v", err)
+	}
+	wt, err := r.Worktree()
+	if err != nil {
+		t.Fatalf("Failed to open worktree: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(targetPath, name), []byte(content), 0o644); err != nil {
+		t.Fatalf("Failed to write %s: %v", name, err)
+	}
+	if _, err := wt.Add(name); err != nil {
+		t.Fatalf("Failed to add %s: %v", name, err)
+	}
+
+	signature := &object.Signature{Name: "Maintainer", Email: "maintainer@example.com", When: fixtureBase.AddDate(0, 1, 0)}
+	hash, err := wt.Commit("docs: add "+name, &git.CommitOptions{Author: signature, Committer: signature})
+	if err != nil {
+		t.Fatalf("Failed to commit %s: %v", name, err)
+	}
+	return hash
+}
+
+func targetHead(t *testing.T, targetPath string) plumbing.Hash {
+	t.Helper()
+
+	r, err := git.PlainOpen(targetPath)
+	if err != nil {
+		t.Fatalf("Failed to open repository: %v", err)
+	}
+	head, err := r.Head()
+	if err != nil {
+		t.Fatalf("Failed to read head: %v", err)
+	}
+	return head.Hash()
+}
+
+func prepareRewriteFixture(t *testing.T, content string) (*config_model.ConfigModel, string) {
+	t.Helper()
+
+	day := 24 * time.Hour
+	sourcePath := prepareSourceRepository(t, []repo.TestCommit{
+		{Author: "Test User", Email: "test@example.com", Message: "feat: one", When: fixtureBase},
+		{Author: "Test User", Email: "test@example.com", Message: "feat: two", When: fixtureBase.Add(day)},
+		{Author: "Test User", Email: "test@example.com", Message: "feat: three", When: fixtureBase.Add(2 * day)},
+	})
+	targetPath := prepareTargetRepository(t)
+
+	cfg := buildConfig(t, []string{sourcePath},
+		config_model.NewTargetBuilder(config_model.DefaultTargetName, targetPath).
+			WithAuthor("Test User", "test@personal.example.com").
+			WithMessageTemplate(dateTemplate).
+			WithContent(content),
+	)
+	if _, err := Export(cfg); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	return cfg, targetPath
+}
+
+func Test_Revert_Since(t *testing.T) {
+	cfg, targetPath := prepareRewriteFixture(t, config_model.ContentEmpty)
+	commitManually(t, targetPath, "README.md", "# Mirror\n")
+	oldHead := targetHead(t, targetPath)
+
+	result, err := Revert(cfg.Targets()[0], time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Revert() error = %v", err)
+	}
+
+	if result.Removed != 2 || result.Kept != 1 {
+		t.Errorf("Expected 2 removed and 1 kept, got %+v", result)
+	}
+
+	expected := []string{"2025-01-01", "docs: add README.md"}
+	if subjects := logSubjects(t, targetPath, ""); !reflect.DeepEqual(subjects, expected) {
+		t.Errorf("Expected subjects %v, got %v", expected, subjects)
+	}
+
+	r, err := git.PlainOpen(targetPath)
+	if err != nil {
+		t.Fatalf("Failed to open repository: %v", err)
+	}
+	backup, err := r.Reference(plumbing.ReferenceName(result.Backup), false)
+	if err != nil || !strings.HasPrefix(result.Backup, BackupRefPrefix+config_model.DefaultTargetName+"/") {
+		t.Fatalf("Expected backup ref %q, got error %v", result.Backup, err)
+	}
+	if backup.Hash() != oldHead {
+		t.Errorf("Expected backup ref at %s, got %s", oldHead, backup.Hash())
+	}
+
+	if files := headFiles(t, targetPath); files["README.md"] != "# Mirror\n" {
+		t.Errorf("Expected the manual commit to keep README.md, got %v", files)
+	}
+
+	// The reverted commits are gone from the mapping, exporting mirrors them again
+	exported, err := Export(cfg)
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if exported[0].Mirrored != 2 || exported[0].Skipped != 1 {
+		t.Errorf("Expected 2 mirrored and 1 skipped, got %+v", exported[0])
+	}
+}
+
+func Test_Revert_All(t *testing.T) {
+	cfg, targetPath := prepareRewriteFixture(t, config_model.ContentActivityLog)
+
+	result, err := Revert(cfg.Targets()[0], time.Time{})
+	if err != nil {
+		t.Fatalf("Revert() error = %v", err)
+	}
+
+	if result.Removed != 3 || result.Kept != 0 {
+		t.Errorf("Expected 3 removed and 0 kept, got %+v", result)
+	}
+	if subjects := logSubjects(t, targetPath, ""); len(subjects) != 0 {
+		t.Errorf("Expected no commits in target, got %v", subjects)
+	}
+	if _, err := os.Stat(filepath.Join(targetPath, ActivityLogDir)); !os.IsNotExist(err) {
+		t.Errorf("Expected the activity log to be removed, got %v", err)
+	}
+}
+
+func Test_Revert_NothingToRevert(t *testing.T) {
+	cfg, targetPath := prepareRewriteFixture(t, config_model.ContentEmpty)
+	oldHead := targetHead(t, targetPath)
+
+	result, err := Revert(cfg.Targets()[0], time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Revert() error = %v", err)
+	}
+
+	if result.Removed != 0 || result.Backup != "" {
+		t.Errorf("Expected nothing reverted and no backup, got %+v", result)
+	}
+	if head := targetHead(t, targetPath); head != oldHead {
+		t.Errorf("Expected head to stay at %s, got %s", oldHead, head)
+	}
+}
+
+func Test_Revert_DirtyWorktree(t *testing.T) {
+	cfg, targetPath := prepareRewriteFixture(t, config_model.ContentEmpty)
+
+	if err := os.WriteFile(filepath.Join(targetPath, "notes.txt"), []byte("wip\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write notes: %v", err)
+	}
+
+	if _, err := Revert(cfg.Targets()[0], time.Time{}); !errors.Is(err, internal_errors.ErrDirtyTargetRepo) {
+		t.Errorf("Expected ErrDirtyTargetRepo, got %v", err)
+	}
+}
+
+func Test_Revert_DirtyWorktreeOnOtherBranch(t *testing.T) {
+	sourcePath := prepareSourceRepository(t, []repo.TestCommit{
+		{Author: "Test User", Email: "test@example.com", Message: "feat: one", When: fixtureBase},
+	})
+	targetPath := prepareTargetRepository(t)
+	cfg := buildConfig(t, []string{sourcePath},
+		config_model.NewTargetBuilder(config_model.DefaultTargetName, targetPath).
+			WithAuthor("Test User", "test@personal.example.com").
+			WithBranch("mirror"),
+	)
+	if _, err := Export(cfg); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	// The worktree is dirty on master, away from the target branch
+	r, _ := git.PlainOpen(targetPath)
+	wt, _ := r.Worktree()
+	if err := wt.Checkout(&git.CheckoutOptions{Branch: plumbing.Master, Create: true}); err != nil {
+		t.Fatalf("Failed to checkout master: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(targetPath, "notes.txt"), []byte("wip\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write notes: %v", err)
+	}
+
+	if _, err := Revert(cfg.Targets()[0], time.Time{}); !errors.Is(err, internal_errors.ErrDirtyTargetRepo) {
+		t.Fatalf("Expected ErrDirtyTargetRepo, got %v", err)
+	}
+	if head, err := r.Head(); err != nil || head.Name() != plumbing.Master {
+		t.Errorf("Expected the target to stay on master, got %v (%v)", head, err)
+	}
+	if subjects := logSubjects(t, targetPath, "mirror"); len(subjects) != 1 {
+		t.Errorf("Expected the mirror branch untouched, got %v", subjects)
+	}
+}
+
+func Test_Revert_ReplaysLedger(t *testing.T) {
+	day := 24 * time.Hour
+	backendPath := prepareSourceRepository(t, []repo.TestCommit{
+		{Author: "Test User", Email: "test@example.com", Message: "feat: one", When: fixtureBase},
+		{Author: "Test User", Email: "test@example.com", Message: "feat: three", When: fixtureBase.Add(2 * day)},
+	})
+	frontendPath := prepareSourceRepository(t, []repo.TestCommit{
+		{Author: "Test User", Email: "test@example.com", Message: "feat: two", When: fixtureBase.Add(day)},
+	})
+	targetPath := prepareTargetRepository(t)
+	target := config_model.NewTargetBuilder(config_model.DefaultTargetName, targetPath).
+		WithAuthor("Test User", "test@personal.example.com").
+		WithMessageTemplate(dateTemplate).
+		WithContent(config_model.ContentJSONLedger)
+
+	// The frontend is tracked later, so its older commit is mirrored last
+	if _, err := Export(buildConfig(t, []string{backendPath}, target)); err != nil {
+		t.Fatalf("First Export() error = %v", err)
+	}
+	cfg := buildConfig(t, []string{backendPath, frontendPath}, target)
+	if _, err := Export(cfg); err != nil {
+		t.Fatalf("Second Export() error = %v", err)
+	}
+
+	result, err := Revert(cfg.Targets()[0], time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Revert() error = %v", err)
+	}
+	if result.Removed != 1 || result.Kept != 1 {
+		t.Errorf("Expected 1 removed and 1 kept, got %+v", result)
+	}
+
+	expected := []string{"2025-01-01", "2025-01-02"}
+	if subjects := logSubjects(t, targetPath, ""); !reflect.DeepEqual(subjects, expected) {
+		t.Errorf("Expected subjects %v, got %v", expected, subjects)
+	}
+
+	var entries []LedgerEntry
+	if err := json.Unmarshal([]byte(headFiles(t, targetPath)[JSONLedgerPath]), &entries); err != nil {
+		t.Fatalf("Failed to parse ledger: %v", err)
+	}
+	dates := []string{}
+	for _, entry := range entries {
+		dates = append(dates, entry.Date[:len(config_model.DateFormat)])
+	}
+	if !reflect.DeepEqual(dates, expected) {
+		t.Errorf("Expected ledger dates %v, got %v", expected, dates)
+	}
+}
+
+func Test_Rebuild(t *testing.T) {
+	sourcePath := prepareSourceRepository(t, []repo.TestCommit{
+		{Author: "Test User", Email: "test@example.com", Message: "feat: mine", When: fixtureBase},
+		{Author: "Other User", Email: "other@example.com", Message: "feat: theirs", When: fixtureBase.Add(time.Hour)},
+	})
+	targetPath := prepareTargetRepository(t)
+
+	buildWithEmails := func(emails []string) *config_model.ConfigModel {
+		cfg, err := config_model.NewConfigBuilder().
+			WithDBPath("/tmp/test.db").
+			WithTrackedAuthor("Test User", emails).
+			WithTrackedRepos([]string{sourcePath}).
+			WithTarget(config_model.NewTargetBuilder(config_model.DefaultTargetName, targetPath).
+				WithAuthor("Test User", "test@personal.example.com").
+				WithMessageTemplate("{{.Type}} {{.Date.Format \"15:04\"}}")).
+			Build()
+		if err != nil {
+			t.Fatalf("Failed to build config: %v", err)
+		}
+		return cfg
+	}
+
+	// A wrong email exported someone else's commit
+	wrong := buildWithEmails([]string{"test@example.com", "other@example.com"})
+	if _, err := Export(wrong); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	fixed := buildWithEmails([]string{"test@example.com"})
+	result, err := Rebuild(fixed, fixed.Targets()[0])
+	if err != nil {
+		t.Fatalf("Rebuild() error = %v", err)
+	}
+
+	if result.Removed != 2 || result.Export.Mirrored != 1 {
+		t.Errorf("Expected 2 removed and 1 mirrored, got %+v %+v", result, result.Export)
+	}
+
+	expected := []string{"feat 10:00"}
+	if subjects := logSubjects(t, targetPath, ""); !reflect.DeepEqual(subjects, expected) {
+		t.Errorf("Expected subjects %v, got %v", expected, subjects)
+	}
+}
@@ -0,0 +1,154 @@
+package export_handler
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-git/go-git/v6"
+	"github.com/go-git/go-git/v6/plumbing/object"
+
+	"github.com/HideyoshiNakazone/tracko/lib/config_model"
+	"github.com/HideyoshiNakazone/tracko/lib/sign_handler"
+)
+
+func targetCommits(t *testing.T, targetPath string) []*object.Commit {
+	t.Helper()
+
+	r, err := git.PlainOpen(targetPath)
+	if err != nil {
+		t.Fatalf("Failed to open repository: %v", err)
+	}
+	ref, err := r.Head()
+	if err != nil {
+		t.Fatalf("Failed to read head: %v", err)
+	}
+	iter, err := r.Log(&git.LogOptions{From: ref.Hash()})
+	if err != nil {
+		t.Fatalf("Failed to read log: %v", err)
+	}
+
+	commits := []*object.Commit{}
+	iter.ForEach(func(c *object.Commit) error {
+		commits = append(commits, c)
+		return nil
+	})
+	return commits
+}
+
+// verifyWithGit runs git verify-commit against an allowed signers file, the
+// same check hosting providers run for SSH signatures.
+func verifyWithGit(t *testing.T, targetPath string, publicKey string, c *object.Commit) {
+	t.Helper()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		t.Skip("ssh-keygen is not installed")
+	}
+
+	allowedSigners := filepath.Join(t.TempDir(), "allowed_signers")
+	if err := os.WriteFile(allowedSigners, []byte(c.Author.Email+" "+publicKey), 0o644); err != nil {
+		t.Fatalf("Failed to write allowed signers: %v", err)
+	}
+
+	command := exec.Command("git",
+		"-c", "gpg.format=ssh",
+		"-c", "gpg.ssh.allowedSignersFile="+allowedSigners,
+		"verify-commit", c.Hash.String(),
+	)
+	command.Dir = targetPath
+	command.Env = append(os.Environ(), "GIT_CONFIG_GLOBAL=/dev/null", "GIT_CONFIG_NOSYSTEM=1")
+	if output, err := command.CombinedOutput(); err != nil {
+		t.Errorf("git verify-commit %s failed: %v: %s", c.Hash, err, output)
+	}
+}
+
+func Test_ExportTarget_OpenPGPSigning(t *testing.T) {
+	key, cleanup, err := sign_handler.PrepareTestOpenPGPKey("secret")
+	if err != nil {
+		t.Fatalf("Failed to prepare key: %v", err)
+	}
+	t.Cleanup(*cleanup)
+	t.Setenv("TRACKO_TEST_SIGNING_PASSPHRASE", "secret")
+
+	fixture, targetPath := prepareExportFixture(t)
+	cfg := buildConfig(t, fixture.TrackedRepos(),
+		config_model.NewTargetBuilder(config_model.DefaultTargetName, targetPath).
+			WithAuthor("Test User", "test@personal.example.com").
+			WithSigning(config_model.SigningFormatOpenPGP, key.Path, "TRACKO_TEST_SIGNING_PASSPHRASE"),
+	)
+
+	if _, err := Export(cfg); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	commits := targetCommits(t, targetPath)
+	if len(commits) != 2 {
+		t.Fatalf("Expected 2 commits in target, got %d", len(commits))
+	}
+	for _, c := range commits {
+		if _, err := c.Verify(key.PublicKey); err != nil {
+			t.Errorf("Expected commit %s to be signed, got %v", c.Hash, err)
+		}
+	}
+}
+
+func Test_ExportTarget_SSHSigning(t *testing.T) {
+	key, cleanup, err := sign_handler.PrepareTestSSHKey("ed25519", "")
+	if err != nil {
+		t.Fatalf("Failed to prepare key: %v", err)
+	}
+	t.Cleanup(*cleanup)
+
+	fixture, targetPath := prepareExportFixture(t)
+	cfg := buildConfig(t, fixture.TrackedRepos(),
+		config_model.NewTargetBuilder(config_model.DefaultTargetName, targetPath).
+			WithAuthor("Test User", "test@personal.example.com").
+			WithSigning(config_model.SigningFormatSSH, key.Path, ""),
+	)
+
+	if _, err := Export(cfg); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	// Reverting replays the unsigned manual commit, it is signed again like
+	// every commit tracko writes
+	commitManually(t, targetPath, "README.md", "# Mirror\n")
+	if _, err := Revert(cfg.Targets()[0], fixtureBase); err != nil {
+		t.Fatalf("Revert() error = %v", err)
+	}
+	if _, err := Export(cfg); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	commits := targetCommits(t, targetPath)
+	if len(commits) != 3 {
+		t.Fatalf("Expected 3 commits in target, got %d", len(commits))
+	}
+	for _, c := range commits {
+		if c.PGPSignature == "" {
+			t.Errorf("Expected commit %s to be signed", c.Hash)
+			continue
+		}
+		verifyWithGit(t, targetPath, key.PublicKey, c)
+	}
+}
+
+func Test_ExportTarget_InvalidSigningKey(t *testing.T) {
+	fixture, targetPath := prepareExportFixture(t)
+	cfg := buildConfig(t, fixture.TrackedRepos(),
+		config_model.NewTargetBuilder(config_model.DefaultTargetName, targetPath).
+			WithAuthor("Test User", "test@personal.example.com").
+			WithSigning(config_model.SigningFormatSSH, filepath.Join(t.TempDir(), "missing"), ""),
+	)
+
+	if _, err := Export(cfg); err == nil {
+		t.Error("Expected an error for a missing signing key, got nil")
+	}
+	if count := len(logSubjects(t, targetPath, "")); count != 0 {
+		t.Errorf("Expected no commits in target, got %d", count)
+	}
+}
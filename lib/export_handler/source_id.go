@@ -0,0 +1,68 @@
+package export_handler
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// SourceTrailerKey is the trailer appended to every mirrored commit, it holds
+// the group id of the source commits that produced it.
+const SourceTrailerKey = "Tracko-Source-Id"
+
+// SourceID returns an opaque identifier for a source commit, so that the
+// target repository never exposes the original commit hash.
+func SourceID(sourceHash string) string {
+	sum := sha256.Sum256([]byte("tracko:" + sourceHash))
+	return hex.EncodeToString(sum[:])
+}
+
+// GroupID returns an opaque identifier for the source commits written as a
+// single target commit, the source id itself for a single commit, so that
+// aggregated commits do not give away how many commits they stand for.
+func GroupID(sourceIDs []string) string {
+	if len(sourceIDs) == 1 {
+		return sourceIDs[0]
+	}
+	sorted := slices.Clone(sourceIDs)
+	slices.Sort(sorted)
+	sum := sha256.Sum256([]byte("tracko-group:" + strings.Join(sorted, ",")))
+	return hex.EncodeToString(sum[:])
+}
+
+func FormatSourceTrailer(sourceID string) string {
+	return fmt.Sprintf("%s: %s", SourceTrailerKey, sourceID)
+}
+
+// ParseSourceTrailer looks for the source id trailer in a commit message.
+func ParseSourceTrailer(message string) (string, bool) {
+	sourceIDs := ParseSourceTrailers(message)
+	if len(sourceIDs) == 0 {
+		return "", false
+	}
+	return sourceIDs[0], true
+}
+
+// ParseSourceTrailers returns every source id trailer of a commit message,
+// aggregated commits written by earlier versions carry one trailer per source
+// commit they stand for.
+func ParseSourceTrailers(message string) []string {
+	prefix := SourceTrailerKey + ":"
+
+	sourceIDs := []string{}
+	scanner := bufio.NewScanner(strings.NewReader(message))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, prefix) {
+			continue
+		}
+		sourceID := strings.TrimSpace(strings.TrimPrefix(line, prefix))
+		if sourceID != "" {
+			sourceIDs = append(sourceIDs, sourceID)
+		}
+	}
+	return sourceIDs
+}
@@ -0,0 +1,84 @@
+package export_handler
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_SourceID(t *testing.T) {
+	id := SourceID("0123456789abcdef0123456789abcdef01234567")
+
+	if len(id) != 64 {
+		t.Errorf("Expected a 64 character id, got %d", len(id))
+	}
+
+	if id == SourceID("fedcba9876543210fedcba9876543210fedcba98") {
+		t.Error("Expected different hashes to produce different ids")
+	}
+
+	if id != SourceID("0123456789abcdef0123456789abcdef01234567") {
+		t.Error("Expected the same hash to always produce the same id")
+	}
+}
+
+func Test_GroupID(t *testing.T) {
+	single := SourceID("0123456789abcdef0123456789abcdef01234567")
+	other := SourceID("fedcba9876543210fedcba9876543210fedcba98")
+
+	if id := GroupID([]string{single}); id != single {
+		t.Errorf("Expected a single commit to keep its source id, got %s", id)
+	}
+
+	id := GroupID([]string{single, other})
+	if len(id) != 64 || id == single || id == other {
+		t.Errorf("Expected an opaque 64 character id, got %s", id)
+	}
+	if id != GroupID([]string{other, single}) {
+		t.Error("Expected the group id not to depend on the order of the source ids")
+	}
+}
+
+func Test_ParseSourceTrailer(t *testing.T) {
+	tests := []struct {
+		name     string
+		message  string
+		expected string
+		found    bool
+	}{
+		{"With trailer", "Mirrored contribution\n\n" + FormatSourceTrailer("abc123") + "\n", "abc123", true},
+		{"Without trailer", "Some manual commit\n", "", false},
+		{"Empty trailer", "Mirrored contribution\n\nTracko-Source-Id: \n", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sourceID, ok := ParseSourceTrailer(tt.message)
+			if ok != tt.found {
+				t.Errorf("Expected found = %v, got %v", tt.found, ok)
+			}
+			if sourceID != tt.expected {
+				t.Errorf("Expected source id %q, got %q", tt.expected, sourceID)
+			}
+		})
+	}
+}
+
+func Test_ParseSourceTrailers(t *testing.T) {
+	tests := []struct {
+		name     string
+		message  string
+		expected []string
+	}{
+		{"Single trailer", "Mirrored contribution\n\n" + FormatSourceTrailer("abc123") + "\n", []string{"abc123"}},
+		{"Multiple trailers", "Mirrored contribution\n\n" + FormatSourceTrailer("abc123") + "\n" + FormatSourceTrailer("def456") + "\n", []string{"abc123", "def456"}},
+		{"Without trailer", "Some manual commit\n", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if sourceIDs := ParseSourceTrailers(tt.message); !reflect.DeepEqual(sourceIDs, tt.expected) {
+				t.Errorf("Expected source ids %v, got %v", tt.expected, sourceIDs)
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package export_handler
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/HideyoshiNakazone/tracko/lib/commit_message"
+	"github.com/HideyoshiNakazone/tracko/lib/config_model"
+	"github.com/HideyoshiNakazone/tracko/lib/repo"
+)
+
+// exportGroup is a set of source commits written as a single target commit.
+// When existing is set the group is folded into a target commit that was
+// already written for the same day and nothing new is committed.
+type exportGroup struct {
+	records  []repo.CommitRecord
+	existing string
+}
+
+func (g exportGroup) sourceIDs() []string {
+	sourceIDs := make([]string, len(g.records))
+	for i, record := range g.records {
+		sourceIDs[i] = SourceID(record.Hash)
+	}
+	return sourceIDs
+}
+
+// last is the newest source commit of the group, the target commit takes
+// its author and date.
+func (g exportGroup) last() repo.CommitRecord {
+	return g.records[len(g.records)-1]
+}
+
+// byRepo splits the group by source repo, keeping the order repos first
+// appear in.
+func (g exportGroup) byRepo() []exportGroup {
+	var groups []exportGroup
+	index := map[string]int{}
+	for _, record := range g.records {
+		i, ok := index[record.RepoPath]
+		if !ok {
+			i = len(groups)
+			index[record.RepoPath] = i
+			groups = append(groups, exportGroup{})
+		}
+		groups[i].records = append(groups[i].records, record)
+	}
+	return groups
+}
+
+func (g exportGroup) templateData() commit_message.TemplateData {
+	data := commit_message.TemplateData{
+		Date:    g.last().When,
+		Commits: len(g.records),
+	}
+
+	aliases := []string{}
+	hashes := []string{}
+	for i, record := range g.records {
+		data.Additions += record.Additions
+		data.Deletions += record.Deletions
+		data.FilesChanged += record.FilesChanged()
+
+		alias := filepath.Base(record.RepoPath)
+		if !slices.Contains(aliases, alias) {
+			aliases = append(aliases, alias)
+		}
+		hashes = append(hashes, record.Hash)
+
+		if i == 0 {
+			data.Type = record.ConventionalType()
+		} else if data.Type != record.ConventionalType() {
+			data.Type = ""
+		}
+	}
+	data.RepoAlias = strings.Join(aliases, ", ")
+	data.SourceID = SourceID(strings.Join(hashes, ","))
+
+	return data
+}
+
+// planGroups splits the source commits, sorted oldest first, into the target
+// commits to write. dailyCap is the maximum number of target commits per day,
+// zero writes one target commit per source commit. Commits already in the
+// mapping table are counted as skipped. Days that already reached the cap in
+// a previous export get their new commits folded into the latest target
+// commit of that day, so that exporting again never exceeds the cap.
+func planGroups(records []repo.CommitRecord, table *MappingTable, dailyCap int) ([]exportGroup, int) {
+	var groups []exportGroup
+	skipped := 0
+
+	for _, day := range groupByDay(records) {
+		existing := []string{}
+		unmapped := []repo.CommitRecord{}
+		for _, record := range day {
+			targetHash, ok := table.Entries[SourceID(record.Hash)]
+			if !ok {
+				unmapped = append(unmapped, record)
+				continue
+			}
+			skipped++
+			if !slices.Contains(existing, targetHash) {
+				existing = append(existing, targetHash)
+			}
+		}
+
+		if len(unmapped) == 0 {
+			continue
+		}
+
+		if dailyCap == 0 {
+			for _, record := range unmapped {
+				groups = append(groups, exportGroup{records: []repo.CommitRecord{record}})
+			}
+			continue
+		}
+
+		allowance := dailyCap - len(existing)
+		if allowance <= 0 {
+			groups = append(groups, exportGroup{records: unmapped, existing: existing[len(existing)-1]})
+			continue
+		}
+
+		for _, chunk := range splitEvenly(unmapped, min(allowance, len(unmapped))) {
+			groups = append(groups, exportGroup{records: chunk})
+		}
+	}
+
+	return groups, skipped
+}
+
+// groupByDay splits records sorted oldest first by calendar day, as seen in
+// the timezone each commit was made in, keeping the order days first appear.
+func groupByDay(records []repo.CommitRecord) [][]repo.CommitRecord {
+	var days [][]repo.CommitRecord
+	index := map[string]int{}
+	for _, record := range records {
+		day := dayKey(record.When)
+		i, ok := index[day]
+		if !ok {
+			i = len(days)
+			index[day] = i
+			days = append(days, []repo.CommitRecord{})
+		}
+		days[i] = append(days[i], record)
+	}
+	return days
+}
+
+func dayKey(when time.Time) string {
+	return when.Format(config_model.DateFormat)
+}
+
+// splitEvenly splits records into n contiguous chunks whose sizes differ by
+// at most one, earlier chunks take the remainder.
+func splitEvenly(records []repo.CommitRecord, n int) [][]repo.CommitRecord {
+	chunks := make([][]repo.CommitRecord, 0, n)
+	size, remainder := len(records)/n, len(records)%n
+
+	start := 0
+	for i := range n {
+		end := start + size
+		if i < remainder {
+			end++
+		}
+		chunks = append(chunks, records[start:end])
+		start = end
+	}
+	return chunks
+}
@@ -0,0 +1,261 @@
+package export_handler
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v6"
+	"github.com/go-git/go-git/v6/plumbing"
+	"github.com/go-git/go-git/v6/plumbing/filemode"
+	"github.com/go-git/go-git/v6/plumbing/object"
+)
+
+// flatTree maps every file path of a tree to its entry, paths use forward
+// slashes as in git.
+type flatTree map[string]object.TreeEntry
+
+func readFlatTree(r *git.Repository, treeHash plumbing.Hash) (flatTree, error) {
+	files := flatTree{}
+	if treeHash.IsZero() {
+		return files, nil
+	}
+
+	tree, err := r.TreeObject(treeHash)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tree.Files().ForEach(func(file *object.File) error {
+		files[file.Name] = object.TreeEntry{Name: file.Name, Mode: file.Mode, Hash: file.Hash}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// writeFlatTree stores the nested tree objects for files and returns the hash
+// of the root tree.
+func writeFlatTree(r *git.Repository, files flatTree) (plumbing.Hash, error) {
+	children := map[string]flatTree{}
+	entries := []object.TreeEntry{}
+
+	for filePath, entry := range files {
+		dir, rest, nested := strings.Cut(filePath, "/")
+		if !nested {
+			entries = append(entries, object.TreeEntry{Name: filePath, Mode: entry.Mode, Hash: entry.Hash})
+			continue
+		}
+		if children[dir] == nil {
+			children[dir] = flatTree{}
+		}
+		children[dir][rest] = entry
+	}
+
+	for dir, child := range children {
+		hash, err := writeFlatTree(r, child)
+		if err != nil {
+			return plumbing.ZeroHash, err
+		}
+		entries = append(entries, object.TreeEntry{Name: dir, Mode: filemode.Dir, Hash: hash})
+	}
+	sort.Sort(object.TreeEntrySorter(entries))
+
+	obj := r.Storer.NewEncodedObject()
+	if err := (&object.Tree{Entries: entries}).Encode(obj); err != nil {
+		return plumbing.ZeroHash, err
+	}
+	return r.Storer.SetEncodedObject(obj)
+}
+
+// replayTree applies the changes a commit made to its original parent on top
+// of a new parent. Files written by the content strategies are only ever
+// appended to, so their new lines are appended to the new parent version
+// instead of replacing it, which drops whatever removed commits had added.
+func replayTree(r *git.Repository, oldParent, commit, newParent flatTree) (flatTree, error) {
+	result := flatTree{}
+	for filePath, entry := range newParent {
+		result[filePath] = entry
+	}
+
+	paths := map[string]bool{}
+	for filePath := range oldParent {
+		paths[filePath] = true
+	}
+	for filePath := range commit {
+		paths[filePath] = true
+	}
+
+	for filePath := range paths {
+		before, hadBefore := oldParent[filePath]
+		after, hasAfter := commit[filePath]
+		if hadBefore && hasAfter && before.Hash == after.Hash && before.Mode == after.Mode {
+			continue
+		}
+
+		if !hasAfter {
+			delete(result, filePath)
+			continue
+		}
+
+		current, hasCurrent := newParent[filePath]
+		if !isContentPath(filePath) || (!hadBefore && !hasCurrent) {
+			result[filePath] = after
+			continue
+		}
+
+		merged, err := mergeContentFile(r, filePath, before.Hash, after.Hash, current.Hash)
+		if err != nil {
+			return nil, err
+		}
+		result[filePath] = object.TreeEntry{Name: filePath, Mode: after.Mode, Hash: merged}
+	}
+
+	return result, nil
+}
+
+func isContentPath(filePath string) bool {
+	return filePath == JSONLedgerPath || filePath == CSVLedgerPath || path.Dir(filePath) == ActivityLogDir
+}
+
+// mergeContentFile appends what a commit added to a content file on top of
+// the version in the new parent. Changes that are not appends, like manual
+// edits, keep the version of the commit.
+func mergeContentFile(r *git.Repository, filePath string, before, after, current plumbing.Hash) (plumbing.Hash, error) {
+	contents := make([][]byte, 3)
+	for i, hash := range []plumbing.Hash{before, after, current} {
+		if hash.IsZero() {
+			continue
+		}
+		data, err := readBlob(r, hash)
+		if err != nil {
+			return plumbing.ZeroHash, err
+		}
+		contents[i] = data
+	}
+
+	var merged []byte
+	var ok bool
+	switch filePath {
+	case JSONLedgerPath:
+		merged, ok = mergeJSONLedger(contents[0], contents[1], contents[2])
+	case CSVLedgerPath:
+		merged, ok = mergeCSVLedger(contents[0], contents[1], contents[2])
+	default:
+		merged, ok = mergeLines(contents[0], contents[1], contents[2])
+	}
+	if !ok {
+		return after, nil
+	}
+
+	return writeBlob(r, merged)
+}
+
+func mergeLines(before, after, current []byte) ([]byte, bool) {
+	if !bytes.HasPrefix(after, before) {
+		return nil, false
+	}
+	return append(bytes.Clone(current), after[len(before):]...), true
+}
+
+func mergeJSONLedger(before, after, current []byte) ([]byte, bool) {
+	var entries [3][]LedgerEntry
+	for i, data := range [][]byte{before, after, current} {
+		if data == nil {
+			continue
+		}
+		if err := json.Unmarshal(data, &entries[i]); err != nil {
+			return nil, false
+		}
+	}
+
+	added, ok := appendedEntries(entries[0], entries[1])
+	if !ok {
+		return nil, false
+	}
+
+	merged, err := encodeJSONLedger(append(entries[2], added...))
+	return merged, err == nil
+}
+
+func mergeCSVLedger(before, after, current []byte) ([]byte, bool) {
+	var rows [3][][]string
+	for i, data := range [][]byte{before, after, current} {
+		records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+		if err != nil {
+			return nil, false
+		}
+		// The header is written with the first entry, only entries are merged
+		if len(records) > 0 {
+			records = records[1:]
+		}
+		rows[i] = records
+	}
+
+	added, ok := appendedEntries(rows[0], rows[1])
+	if !ok {
+		return nil, false
+	}
+
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+	if err := writer.WriteAll(append(append([][]string{LedgerColumns}, rows[2]...), added...)); err != nil {
+		return nil, false
+	}
+	return buffer.Bytes(), true
+}
+
+// appendedEntries returns the entries of after past those of before, as long
+// as after starts with every entry of before.
+func appendedEntries[T any](before, after []T) ([]T, bool) {
+	if len(after) < len(before) {
+		return nil, false
+	}
+	for i := range before {
+		if !reflect.DeepEqual(before[i], after[i]) {
+			return nil, false
+		}
+	}
+	return after[len(before):], true
+}
+
+func readBlob(r *git.Repository, hash plumbing.Hash) ([]byte, error) {
+	blob, err := r.BlobObject(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	reader, err := blob.Reader()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	return io.ReadAll(reader)
+}
+
+func writeBlob(r *git.Repository, data []byte) (plumbing.Hash, error) {
+	obj := r.Storer.NewEncodedObject()
+	obj.SetType(plumbing.BlobObject)
+
+	writer, err := obj.Writer()
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	if _, err := writer.Write(data); err != nil {
+		writer.Close()
+		return plumbing.ZeroHash, err
+	}
+	if err := writer.Close(); err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	return r.Storer.SetEncodedObject(obj)
+}
@@ -0,0 +1,129 @@
+package heatmap
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"time"
+
+	"github.com/HideyoshiNakazone/tracko/lib/config_model"
+)
+
+// Palette holds the fill of each activity level, from no commits to the
+// busiest days.
+type Palette [5]string
+
+// DefaultPalette is the green scale of GitHub contribution graphs.
+var DefaultPalette = Palette{"#ebedf0", "#9be9a8", "#40c463", "#30a14e", "#216e39"}
+
+const (
+	cellSize   = 10
+	cellStep   = 13
+	leftMargin = 30
+	topMargin  = 20
+	legendSize = 24
+	minWidth   = 160
+	textColor  = "#57606a"
+	fontFamily = "-apple-system, BlinkMacSystemFont, 'Segoe UI', Helvetica, Arial, sans-serif"
+)
+
+// Render writes an SVG heatmap of the days from from to to, both inclusive,
+// with a column per week starting on Sunday. counts are keyed by
+// config_model.DateFormat, days are shaded relative to the busiest one.
+func Render(w io.Writer, counts map[string]int, from time.Time, to time.Time, palette Palette) error {
+	first := truncateDay(from)
+	last := truncateDay(to)
+	if last.Before(first) {
+		return fmt.Errorf("heatmap range ends on %s before it starts on %s",
+			last.Format(config_model.DateFormat), first.Format(config_model.DateFormat))
+	}
+
+	start := first.AddDate(0, 0, -int(first.Weekday()))
+	weeks := int(last.Sub(start).Hours()/24)/7 + 1
+
+	busiest := 0
+	total := 0
+	for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+		count := counts[day.Format(config_model.DateFormat)]
+		busiest = max(busiest, count)
+		total += count
+	}
+
+	// Short ranges are widened to fit the legend
+	width := max(leftMargin+weeks*cellStep, minWidth)
+	height := topMargin + 7*cellStep + legendSize
+
+	b := bufio.NewWriter(w)
+	fmt.Fprintf(b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" role="img" aria-label="%d commits from %s to %s">`+"\n",
+		width, height, width, height, total, first.Format(config_model.DateFormat), last.Format(config_model.DateFormat))
+	fmt.Fprintf(b, `<g font-family="%s" font-size="9" fill="%s">`+"\n", fontFamily, textColor)
+
+	// Month labels over the first week holding the 1st of a month, or the
+	// first week when the range starts mid-month
+	for week := 0; week < weeks; week++ {
+		for weekday := 0; weekday < 7; weekday++ {
+			day := start.AddDate(0, 0, week*7+weekday)
+			if day.Before(first) || day.After(last) {
+				continue
+			}
+			if day.Day() == 1 || (day.Equal(first) && day.Day() <= 21) {
+				fmt.Fprintf(b, `<text x="%d" y="%d">%s</text>`+"\n", leftMargin+week*cellStep, topMargin-7, day.Format("Jan"))
+			}
+		}
+	}
+
+	for _, weekday := range []time.Weekday{time.Monday, time.Wednesday, time.Friday} {
+		fmt.Fprintf(b, `<text x="0" y="%d">%s</text>`+"\n", topMargin+int(weekday)*cellStep+cellSize-1, weekday.String()[:3])
+	}
+	fmt.Fprint(b, "</g>\n")
+
+	for week := 0; week < weeks; week++ {
+		for weekday := 0; weekday < 7; weekday++ {
+			day := start.AddDate(0, 0, week*7+weekday)
+			if day.Before(first) || day.After(last) {
+				continue
+			}
+
+			date := day.Format(config_model.DateFormat)
+			count := counts[date]
+			fmt.Fprintf(b, `<rect x="%d" y="%d" width="%d" height="%d" rx="2" fill="%s" data-date="%s" data-count="%d"><title>%s</title></rect>`+"\n",
+				leftMargin+week*cellStep, topMargin+weekday*cellStep, cellSize, cellSize,
+				html.EscapeString(palette[Level(count, busiest)]), date, count, html.EscapeString(describe(count, date)))
+		}
+	}
+
+	legendY := topMargin + 7*cellStep + 6
+	legendX := width - 5*cellStep - 30
+	fmt.Fprintf(b, `<g font-family="%s" font-size="9" fill="%s">`+"\n", fontFamily, textColor)
+	fmt.Fprintf(b, `<text x="%d" y="%d" text-anchor="end">Less</text>`+"\n", legendX-4, legendY+cellSize-1)
+	for level, fill := range palette {
+		fmt.Fprintf(b, `<rect x="%d" y="%d" width="%d" height="%d" rx="2" fill="%s"/>`+"\n", legendX+level*cellStep, legendY, cellSize, cellSize, html.EscapeString(fill))
+	}
+	fmt.Fprintf(b, `<text x="%d" y="%d">More</text>`+"\n", legendX+5*cellStep+1, legendY+cellSize-1)
+	fmt.Fprint(b, "</g>\n</svg>\n")
+
+	return b.Flush()
+}
+
+// Level returns the palette level of a day with count commits, relative to
+// the busiest day of the heatmap. Every day with commits is at least level 1.
+func Level(count int, busiest int) int {
+	if count <= 0 || busiest <= 0 {
+		return 0
+	}
+	return min(4, (count*4+busiest-1)/busiest)
+}
+
+func describe(count int, date string) string {
+	if count == 1 {
+		return "1 commit on " + date
+	}
+	return fmt.Sprintf("%d commits on %s", count, date)
+}
+
+// truncateDay returns the calendar day of t as midnight UTC, so that days can
+// be stepped through without daylight saving shifts.
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,190 @@
+package heatmap
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_Level(t *testing.T) {
+	tests := []struct {
+		count   int
+		busiest int
+		want    int
+	}{
+		{0, 10, 0},
+		{1, 10, 1},
+		{3, 10, 2},
+		{5, 10, 2},
+		{6, 10, 3},
+		{10, 10, 4},
+		{1, 1, 4},
+		{0, 0, 0},
+	}
+
+	for _, tt := range tests {
+		if got := Level(tt.count, tt.busiest); got != tt.want {
+			t.Errorf("Level(%d, %d) = %d, want %d", tt.count, tt.busiest, got, tt.want)
+		}
+	}
+}
+
+func Test_Render(t *testing.T) {
+	counts := map[string]int{"2025-01-01": 1, "2025-01-15": 4, "2024-12-31": 9}
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	var buffer bytes.Buffer
+	if err := Render(&buffer, counts, from, to, DefaultPalette); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	svg := buffer.String()
+	if !strings.Contains(svg, ">Jan</text>") {
+		t.Errorf("Expected a January label, got %s", svg)
+	}
+
+	days := map[string]string{}
+	decoder := xml.NewDecoder(&buffer)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Heatmap is not valid XML: %v", err)
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "rect" {
+			continue
+		}
+		var date, fill string
+		for _, attr := range start.Attr {
+			switch attr.Name.Local {
+			case "data-date":
+				date = attr.Value
+			case "fill":
+				fill = attr.Value
+			}
+		}
+		if date != "" {
+			days[date] = fill
+		}
+	}
+
+	if len(days) != 31 {
+		t.Fatalf("Expected a cell per day of January, got %d", len(days))
+	}
+	// Days outside of the range do not count to the busiest day
+	if days["2025-01-15"] != DefaultPalette[4] || days["2025-01-01"] != DefaultPalette[1] || days["2025-01-02"] != DefaultPalette[0] {
+		t.Errorf("Unexpected shades %v", days)
+	}
+}
+
+func Test_Render_InvalidRange(t *testing.T) {
+	from := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	if err := Render(io.Discard, nil, from, from.AddDate(0, 0, -1), DefaultPalette); err == nil {
+		t.Errorf("Expected an error for a range ending before it starts")
+	}
+}
+
+func Test_ParseRange(t *testing.T) {
+	now := time.Date(2025, 6, 15, 18, 0, 0, 0, time.UTC)
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		value   string
+		want    Range
+		wantErr bool
+	}{
+		{"", Range{From: time.Date(2024, 6, 16, 18, 0, 0, 0, time.UTC), To: now}, false},
+		{RangeRolling, Range{From: time.Date(2024, 6, 16, 18, 0, 0, 0, time.UTC), To: now}, false},
+		{RangeYear, Range{From: date(2025, 1, 1), To: date(2025, 12, 31)}, false},
+		{"2023", Range{From: date(2023, 1, 1), To: date(2023, 12, 31)}, false},
+		{"2025-01-10..2025-03-01", Range{From: date(2025, 1, 10), To: date(2025, 3, 1)}, false},
+		{"2025-03-01..2025-01-10", Range{}, true},
+		{"2025-01-10..soon", Range{}, true},
+		{"last-week", Range{}, true},
+		{"25", Range{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseRange(tt.value, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRange() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseRange() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_ParsePalette(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    Palette
+		wantErr bool
+	}{
+		{"", DefaultPalette, false},
+		{"blue", Palettes["blue"], false},
+		{"#fff, #eee,#ddd,#ccc,#000000", Palette{"#fff", "#eee", "#ddd", "#ccc", "#000000"}, false},
+		{"#fff,#eee", Palette{}, true},
+		{"#fff,#eee,#ddd,#ccc,red", Palette{}, true},
+		{"rainbow", Palette{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParsePalette(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePalette() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParsePalette() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_RenderText(t *testing.T) {
+	counts := map[string]int{"2025-01-01": 1, "2025-01-15": 4}
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC)
+
+	var buffer bytes.Buffer
+	if err := RenderText(&buffer, counts, from, to, 0); err != nil {
+		t.Fatalf("RenderText() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimRight(buffer.String(), "\n"), "\n")
+	if len(lines) != 9 {
+		t.Fatalf("Expected a month row, 7 day rows and a legend, got %q", buffer.String())
+	}
+	if lines[0] != "    Jan Feb" {
+		t.Errorf("Expected month labels, got %q", lines[0])
+	}
+
+	// 2025-01-01 is the Wednesday of the first week, 2025-01-15 two weeks later
+	if lines[4] != "Wed ░·█······" {
+		t.Errorf("Expected the Wednesday row shaded relative to the busiest day, got %q", lines[4])
+	}
+
+	buffer.Reset()
+	if err := RenderText(&buffer, counts, from, to, 8); err != nil {
+		t.Fatalf("RenderText() error = %v", err)
+	}
+	if lines := strings.Split(buffer.String(), "\n"); lines[4] != "Wed ░·▒······" {
+		t.Errorf("Expected the Wednesday row shaded relative to the given busiest, got %q", lines[4])
+	}
+
+	if err := RenderText(&buffer, counts, to, from, 0); err == nil {
+		t.Errorf("Expected an error for an inverted range")
+	}
+}
@@ -0,0 +1,52 @@
+package heatmap
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Palettes are the named palettes, light to dark.
+var Palettes = map[string]Palette{
+	"green":  DefaultPalette,
+	"blue":   {"#ebedf0", "#c6dbef", "#6baed6", "#2171b5", "#08306b"},
+	"purple": {"#ebedf0", "#dadaeb", "#9e9ac8", "#6a51a3", "#3f007d"},
+	"orange": {"#ebedf0", "#fdd0a2", "#fd8d3c", "#d94801", "#7f2704"},
+	"gray":   {"#ebedf0", "#bdbdbd", "#969696", "#525252", "#252525"},
+	"dark":   {"#161b22", "#0e4429", "#006d32", "#26a641", "#39d353"},
+}
+
+var hexColor = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// PaletteNames returns the names of the named palettes, sorted.
+func PaletteNames() []string {
+	return slices.Sorted(maps.Keys(Palettes))
+}
+
+// ParsePalette returns the named palette, or a custom one given as five hex
+// colours separated by commas. An empty value is the default palette.
+func ParsePalette(value string) (Palette, error) {
+	if value == "" {
+		return DefaultPalette, nil
+	}
+	if palette, ok := Palettes[value]; ok {
+		return palette, nil
+	}
+
+	colors := strings.Split(value, ",")
+	if len(colors) != len(Palette{}) {
+		return Palette{}, fmt.Errorf("invalid palette %q, expected one of %v or %d hex colours", value, PaletteNames(), len(Palette{}))
+	}
+
+	var palette Palette
+	for i, color := range colors {
+		color = strings.TrimSpace(color)
+		if !hexColor.MatchString(color) {
+			return Palette{}, fmt.Errorf("invalid palette colour %q", color)
+		}
+		palette[i] = color
+	}
+	return palette, nil
+}
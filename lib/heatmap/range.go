@@ -0,0 +1,55 @@
+package heatmap
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/HideyoshiNakazone/tracko/lib/utils"
+)
+
+const (
+	RangeRolling = "rolling"
+	RangeYear    = "year"
+)
+
+// Range is the span of days of a heatmap, both ends inclusive.
+type Range struct {
+	From time.Time
+	To   time.Time
+}
+
+// RollingRange is the last 12 months up to the day of now.
+func RollingRange(now time.Time) Range {
+	return Range{From: now.AddDate(-1, 0, 1), To: now}
+}
+
+// ParseRange reads a heatmap range relative to now. It is one of:
+//
+//	rolling                 the last 12 months, the default
+//	year                    the current calendar year
+//	2025                    a calendar year
+//	2025-01-01..2025-06-30  a custom range of days
+func ParseRange(value string, now time.Time) (Range, error) {
+	switch value {
+	case "", RangeRolling:
+		return RollingRange(now), nil
+	case RangeYear:
+		return yearRange(now.Year()), nil
+	}
+
+	from, to, ok, err := utils.ParseDays(value)
+	if err != nil {
+		return Range{}, fmt.Errorf("invalid range %q, %v", value, err)
+	}
+	if !ok {
+		return Range{}, fmt.Errorf("invalid range %q, expected %s, %s, a year or FROM..TO", value, RangeRolling, RangeYear)
+	}
+	return Range{From: from, To: to}, nil
+}
+
+func yearRange(year int) Range {
+	return Range{
+		From: time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC),
+	}
+}
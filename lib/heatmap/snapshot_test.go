@@ -0,0 +1,81 @@
+package heatmap
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+// fixtureCounts is a fixed dataset spanning 2024 and 2025, with quiet weeks,
+// busy days and a streak across the new year.
+func fixtureCounts() map[string]int {
+	counts := map[string]int{}
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 731; i++ {
+		date := day.AddDate(0, 0, i)
+		// A deterministic pattern, weekends are quiet
+		if date.Weekday() == time.Saturday || date.Weekday() == time.Sunday {
+			continue
+		}
+		if count := (i*7 + i/13) % 11; count > 3 {
+			counts[date.Format("2006-01-02")] = count - 3
+		}
+	}
+	for i := -3; i <= 3; i++ {
+		counts[time.Date(2025, 1, 1+i, 0, 0, 0, 0, time.UTC).Format("2006-01-02")] = 12
+	}
+	return counts
+}
+
+func Test_Render_Snapshots(t *testing.T) {
+	now := time.Date(2025, 6, 15, 18, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		value   string
+		palette string
+	}{
+		{"year_2024", "2024", ""},
+		{"rolling", RangeRolling, "blue"},
+		{"custom_range", "2024-12-15..2025-02-10", "#eeeeee,#ffd6a5,#ff9f1c,#e76f51,#9d0208"},
+		{"dark_year", RangeYear, "dark"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			heatmapRange, err := ParseRange(tt.value, now)
+			if err != nil {
+				t.Fatalf("ParseRange() error = %v", err)
+			}
+			palette, err := ParsePalette(tt.palette)
+			if err != nil {
+				t.Fatalf("ParsePalette() error = %v", err)
+			}
+
+			var buffer bytes.Buffer
+			if err := Render(&buffer, fixtureCounts(), heatmapRange.From, heatmapRange.To, palette); err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+
+			golden := filepath.Join("testdata", tt.name+".svg")
+			if *update {
+				if err := os.WriteFile(golden, buffer.Bytes(), 0o644); err != nil {
+					t.Fatalf("Failed to update %s: %v", golden, err)
+				}
+			}
+
+			expected, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("Failed to read %s, run the tests with -update to create it: %v", golden, err)
+			}
+			if !bytes.Equal(buffer.Bytes(), expected) {
+				t.Errorf("Heatmap differs from %s, run the tests with -update if the change is intended", golden)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package heatmap
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/HideyoshiNakazone/tracko/lib/config_model"
+)
+
+// Glyphs are the terminal cells of each activity level, from no commits to
+// the busiest days.
+var Glyphs = [5]string{"·", "░", "▒", "▓", "█"}
+
+const labelWidth = 4
+
+// RenderText writes a plain text heatmap of the days from from to to, both
+// inclusive, laid out like Render with a column per week. Days are shaded
+// relative to busiest, or to the busiest day of the range when it is zero,
+// so that two heatmaps sharing busiest can be compared side by side.
+func RenderText(w io.Writer, counts map[string]int, from time.Time, to time.Time, busiest int) error {
+	first := truncateDay(from)
+	last := truncateDay(to)
+	if last.Before(first) {
+		return fmt.Errorf("heatmap range ends on %s before it starts on %s",
+			last.Format(config_model.DateFormat), first.Format(config_model.DateFormat))
+	}
+
+	start := first.AddDate(0, 0, -int(first.Weekday()))
+	weeks := int(last.Sub(start).Hours()/24)/7 + 1
+
+	if busiest <= 0 {
+		for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+			busiest = max(busiest, counts[day.Format(config_model.DateFormat)])
+		}
+	}
+
+	// Month labels are placed as in Render, and dropped when the previous
+	// label still runs over them
+	months := []rune(strings.Repeat(" ", weeks+3))
+	free := 0
+	for week := 0; week < weeks; week++ {
+		for weekday := 0; weekday < 7; weekday++ {
+			day := start.AddDate(0, 0, week*7+weekday)
+			if day.Before(first) || day.After(last) {
+				continue
+			}
+			if (day.Day() == 1 || (day.Equal(first) && day.Day() <= 21)) && week >= free {
+				copy(months[week:], []rune(day.Format("Jan")))
+				free = week + 4
+			}
+		}
+	}
+
+	b := bufio.NewWriter(w)
+	fmt.Fprintf(b, "%s%s\n", strings.Repeat(" ", labelWidth), strings.TrimRight(string(months), " "))
+	for weekday := 0; weekday < 7; weekday++ {
+		label := ""
+		if weekday%2 == 1 {
+			label = time.Weekday(weekday).String()[:3]
+		}
+		fmt.Fprintf(b, "%-*s", labelWidth, label)
+
+		row := strings.Builder{}
+		for week := 0; week < weeks; week++ {
+			day := start.AddDate(0, 0, week*7+weekday)
+			if day.Before(first) || day.After(last) {
+				row.WriteString(" ")
+				continue
+			}
+			row.WriteString(Glyphs[Level(counts[day.Format(config_model.DateFormat)], busiest)])
+		}
+		fmt.Fprintln(b, strings.TrimRight(row.String(), " "))
+	}
+	fmt.Fprintf(b, "%sLess %s More\n", strings.Repeat(" ", labelWidth), strings.Join(Glyphs[:], ""))
+
+	return b.Flush()
+}
@@ -0,0 +1,5 @@
+package internal_errors
+
+import "errors"
+
+var ErrDirtyTargetRepo = errors.New("target repository has uncommitted changes")
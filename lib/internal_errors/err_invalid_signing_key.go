@@ -0,0 +1,5 @@
+package internal_errors
+
+import "errors"
+
+var ErrInvalidSigningKey = errors.New("invalid signing key")
@@ -0,0 +1,5 @@
+package internal_errors
+
+import "errors"
+
+var ErrInvalidTargetRepo = errors.New("target repository is not a valid git repository")
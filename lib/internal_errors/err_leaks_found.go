@@ -0,0 +1,5 @@
+package internal_errors
+
+import "errors"
+
+var ErrLeaksFound = errors.New("target history leaks tracked data")
@@ -0,0 +1,5 @@
+package internal_errors
+
+import "errors"
+
+var ErrNonLinearTarget = errors.New("target branch history is not linear")
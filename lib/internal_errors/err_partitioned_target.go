@@ -0,0 +1,5 @@
+package internal_errors
+
+import "errors"
+
+var ErrPartitionedTarget = errors.New("target is partitioned, each of its partitions is exported on its own")
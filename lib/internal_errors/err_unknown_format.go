@@ -0,0 +1,5 @@
+package internal_errors
+
+import "errors"
+
+var ErrUnknownFormat = errors.New("unknown export format")
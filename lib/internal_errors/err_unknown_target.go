@@ -0,0 +1,5 @@
+package internal_errors
+
+import "errors"
+
+var ErrUnknownTarget = errors.New("unknown export target")
@@ -0,0 +1,5 @@
+package internal_errors
+
+import "errors"
+
+var ErrUnsupportedPushAuth = errors.New("push auth method is not supported by the remote protocol")
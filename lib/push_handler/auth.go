@@ -0,0 +1,117 @@
+package push_handler
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/go-git/go-git/v6/plumbing/transport"
+	"github.com/go-git/go-git/v6/plumbing/transport/http"
+	"github.com/go-git/go-git/v6/plumbing/transport/ssh"
+
+	"github.com/HideyoshiNakazone/tracko/lib/config_model"
+	"github.com/HideyoshiNakazone/tracko/lib/internal_errors"
+)
+
+const defaultSSHUser = "git"
+const defaultTokenUser = "tracko"
+
+// ResolveAuth builds the auth method for pushing to endpoint. When the
+// credentials come from a credential helper the credential is returned as
+// well, so the caller can approve or reject it after the push.
+func ResolveAuth(push config_model.ConfigPushModel, endpoint *transport.Endpoint) (transport.AuthMethod, *Credential, error) {
+	method := push.Auth()
+	if method == config_model.PushAuthAuto {
+		method = detectAuthMethod(push, endpoint)
+	}
+
+	switch method {
+	case config_model.PushAuthNone:
+		return nil, nil, nil
+
+	case config_model.PushAuthSSHAgent:
+		if !isSSH(endpoint) {
+			return nil, nil, internal_errors.ErrUnsupportedPushAuth
+		}
+		auth, err := ssh.NewSSHAgentAuth(sshUser(push, endpoint))
+		return auth, nil, err
+
+	case config_model.PushAuthSSHKey:
+		if !isSSH(endpoint) {
+			return nil, nil, internal_errors.ErrUnsupportedPushAuth
+		}
+		auth, err := ssh.NewPublicKeysFromFile(sshUser(push, endpoint), os.ExpandEnv(push.SSHKeyPath()), envValue(push.PassphraseEnv()))
+		return auth, nil, err
+
+	case config_model.PushAuthToken:
+		if !isHTTP(endpoint) {
+			return nil, nil, internal_errors.ErrUnsupportedPushAuth
+		}
+		token := envValue(push.TokenEnv())
+		if token == "" {
+			return nil, nil, fmt.Errorf("environment variable %q is empty", push.TokenEnv())
+		}
+		username := push.Username()
+		if username == "" {
+			username = defaultTokenUser
+		}
+		return &http.BasicAuth{Username: username, Password: token}, nil, nil
+
+	case config_model.PushAuthCredentialHelper:
+		if !isHTTP(endpoint) {
+			return nil, nil, internal_errors.ErrUnsupportedPushAuth
+		}
+		credential := NewCredential(endpoint)
+		if push.Username() != "" {
+			credential.Username = push.Username()
+		}
+		if err := credential.Fill(); err != nil {
+			return nil, nil, err
+		}
+		return &http.BasicAuth{Username: credential.Username, Password: credential.Password}, credential, nil
+	}
+
+	return nil, nil, fmt.Errorf("unknown push auth method %q", method)
+}
+
+// detectAuthMethod picks an auth method from the remote protocol: ssh remotes
+// use the configured key file or the agent, http remotes use the token from
+// the environment or the credential helpers.
+func detectAuthMethod(push config_model.ConfigPushModel, endpoint *transport.Endpoint) string {
+	switch {
+	case isSSH(endpoint) && push.SSHKeyPath() != "":
+		return config_model.PushAuthSSHKey
+	case isSSH(endpoint):
+		return config_model.PushAuthSSHAgent
+	case isHTTP(endpoint) && envValue(push.TokenEnv()) != "":
+		return config_model.PushAuthToken
+	case isHTTP(endpoint):
+		return config_model.PushAuthCredentialHelper
+	default:
+		return config_model.PushAuthNone
+	}
+}
+
+func isSSH(endpoint *transport.Endpoint) bool {
+	return endpoint.Protocol == "ssh"
+}
+
+func isHTTP(endpoint *transport.Endpoint) bool {
+	return endpoint.Protocol == "http" || endpoint.Protocol == "https"
+}
+
+func sshUser(push config_model.ConfigPushModel, endpoint *transport.Endpoint) string {
+	if endpoint.User != "" {
+		return endpoint.User
+	}
+	if push.Username() != "" {
+		return push.Username()
+	}
+	return defaultSSHUser
+}
+
+func envValue(name string) string {
+	if name == "" {
+		return ""
+	}
+	return os.Getenv(name)
+}
@@ -0,0 +1,74 @@
+package push_handler
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-git/go-git/v6/plumbing/transport"
+
+	"github.com/HideyoshiNakazone/tracko/lib/config_model"
+	"github.com/HideyoshiNakazone/tracko/lib/internal_errors"
+)
+
+func Test_DetectAuthMethod(t *testing.T) {
+	tests := []struct {
+		name      string
+		url       string
+		token     string
+		configure func(*config_model.ConfigTargetBuilder)
+		expected  string
+	}{
+		{"Local path", "/tmp/mirror.git", "", nil, config_model.PushAuthNone},
+		{"SSH without key", "git@github.com:owner/repo.git", "", nil, config_model.PushAuthSSHAgent},
+		{"SSH with key", "ssh://git@github.com/owner/repo.git", "", func(b *config_model.ConfigTargetBuilder) {
+			b.WithPushSSHKey("~/.ssh/id_ed25519", "")
+		}, config_model.PushAuthSSHKey},
+		{"HTTPS with token", "https://github.com/owner/repo.git", "secret", nil, config_model.PushAuthToken},
+		{"HTTPS without token", "https://github.com/owner/repo.git", "", nil, config_model.PushAuthCredentialHelper},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(config_model.DefaultPushTokenEnv, tt.token)
+
+			endpoint, err := transport.NewEndpoint(tt.url)
+			if err != nil {
+				t.Fatalf("Failed to parse endpoint: %v", err)
+			}
+
+			if got := detectAuthMethod(pushTarget(t, "test/repo", tt.configure).Push(), endpoint); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func Test_ResolveAuth_UnsupportedProtocol(t *testing.T) {
+	tests := []struct {
+		name   string
+		url    string
+		method string
+	}{
+		{"Token over SSH", "git@github.com:owner/repo.git", config_model.PushAuthToken},
+		{"Credential helper over SSH", "git@github.com:owner/repo.git", config_model.PushAuthCredentialHelper},
+		{"Agent over HTTPS", "https://github.com/owner/repo.git", config_model.PushAuthSSHAgent},
+		{"Key file over HTTPS", "https://github.com/owner/repo.git", config_model.PushAuthSSHKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			endpoint, err := transport.NewEndpoint(tt.url)
+			if err != nil {
+				t.Fatalf("Failed to parse endpoint: %v", err)
+			}
+
+			push := pushTarget(t, "test/repo", func(b *config_model.ConfigTargetBuilder) {
+				b.WithPushAuth(tt.method)
+			}).Push()
+
+			if _, _, err := ResolveAuth(push, endpoint); !errors.Is(err, internal_errors.ErrUnsupportedPushAuth) {
+				t.Errorf("Expected ErrUnsupportedPushAuth, got %v", err)
+			}
+		})
+	}
+}
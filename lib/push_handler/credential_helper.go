@@ -0,0 +1,109 @@
+package push_handler
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/go-git/go-git/v6/plumbing/transport"
+)
+
+// Credential is a username and password pair exchanged with git's
+// credential-helper protocol, see https://git-scm.com/docs/git-credential.
+type Credential struct {
+	Protocol string
+	Host     string
+	Path     string
+	Username string
+	Password string
+}
+
+func NewCredential(endpoint *transport.Endpoint) *Credential {
+	host := endpoint.Host
+	if endpoint.Port != 0 {
+		host = fmt.Sprintf("%s:%d", endpoint.Host, endpoint.Port)
+	}
+	return &Credential{
+		Protocol: endpoint.Protocol,
+		Host:     host,
+		Path:     strings.TrimPrefix(endpoint.Path, "/"),
+		Username: endpoint.User,
+	}
+}
+
+// Fill asks the configured credential helpers for the missing username and
+// password.
+func (c *Credential) Fill() error {
+	output, err := runCredentialCommand("fill", c)
+	if err != nil {
+		return err
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "username":
+			c.Username = value
+		case "password":
+			c.Password = value
+		}
+	}
+
+	if c.Password == "" {
+		return fmt.Errorf("no credentials returned for %s://%s", c.Protocol, c.Host)
+	}
+	return nil
+}
+
+// Approve tells the credential helpers that the credential worked, so they
+// can store it.
+func (c *Credential) Approve() error {
+	_, err := runCredentialCommand("approve", c)
+	return err
+}
+
+// Reject tells the credential helpers to forget a credential that failed.
+func (c *Credential) Reject() error {
+	_, err := runCredentialCommand("reject", c)
+	return err
+}
+
+func (c *Credential) encode() string {
+	var b strings.Builder
+	fields := [][2]string{
+		{"protocol", c.Protocol},
+		{"host", c.Host},
+		{"path", c.Path},
+		{"username", c.Username},
+		{"password", c.Password},
+	}
+	for _, field := range fields {
+		if field[1] != "" {
+			fmt.Fprintf(&b, "%s=%s\n", field[0], field[1])
+		}
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+func runCredentialCommand(action string, c *Credential) ([]byte, error) {
+	cmd := exec.Command("git", "credential", action)
+	cmd.Stdin = strings.NewReader(c.encode())
+	// Never fall back to prompting on the terminal
+	cmd.Env = append(cmd.Environ(), "GIT_TERMINAL_PROMPT=0")
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git credential %s failed: %w: %s", action, err, strings.TrimSpace(stderr.String()))
+	}
+	return output, nil
+}
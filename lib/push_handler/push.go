@@ -0,0 +1,93 @@
+package push_handler
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-git/go-git/v6"
+	"github.com/go-git/go-git/v6/config"
+	"github.com/go-git/go-git/v6/plumbing"
+	"github.com/go-git/go-git/v6/plumbing/transport"
+
+	"github.com/HideyoshiNakazone/tracko/lib/config_model"
+	"github.com/HideyoshiNakazone/tracko/lib/internal_errors"
+)
+
+type PushResult struct {
+	Remote   string
+	Branch   string
+	UpToDate bool
+}
+
+// Push pushes the branch of the target to its configured remote, when the
+// target has no branch configured the checked out branch is pushed.
+func Push(target config_model.ConfigTargetModel) (*PushResult, error) {
+	push := target.Push()
+
+	r, err := git.PlainOpen(target.LocalPath())
+	if err != nil {
+		return nil, internal_errors.ErrInvalidTargetRepo
+	}
+
+	head, err := r.Head()
+	if target.Branch() != "" {
+		head, err = r.Reference(plumbing.NewBranchReferenceName(target.Branch()), true)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("target repository has no commits to push: %w", err)
+	}
+	if !head.Name().IsBranch() {
+		return nil, errors.New("target repository is not on a branch")
+	}
+
+	remote, err := r.Remote(push.Remote())
+	if err != nil {
+		return nil, fmt.Errorf("remote %q not found: %w", push.Remote(), err)
+	}
+
+	if len(remote.Config().URLs) == 0 {
+		return nil, fmt.Errorf("remote %q has no url", push.Remote())
+	}
+
+	endpoint, err := transport.NewEndpoint(remote.Config().URLs[0])
+	if err != nil {
+		return nil, err
+	}
+
+	auth, credential, err := ResolveAuth(push, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	refSpec := config.RefSpec(fmt.Sprintf("%s:%s", head.Name(), head.Name()))
+	err = r.Push(&git.PushOptions{
+		RemoteName: push.Remote(),
+		RefSpecs:   []config.RefSpec{refSpec},
+		Auth:       auth,
+	})
+
+	result := &PushResult{
+		Remote: push.Remote(),
+		Branch: head.Name().Short(),
+	}
+
+	switch {
+	case errors.Is(err, git.NoErrAlreadyUpToDate):
+		result.UpToDate = true
+	case err != nil:
+		if credential != nil && isAuthError(err) {
+			credential.Reject()
+		}
+		return nil, err
+	}
+
+	if credential != nil {
+		credential.Approve()
+	}
+
+	return result, nil
+}
+
+func isAuthError(err error) bool {
+	return errors.Is(err, transport.ErrAuthenticationRequired) || errors.Is(err, transport.ErrAuthorizationFailed)
+}
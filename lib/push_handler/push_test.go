@@ -0,0 +1,227 @@
+package push_handler
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v6"
+	"github.com/go-git/go-git/v6/config"
+
+	"github.com/HideyoshiNakazone/tracko/lib/config_model"
+	"github.com/HideyoshiNakazone/tracko/lib/repo"
+)
+
+func pushTarget(t *testing.T, targetPath string, configure func(*config_model.ConfigTargetBuilder)) config_model.ConfigTargetModel {
+	t.Helper()
+
+	builder := config_model.NewTargetBuilder("default", targetPath).WithAuthor("Test User", "test@personal.example.com")
+	if configure != nil {
+		configure(builder)
+	}
+
+	target, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Failed to build target: %v", err)
+	}
+	return *target
+}
+
+// prepareTarget creates a target repository with a single commit whose
+// origin remote points at url.
+func prepareTarget(t *testing.T, url string) (string, *git.Repository) {
+	t.Helper()
+
+	targetPath, cleanup, err := repo.PrepareTestRepository([]repo.TestCommit{
+		{Author: "Test User", Email: "test@example.com", Message: "Mirrored contribution", When: time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)},
+	})
+	if err != nil {
+		t.Fatalf("Failed to prepare target repository: %v", err)
+	}
+	t.Cleanup(*cleanup)
+
+	target, err := git.PlainOpen(targetPath)
+	if err != nil {
+		t.Fatalf("Failed to open target repository: %v", err)
+	}
+
+	if _, err := target.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{url}}); err != nil {
+		t.Fatalf("Failed to create remote: %v", err)
+	}
+
+	return targetPath, target
+}
+
+func assertPushed(t *testing.T, target *git.Repository, barePath string) {
+	t.Helper()
+
+	head, err := target.Head()
+	if err != nil {
+		t.Fatalf("Failed to read target head: %v", err)
+	}
+
+	bare, err := git.PlainOpen(barePath)
+	if err != nil {
+		t.Fatalf("Failed to open bare repository: %v", err)
+	}
+
+	ref, err := bare.Reference(head.Name(), true)
+	if err != nil {
+		t.Fatalf("Expected %s to be pushed: %v", head.Name(), err)
+	}
+	if ref.Hash() != head.Hash() {
+		t.Errorf("Expected remote %s at %s, got %s", head.Name(), head.Hash(), ref.Hash())
+	}
+}
+
+func Test_Push_LocalBareRepository(t *testing.T) {
+	isolateGitConfig(t, "")
+
+	barePath := prepareBareRepository(t, t.TempDir(), "mirror.git")
+	targetPath, target := prepareTarget(t, barePath)
+	pushed := pushTarget(t, targetPath, nil)
+
+	result, err := Push(pushed)
+	if err != nil {
+		t.Fatalf("Push() error = %v", err)
+	}
+	if result.UpToDate {
+		t.Error("Expected first push to update the remote")
+	}
+	assertPushed(t, target, barePath)
+
+	result, err = Push(pushed)
+	if err != nil {
+		t.Fatalf("Second Push() error = %v", err)
+	}
+	if !result.UpToDate {
+		t.Error("Expected second push to be up to date")
+	}
+}
+
+func Test_Push_UnknownRemote(t *testing.T) {
+	isolateGitConfig(t, "")
+
+	barePath := prepareBareRepository(t, t.TempDir(), "mirror.git")
+	targetPath, _ := prepareTarget(t, barePath)
+
+	target := pushTarget(t, targetPath, func(b *config_model.ConfigTargetBuilder) {
+		b.WithPushRemote("upstream")
+	})
+
+	if _, err := Push(target); err == nil {
+		t.Error("Expected error for unknown remote, got nil")
+	}
+}
+
+func Test_Push_HTTP(t *testing.T) {
+	tests := []struct {
+		name      string
+		token     string
+		helper    string
+		configure func(*config_model.ConfigTargetBuilder)
+		wantErr   bool
+	}{
+		{
+			name:  "Token from environment",
+			token: "secret",
+		},
+		{
+			name:    "Wrong token",
+			token:   "wrong",
+			wantErr: true,
+		},
+		{
+			name:   "Credential helper",
+			helper: "!f() { test \"$1\" = get && echo username=tracko && echo password=secret; }; f",
+		},
+		{
+			name:   "Credential helper with wrong password",
+			helper: "!f() { test \"$1\" = get && echo username=tracko && echo password=wrong; }; f",
+			wantErr: true,
+		},
+		{
+			name:  "Explicit credential helper ignores token",
+			token: "wrong",
+			helper: "!f() { test \"$1\" = get && echo username=tracko && echo password=secret; }; f",
+			configure: func(b *config_model.ConfigTargetBuilder) {
+				b.WithPushAuth(config_model.PushAuthCredentialHelper)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gitConfig := ""
+			if tt.helper != "" {
+				gitConfig = fmt.Sprintf("[credential]\n\thelper = %q\n", tt.helper)
+			}
+			isolateGitConfig(t, gitConfig)
+			t.Setenv(config_model.DefaultPushTokenEnv, tt.token)
+
+			root := t.TempDir()
+			barePath := prepareBareRepository(t, root, "mirror.git")
+			server := startHTTPStandIn(t, root, "tracko", "secret")
+			targetPath, target := prepareTarget(t, server.URL+"/mirror.git")
+
+			_, err := Push(pushTarget(t, targetPath, tt.configure))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Push() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr {
+				assertPushed(t, target, barePath)
+			}
+		})
+	}
+}
+
+func Test_Push_SSH(t *testing.T) {
+	tests := []struct {
+		name       string
+		useAgent   bool
+		passphrase string
+		wrongKey   bool
+		wantErr    bool
+	}{
+		{name: "Key file"},
+		{name: "Key file with passphrase", passphrase: "hunter2"},
+		{name: "Agent", useAgent: true},
+		{name: "Unauthorized key", wrongKey: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			isolateGitConfig(t, "")
+			t.Setenv("SSH_AUTH_SOCK", "")
+
+			clientKey, clientPublic := generateKey(t)
+			authorizedKey := clientPublic
+			if tt.wrongKey {
+				_, authorizedKey = generateKey(t)
+			}
+
+			barePath := prepareBareRepository(t, t.TempDir(), "mirror.git")
+			addr := startSSHStandIn(t, authorizedKey)
+			targetPath, target := prepareTarget(t, fmt.Sprintf("ssh://git@%s%s", addr, barePath))
+
+			pushed := pushTarget(t, targetPath, func(b *config_model.ConfigTargetBuilder) {
+				if tt.useAgent {
+					return
+				}
+				t.Setenv("TRACKO_TEST_PASSPHRASE", tt.passphrase)
+				b.WithPushSSHKey(writeKeyFile(t, clientKey, tt.passphrase), "TRACKO_TEST_PASSPHRASE")
+			})
+			if tt.useAgent {
+				startSSHAgent(t, clientKey)
+			}
+
+			_, err := Push(pushed)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Push() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr {
+				assertPushed(t, target, barePath)
+			}
+		})
+	}
+}
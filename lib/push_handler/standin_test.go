@@ -0,0 +1,267 @@
+package push_handler
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/pem"
+	"io"
+	"net"
+	"net/http"
+	"net/http/cgi"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-git/go-git/v6"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// Local stand-ins for the remotes a target repository is pushed to. They
+// serve bare repositories through the git binary, so tests never reach out
+// to a real host.
+
+func requireGit(t *testing.T) string {
+	t.Helper()
+
+	gitPath, err := exec.LookPath("git")
+	if err != nil {
+		t.Skip("git binary is required for the remote stand-ins")
+	}
+	return gitPath
+}
+
+func prepareBareRepository(t *testing.T, root string, name string) string {
+	t.Helper()
+
+	path := filepath.Join(root, name)
+	if _, err := git.PlainInit(path, true); err != nil {
+		t.Fatalf("Failed to init bare repository: %v", err)
+	}
+
+	return path
+}
+
+// isolateGitConfig points git at an empty global config, so the user's
+// credential helpers never leak into the tests.
+func isolateGitConfig(t *testing.T, content string) {
+	t.Helper()
+
+	configPath := filepath.Join(t.TempDir(), "gitconfig")
+	if err := os.WriteFile(configPath, []byte(content), 0o644); err != nil {
+		t.Fatalf("Failed to write git config: %v", err)
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", configPath)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+}
+
+// startHTTPStandIn serves root through git http-backend, requiring basic auth
+// with the given username and password.
+func startHTTPStandIn(t *testing.T, root string, username string, password string) *httptest.Server {
+	t.Helper()
+
+	backend := &cgi.Handler{
+		Path: requireGit(t),
+		Args: []string{"http-backend"},
+		Env: []string{
+			"GIT_PROJECT_ROOT=" + root,
+			"GIT_HTTP_EXPORT_ALL=1",
+			"REMOTE_USER=" + username,
+			"GIT_CONFIG_GLOBAL=" + os.Getenv("GIT_CONFIG_GLOBAL"),
+			"GIT_CONFIG_NOSYSTEM=1",
+		},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != username || pass != password {
+			w.Header().Set("WWW-Authenticate", `Basic realm="tracko"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		backend.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func generateKey(t *testing.T) (ed25519.PrivateKey, ssh.PublicKey) {
+	t.Helper()
+
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	public, err := ssh.NewPublicKey(private.Public())
+	if err != nil {
+		t.Fatalf("Failed to convert public key: %v", err)
+	}
+	return private, public
+}
+
+// writeKeyFile writes an OpenSSH private key file, encrypted when a
+// passphrase is given.
+func writeKeyFile(t *testing.T, private ed25519.PrivateKey, passphrase string) string {
+	t.Helper()
+
+	var block *pem.Block
+	var err error
+	if passphrase == "" {
+		block, err = ssh.MarshalPrivateKey(private, "")
+	} else {
+		block, err = ssh.MarshalPrivateKeyWithPassphrase(private, "", []byte(passphrase))
+	}
+	if err != nil {
+		t.Fatalf("Failed to marshal private key: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "id_ed25519")
+	if err := os.WriteFile(path, pem.EncodeToMemory(block), 0o600); err != nil {
+		t.Fatalf("Failed to write private key: %v", err)
+	}
+	return path
+}
+
+// startSSHAgent serves an in-memory agent holding private and points
+// SSH_AUTH_SOCK at it.
+func startSSHAgent(t *testing.T, private ed25519.PrivateKey) {
+	t.Helper()
+
+	keyring := agent.NewKeyring()
+	if err := keyring.Add(agent.AddedKey{PrivateKey: private}); err != nil {
+		t.Fatalf("Failed to add key to agent: %v", err)
+	}
+
+	socketPath := filepath.Join(t.TempDir(), "agent.sock")
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Fatalf("Failed to listen on agent socket: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go agent.ServeAgent(keyring, conn)
+		}
+	}()
+
+	t.Setenv("SSH_AUTH_SOCK", socketPath)
+}
+
+// startSSHStandIn serves git-receive-pack and git-upload-pack over ssh for
+// clients authenticating with authorizedKey. The host key is written to a
+// known_hosts file referenced by SSH_KNOWN_HOSTS.
+func startSSHStandIn(t *testing.T, authorizedKey ssh.PublicKey) string {
+	t.Helper()
+	requireGit(t)
+
+	hostPrivate, hostPublic := generateKey(t)
+	hostSigner, err := ssh.NewSignerFromKey(hostPrivate)
+	if err != nil {
+		t.Fatalf("Failed to create host signer: %v", err)
+	}
+
+	config := &ssh.ServerConfig{
+		PublicKeyCallback: func(conn ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if bytes.Equal(key.Marshal(), authorizedKey.Marshal()) {
+				return nil, nil
+			}
+			return nil, io.EOF
+		},
+	}
+	config.AddHostKey(hostSigner)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveSSHConn(conn, config)
+		}
+	}()
+
+	addr := listener.Addr().String()
+	knownHostsPath := filepath.Join(t.TempDir(), "known_hosts")
+	line := knownhosts.Line([]string{knownhosts.Normalize(addr)}, hostPublic)
+	if err := os.WriteFile(knownHostsPath, []byte(line+"\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write known_hosts: %v", err)
+	}
+	t.Setenv("SSH_KNOWN_HOSTS", knownHostsPath)
+
+	return addr
+}
+
+func serveSSHConn(conn net.Conn, config *ssh.ServerConfig) {
+	_, channels, requests, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		conn.Close()
+		return
+	}
+	go ssh.DiscardRequests(requests)
+
+	for newChannel := range channels {
+		if newChannel.ChannelType() != "session" {
+			newChannel.Reject(ssh.UnknownChannelType, "unknown channel type")
+			continue
+		}
+		channel, channelRequests, err := newChannel.Accept()
+		if err != nil {
+			continue
+		}
+		go serveSSHSession(channel, channelRequests)
+	}
+}
+
+func serveSSHSession(channel ssh.Channel, requests <-chan *ssh.Request) {
+	defer channel.Close()
+
+	for req := range requests {
+		if req.Type != "exec" {
+			req.Reply(req.Type == "env", nil)
+			continue
+		}
+
+		length := binary.BigEndian.Uint32(req.Payload[:4])
+		command := string(req.Payload[4 : 4+length])
+		service, path, _ := strings.Cut(command, " ")
+		if service != "git-receive-pack" && service != "git-upload-pack" {
+			req.Reply(false, nil)
+			return
+		}
+		req.Reply(true, nil)
+
+		cmd := exec.Command("git", strings.TrimPrefix(service, "git-"), strings.Trim(path, "'"))
+		stdin, _ := cmd.StdinPipe()
+		go func() {
+			io.Copy(stdin, channel)
+			stdin.Close()
+		}()
+		cmd.Stdout = channel
+		cmd.Stderr = channel.Stderr()
+
+		status := uint32(0)
+		if err := cmd.Run(); err != nil {
+			status = 1
+		}
+		channel.SendRequest("exit-status", false, binary.BigEndian.AppendUint32(nil, status))
+		return
+	}
+}
@@ -0,0 +1,78 @@
+package readme_template
+
+import (
+	"bytes"
+	_ "embed"
+	"os"
+	"text/template"
+)
+
+// DefaultTemplate is the README.md written when a target sets no template.
+//
+//go:embed templates/README.md.tmpl
+var DefaultTemplate string
+
+// Language is the share, in percent, of the files changed in a language.
+type Language struct {
+	Language string
+	Share    int
+}
+
+// TemplateData is the data available to readme templates. Author is the name
+// the target exports as, Commits the target commits mirroring source commits
+// and From and To the first and last mirrored days.
+type TemplateData struct {
+	Target     string
+	Author     string
+	Commits    int
+	ActiveDays int
+	From       string
+	To         string
+	Languages  []Language
+	Badge      string
+}
+
+var sampleData = TemplateData{
+	Target:     "target",
+	Author:     "author",
+	Commits:    1,
+	ActiveDays: 1,
+	From:       "2000-01-01",
+	To:         "2000-01-01",
+	Languages:  []Language{{Language: "Go", Share: 100}},
+	Badge:      "badge.svg",
+}
+
+// Parse reads the readme template file at path, env vars expanded, or the
+// built-in one when path is empty. The template is tried on a target with a
+// single target commit on a single day, written in Go, so that a template
+// reading a field TemplateData lacks is refused on config load.
+func Parse(path string) (*template.Template, error) {
+	text := DefaultTemplate
+	if path != "" {
+		data, err := os.ReadFile(os.ExpandEnv(path))
+		if err != nil {
+			return nil, err
+		}
+		text = string(data)
+	}
+
+	tmpl, err := template.New("readme").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := Render(tmpl, sampleData); err != nil {
+		return nil, err
+	}
+
+	return tmpl, nil
+}
+
+func Render(tmpl *template.Template, data TemplateData) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
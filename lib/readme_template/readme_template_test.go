@@ -0,0 +1,84 @@
+package readme_template
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_Parse(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("TRACKO_TEST_TEMPLATES", dir)
+
+	tests := []struct {
+		name    string
+		text    string
+		wantErr bool
+	}{
+		{"Known fields", "{{.Target}} by {{.Author}}: {{.Commits}} commits on {{.ActiveDays}} days from {{.From}} to {{.To}} ![]({{.Badge}})", false},
+		{"Languages", "{{range .Languages}}{{.Language}} {{.Share}}%\n{{end}}", false},
+		{"Syntax error", "{{.Target", true},
+		{"Unknown field", "{{.Hash}}", true},
+		{"Unknown language field", "{{range .Languages}}{{.Files}}{{end}}", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := os.WriteFile(filepath.Join(dir, "README.md.tmpl"), []byte(tt.text), 0o644); err != nil {
+				t.Fatalf("Failed to write template: %v", err)
+			}
+
+			_, err := Parse("$TRACKO_TEST_TEMPLATES/README.md.tmpl")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := Parse(""); err != nil {
+		t.Errorf("Expected the default template to parse, got %v", err)
+	}
+	if _, err := Parse(filepath.Join(dir, "missing.tmpl")); err == nil {
+		t.Errorf("Expected a missing template to fail")
+	}
+}
+
+func Test_Render(t *testing.T) {
+	tmpl, err := Parse("")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	data := TemplateData{
+		Target:     "github",
+		Author:     "Personal",
+		Commits:    2,
+		ActiveDays: 2,
+		From:       "2025-01-01",
+		To:         "2025-01-02",
+		Languages:  []Language{},
+		Badge:      "badge.svg",
+	}
+	readme, err := Render(tmpl, data)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `# github
+
+![mirrored commits](badge.svg)
+
+This repository mirrors the commit activity of Personal, exported with
+[tracko](https://github.com/HideyoshiNakazone/tracko). Its commits carry the
+dates of the original work and no source code.
+
+| | |
+|---|---|
+| Mirrored commits | 2 |
+| Active days | 2 |
+| Period | 2025-01-01 to 2025-01-02 |
+`
+	if string(readme) != expected {
+		t.Errorf("Render() = %q, want %q", readme, expected)
+	}
+}
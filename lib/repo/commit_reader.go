@@ -0,0 +1,139 @@
+package repo
+
+import (
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v6"
+	"github.com/go-git/go-git/v6/plumbing/object"
+)
+
+// CommitRecord holds the metadata of a single commit read from a tracked repository.
+type CommitRecord struct {
+	RepoPath string
+	Hash     string
+	Author   string
+	Email    string
+	When     time.Time
+	Message  string
+
+	Additions int
+	Deletions int
+	Files     []string
+}
+
+func (c CommitRecord) FilesChanged() int {
+	return len(c.Files)
+}
+
+// ReadAuthorCommits returns every commit reachable from any reference of the
+// repository at repoPath that was authored by one of the given emails, sorted
+// from oldest to newest.
+func ReadAuthorCommits(repoPath string, emails []string) ([]CommitRecord, error) {
+	r, err := git.PlainOpen(repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	iter, err := r.Log(&git.LogOptions{All: true})
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	normalizedEmails := make([]string, 0, len(emails))
+	for _, email := range emails {
+		normalizedEmails = append(normalizedEmails, strings.ToLower(strings.TrimSpace(email)))
+	}
+
+	var records []CommitRecord
+	err = iter.ForEach(func(c *object.Commit) error {
+		if !slices.Contains(normalizedEmails, strings.ToLower(c.Author.Email)) {
+			return nil
+		}
+		record := CommitRecord{
+			RepoPath: repoPath,
+			Hash:     c.Hash.String(),
+			Author:   c.Author.Name,
+			Email:    c.Author.Email,
+			When:     c.Author.When,
+			Message:  c.Message,
+		}
+
+		stats, err := c.Stats()
+		if err != nil {
+			return err
+		}
+		for _, stat := range stats {
+			record.Additions += stat.Addition
+			record.Deletions += stat.Deletion
+			record.Files = append(record.Files, stat.Name)
+		}
+
+		records = append(records, record)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].When.Before(records[j].When)
+	})
+
+	return records, nil
+}
+
+// ReadTrackedCommits reads the commits of the given emails from every tracked
+// repository, environment variables in the paths are expanded. The records of
+// all repositories are merged from oldest to newest.
+func ReadTrackedCommits(repoPaths []string, emails []string) ([]CommitRecord, error) {
+	var records []CommitRecord
+	for _, repoPath := range repoPaths {
+		repoRecords, err := ReadAuthorCommits(os.ExpandEnv(repoPath), emails)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, repoRecords...)
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].When.Before(records[j].When)
+	})
+
+	return records, nil
+}
+
+// Subject returns the first line of the commit message.
+func (c CommitRecord) Subject() string {
+	subject, _, _ := strings.Cut(strings.TrimSpace(c.Message), "\n")
+	return strings.TrimSpace(subject)
+}
+
+// ReadCommitHashes returns the hash of every commit reachable from any
+// reference of the repository at repoPath, whoever authored it.
+func ReadCommitHashes(repoPath string) ([]string, error) {
+	r, err := git.PlainOpen(repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	iter, err := r.Log(&git.LogOptions{All: true})
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	hashes := []string{}
+	err = iter.ForEach(func(c *object.Commit) error {
+		hashes = append(hashes, c.Hash.String())
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return hashes, nil
+}
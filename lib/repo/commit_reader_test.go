@@ -0,0 +1,177 @@
+package repo
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_ReadAuthorCommits(t *testing.T) {
+	base := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	repoPath, cleanup, err := PrepareTestRepository([]TestCommit{
+		{Author: "Test User", Email: "test@example.com", Message: "first", When: base},
+		{Author: "Other User", Email: "other@example.com", Message: "second", When: base.Add(time.Hour)},
+		{Author: "Test User", Email: "TEST@example.com", Message: "third", When: base.Add(2 * time.Hour)},
+	})
+	if err != nil {
+		t.Fatalf("Failed to prepare test repository: %v", err)
+	}
+	defer (*cleanup)()
+
+	tests := []struct {
+		name     string
+		emails   []string
+		expected []string
+	}{
+		{"Single author", []string{"test@example.com"}, []string{"first", "third"}},
+		{"Multiple authors", []string{"test@example.com", "other@example.com"}, []string{"first", "second", "third"}},
+		{"Unknown author", []string{"unknown@example.com"}, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, err := ReadAuthorCommits(repoPath, tt.emails)
+			if err != nil {
+				t.Fatalf("ReadAuthorCommits() error = %v", err)
+			}
+
+			if len(records) != len(tt.expected) {
+				t.Fatalf("Expected %d commits, got %d", len(tt.expected), len(records))
+			}
+
+			for i, message := range tt.expected {
+				if records[i].Message != message {
+					t.Errorf("At index %d: expected message %q, got %q", i, message, records[i].Message)
+				}
+				if records[i].RepoPath != repoPath {
+					t.Errorf("At index %d: expected repo path %q, got %q", i, repoPath, records[i].RepoPath)
+				}
+			}
+		})
+	}
+}
+
+func Test_ReadAuthorCommits_InvalidRepo(t *testing.T) {
+	if _, err := ReadAuthorCommits("/path/to/invalid/repo", []string{"test@example.com"}); err == nil {
+		t.Error("Expected error for invalid repository, got nil")
+	}
+}
+
+func Test_ReadAuthorCommits_Stats(t *testing.T) {
+	base := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	repoPath, cleanup, err := PrepareTestRepository([]TestCommit{
+		{Author: "Test User", Email: "test@example.com", Message: "first", When: base, Files: map[string]string{
+			"main.go":   "package main\n\nfunc main() {}\n",
+			"README.md": "# Test\n",
+		}},
+		{Author: "Test User", Email: "test@example.com", Message: "second", When: base.Add(time.Hour), Files: map[string]string{
+			"main.go": "package main\n",
+		}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to prepare test repository: %v", err)
+	}
+	defer (*cleanup)()
+
+	records, err := ReadAuthorCommits(repoPath, []string{"test@example.com"})
+	if err != nil {
+		t.Fatalf("ReadAuthorCommits() error = %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		record       CommitRecord
+		additions    int
+		deletions    int
+		filesChanged int
+	}{
+		{"First commit", records[0], 4, 0, 2},
+		{"Second commit", records[1], 0, 2, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.record.Additions != tt.additions || tt.record.Deletions != tt.deletions {
+				t.Errorf("Expected +%d -%d, got +%d -%d", tt.additions, tt.deletions, tt.record.Additions, tt.record.Deletions)
+			}
+			if tt.record.FilesChanged() != tt.filesChanged {
+				t.Errorf("Expected %d files changed, got %d", tt.filesChanged, tt.record.FilesChanged())
+			}
+		})
+	}
+}
+
+func Test_ReadAuthorCommits_KeepsOffset(t *testing.T) {
+	tests := []struct {
+		name string
+		when time.Time
+	}{
+		{"UTC", time.Date(2025, 1, 1, 23, 30, 0, 0, time.UTC)},
+		{"East of UTC", time.Date(2025, 1, 1, 23, 30, 0, 0, time.FixedZone("", 9*60*60))},
+		{"West of UTC", time.Date(2025, 1, 1, 23, 30, 0, 0, time.FixedZone("", -(5*60*60+30*60)))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoPath, cleanup, err := PrepareTestRepository([]TestCommit{
+				{Author: "Test User", Email: "test@example.com", Message: "first", When: tt.when},
+			})
+			if err != nil {
+				t.Fatalf("Failed to prepare test repository: %v", err)
+			}
+			defer (*cleanup)()
+
+			records, err := ReadAuthorCommits(repoPath, []string{"test@example.com"})
+			if err != nil {
+				t.Fatalf("ReadAuthorCommits() error = %v", err)
+			}
+
+			_, wantOffset := tt.when.Zone()
+			_, gotOffset := records[0].When.Zone()
+			if gotOffset != wantOffset || !records[0].When.Equal(tt.when) {
+				t.Errorf("Expected %v, got %v", tt.when, records[0].When)
+			}
+		})
+	}
+}
+
+func Test_ReadTrackedCommits(t *testing.T) {
+	base := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	backendPath, backendCleanup, err := PrepareTestRepository([]TestCommit{
+		{Author: "Test User", Email: "test@example.com", Message: "backend first", When: base},
+		{Author: "Test User", Email: "test@example.com", Message: "backend second\n\nwith a body", When: base.Add(2 * time.Hour)},
+	})
+	if err != nil {
+		t.Fatalf("Failed to prepare test repository: %v", err)
+	}
+	defer (*backendCleanup)()
+
+	frontendPath, frontendCleanup, err := PrepareTestRepository([]TestCommit{
+		{Author: "Test User", Email: "test@example.com", Message: "frontend", When: base.Add(time.Hour)},
+	})
+	if err != nil {
+		t.Fatalf("Failed to prepare test repository: %v", err)
+	}
+	defer (*frontendCleanup)()
+
+	records, err := ReadTrackedCommits([]string{backendPath, frontendPath}, []string{"test@example.com"})
+	if err != nil {
+		t.Fatalf("ReadTrackedCommits() error = %v", err)
+	}
+
+	expected := []string{"backend first", "frontend", "backend second"}
+	if len(records) != len(expected) {
+		t.Fatalf("Expected %d commits, got %d", len(expected), len(records))
+	}
+	for i, subject := range expected {
+		if records[i].Subject() != subject {
+			t.Errorf("At index %d: expected subject %q, got %q", i, subject, records[i].Subject())
+		}
+	}
+
+	if _, err := ReadTrackedCommits([]string{"/path/to/invalid/repo"}, []string{"test@example.com"}); err == nil {
+		t.Error("Expected error for invalid repository, got nil")
+	}
+}
@@ -0,0 +1,24 @@
+package repo
+
+import (
+	"regexp"
+	"strings"
+)
+
+var conventionalCommitRe = regexp.MustCompile(`^([a-zA-Z]+)(\([^)]*\))?!?:\s`)
+
+// ConventionalType returns the conventional-commit type of a commit message
+// (feat, fix, chore...), or an empty string when the subject does not follow
+// the convention.
+func ConventionalType(message string) string {
+	subject, _, _ := strings.Cut(message, "\n")
+	match := conventionalCommitRe.FindStringSubmatch(strings.TrimSpace(subject) + " ")
+	if match == nil {
+		return ""
+	}
+	return strings.ToLower(match[1])
+}
+
+func (c CommitRecord) ConventionalType() string {
+	return ConventionalType(c.Message)
+}
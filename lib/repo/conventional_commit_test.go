@@ -0,0 +1,27 @@
+package repo
+
+import "testing"
+
+func Test_ConventionalType(t *testing.T) {
+	tests := []struct {
+		name     string
+		message  string
+		expected string
+	}{
+		{"Simple type", "feat: add export", "feat"},
+		{"Scoped type", "fix(config): handle empty path\n\nbody", "fix"},
+		{"Breaking change", "refactor!: drop v0 config", "refactor"},
+		{"Uppercase type", "Chore: bump deps", "chore"},
+		{"Not conventional", "Add export command", ""},
+		{"Missing space", "feat:add export", ""},
+		{"Empty message", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ConventionalType(tt.message); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
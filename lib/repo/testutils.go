@@ -0,0 +1,71 @@
+package repo
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-git/go-git/v6"
+	"github.com/go-git/go-git/v6/plumbing/object"
+)
+
+type TestCommit struct {
+	Author  string
+	Email   string
+	Message string
+	When    time.Time
+	Files   map[string]string
+}
+
+func PrepareTestRepository(commits []TestCommit) (string, *func(), error) {
+	repoPath, err := os.MkdirTemp("", "tracko_test_repo_*")
+	if err != nil {
+		return "", nil, err
+	}
+
+	cleanup := func() {
+		os.RemoveAll(repoPath)
+	}
+
+	r, err := git.PlainInit(repoPath, false)
+	if err != nil {
+		cleanup()
+		return "", nil, err
+	}
+
+	wt, err := r.Worktree()
+	if err != nil {
+		cleanup()
+		return "", nil, err
+	}
+
+	for _, c := range commits {
+		for name, content := range c.Files {
+			filePath := filepath.Join(repoPath, name)
+			if err := os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
+				cleanup()
+				return "", nil, err
+			}
+			if err := os.WriteFile(filePath, []byte(content), 0o644); err != nil {
+				cleanup()
+				return "", nil, err
+			}
+			if _, err := wt.Add(name); err != nil {
+				cleanup()
+				return "", nil, err
+			}
+		}
+
+		signature := &object.Signature{Name: c.Author, Email: c.Email, When: c.When}
+		_, err := wt.Commit(c.Message, &git.CommitOptions{
+			Author:            signature,
+			AllowEmptyCommits: true,
+		})
+		if err != nil {
+			cleanup()
+			return "", nil, err
+		}
+	}
+
+	return repoPath, &cleanup, nil
+}
@@ -0,0 +1,239 @@
+package report
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/HideyoshiNakazone/tracko/lib/config_model"
+	"github.com/HideyoshiNakazone/tracko/lib/repo"
+)
+
+// Limits of the notable lists of every repo.
+const (
+	LargestCommits = 3
+	TopSubjects    = 5
+)
+
+//go:embed templates/brag.md.tmpl
+var DefaultBragTemplate string
+
+// Stats sums the size of a set of commits.
+type Stats struct {
+	Commits      int
+	Additions    int
+	Deletions    int
+	FilesChanged int
+}
+
+func (s *Stats) add(record repo.CommitRecord) {
+	s.Commits++
+	s.Additions += record.Additions
+	s.Deletions += record.Deletions
+	s.FilesChanged += record.FilesChanged()
+}
+
+// BragCommit is a single commit quoted in a brag document.
+type BragCommit struct {
+	Hash         string
+	Date         string
+	Type         string
+	Subject      string
+	Additions    int
+	Deletions    int
+	FilesChanged int
+}
+
+// BragSubject is a subject shared by Count commits, without its
+// conventional commit prefix.
+type BragSubject struct {
+	Subject string
+	Count   int
+}
+
+// BragMonth holds the commits of a repo in a calendar month. Features are
+// the conventional feat commits of the month.
+type BragMonth struct {
+	Month    string
+	Label    string
+	Stats    Stats
+	Features []BragCommit
+}
+
+// BragRepo holds the commits of a repo, Largest are the commits with the
+// most lines changed.
+type BragRepo struct {
+	Repo        string
+	Stats       Stats
+	Months      []BragMonth
+	Largest     []BragCommit
+	TopSubjects []BragSubject
+}
+
+// BragData is passed to brag templates. Repos are sorted by number of
+// commits, months from oldest to newest.
+type BragData struct {
+	Author string
+	Period Period
+	Stats  Stats
+	Repos  []BragRepo
+}
+
+var conventionalPrefix = regexp.MustCompile(`^[a-zA-Z]+(\([^)]*\))?!?:\s*`)
+
+// markdownEscaper escapes the characters that would format text in
+// Markdown, so that commit subjects are quoted as written.
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`,
+	"<", `\<`, ">", `\>`, "|", `\|`, "#", `\#`,
+)
+
+// NewBragData builds the brag document data of the records made during
+// period.
+func NewBragData(cfg *config_model.ConfigModel, period Period, records []repo.CommitRecord) BragData {
+	data := BragData{Author: cfg.TrackedAuthor().Name(), Period: period}
+
+	byRepo := map[string][]repo.CommitRecord{}
+	for _, record := range period.Filter(records) {
+		alias := filepath.Base(record.RepoPath)
+		byRepo[alias] = append(byRepo[alias], record)
+		data.Stats.add(record)
+	}
+
+	for alias, repoRecords := range byRepo {
+		data.Repos = append(data.Repos, newBragRepo(alias, repoRecords))
+	}
+	sort.Slice(data.Repos, func(i, j int) bool {
+		if data.Repos[i].Stats.Commits != data.Repos[j].Stats.Commits {
+			return data.Repos[i].Stats.Commits > data.Repos[j].Stats.Commits
+		}
+		return data.Repos[i].Repo < data.Repos[j].Repo
+	})
+
+	return data
+}
+
+func newBragRepo(alias string, records []repo.CommitRecord) BragRepo {
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].When.Before(records[j].When)
+	})
+
+	bragRepo := BragRepo{Repo: alias}
+	for _, record := range records {
+		bragRepo.Stats.add(record)
+
+		month := record.When.Format("2006-01")
+		if n := len(bragRepo.Months); n == 0 || bragRepo.Months[n-1].Month != month {
+			bragRepo.Months = append(bragRepo.Months, BragMonth{Month: month, Label: record.When.Format("January 2006")})
+		}
+		current := &bragRepo.Months[len(bragRepo.Months)-1]
+		current.Stats.add(record)
+		if record.ConventionalType() == "feat" {
+			current.Features = append(current.Features, newBragCommit(record))
+		}
+	}
+
+	largest := append([]repo.CommitRecord(nil), records...)
+	sort.SliceStable(largest, func(i, j int) bool {
+		return size(largest[i]) > size(largest[j])
+	})
+	for _, record := range largest[:min(LargestCommits, len(largest))] {
+		bragRepo.Largest = append(bragRepo.Largest, newBragCommit(record))
+	}
+
+	bragRepo.TopSubjects = topSubjects(records)
+	return bragRepo
+}
+
+func newBragCommit(record repo.CommitRecord) BragCommit {
+	return BragCommit{
+		Hash:         record.Hash[:min(7, len(record.Hash))],
+		Date:         record.When.Format(config_model.DateFormat),
+		Type:         record.ConventionalType(),
+		Subject:      record.Subject(),
+		Additions:    record.Additions,
+		Deletions:    record.Deletions,
+		FilesChanged: record.FilesChanged(),
+	}
+}
+
+func size(record repo.CommitRecord) int {
+	return record.Additions + record.Deletions
+}
+
+// topSubjects returns the most repeated subjects, ignoring case and the
+// conventional commit prefix. Subjects repeated as often are ranked by the
+// lines they changed.
+func topSubjects(records []repo.CommitRecord) []BragSubject {
+	type subjectStats struct {
+		BragSubject
+		size  int
+		order int
+	}
+
+	bySubject := map[string]*subjectStats{}
+	for _, record := range records {
+		subject := strings.TrimSpace(conventionalPrefix.ReplaceAllString(record.Subject(), ""))
+		if subject == "" {
+			continue
+		}
+		key := strings.ToLower(subject)
+		if bySubject[key] == nil {
+			bySubject[key] = &subjectStats{BragSubject: BragSubject{Subject: subject}, order: len(bySubject)}
+		}
+		bySubject[key].Count++
+		bySubject[key].size += size(record)
+	}
+
+	ranked := make([]*subjectStats, 0, len(bySubject))
+	for _, stats := range bySubject {
+		ranked = append(ranked, stats)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Count != ranked[j].Count {
+			return ranked[i].Count > ranked[j].Count
+		}
+		if ranked[i].size != ranked[j].size {
+			return ranked[i].size > ranked[j].size
+		}
+		return ranked[i].order < ranked[j].order
+	})
+
+	var subjects []BragSubject
+	for _, stats := range ranked[:min(TopSubjects, len(ranked))] {
+		subjects = append(subjects, stats.BragSubject)
+	}
+	return subjects
+}
+
+var bragFuncs = template.FuncMap{
+	// md escapes text quoted from commits
+	"md": markdownEscaper.Replace,
+	// plural returns "1 commit" or "2 commits"
+	"plural": func(count int, noun string) string {
+		if count == 1 {
+			return fmt.Sprintf("%d %s", count, noun)
+		}
+		return fmt.Sprintf("%d %ss", count, noun)
+	},
+}
+
+// ParseBragTemplate parses a brag document template, text is the default
+// template when empty. Templates get BragData and the md and plural
+// functions.
+func ParseBragTemplate(text string) (*template.Template, error) {
+	if text == "" {
+		text = DefaultBragTemplate
+	}
+	return template.New("brag").Funcs(bragFuncs).Parse(text)
+}
+
+// WriteBrag writes the brag document of data with tmpl.
+func WriteBrag(w io.Writer, tmpl *template.Template, data BragData) error {
+	return tmpl.Execute(w, data)
+}
@@ -0,0 +1,152 @@
+package report
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/HideyoshiNakazone/tracko/lib/config_model"
+	"github.com/HideyoshiNakazone/tracko/lib/repo"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+func buildConfig(t *testing.T) *config_model.ConfigModel {
+	t.Helper()
+
+	cfg, err := config_model.NewConfigBuilder().
+		WithTrackedAuthor("Test User", []string{"test@example.com"}).
+		WithTargetRepo("target", "Test User", "test@personal.example.com").
+		Build()
+	if err != nil {
+		t.Fatalf("Failed to build config: %v", err)
+	}
+	return cfg
+}
+
+func bragRecords() []repo.CommitRecord {
+	at := func(month time.Month, day int) time.Time {
+		return time.Date(2026, month, day, 10, 0, 0, 0, time.UTC)
+	}
+	files := func(n int) []string {
+		names := make([]string, n)
+		for i := range names {
+			names[i] = "file" + string(rune('a'+i)) + ".go"
+		}
+		return names
+	}
+
+	return []repo.CommitRecord{
+		{RepoPath: "/src/backend", Hash: "1111111aaaa", When: at(1, 5), Message: "feat(api): add users endpoint", Additions: 120, Deletions: 4, Files: files(3)},
+		{RepoPath: "/src/backend", Hash: "2222222bbbb", When: at(1, 6), Message: "fix: handle empty pages", Additions: 5, Deletions: 2, Files: files(1)},
+		{RepoPath: "/src/backend", Hash: "3333333cccc", When: at(1, 20), Message: "chore: bump deps", Additions: 300, Deletions: 280, Files: files(2)},
+		{RepoPath: "/src/backend", Hash: "4444444dddd", When: at(3, 2), Message: "feat: support *bulk* imports | exports", Additions: 80, Deletions: 10, Files: files(4)},
+		{RepoPath: "/src/backend", Hash: "5555555eeee", When: at(3, 3), Message: "fix: handle empty pages", Additions: 3, Deletions: 1, Files: files(1)},
+		{RepoPath: "/src/frontend", Hash: "6666666ffff", When: at(2, 14), Message: "feat: dark mode", Additions: 60, Deletions: 12, Files: files(5)},
+		{RepoPath: "/src/frontend", Hash: "7777777gggg", When: at(6, 30), Message: "Polish settings page", Additions: 9, Deletions: 9, Files: files(2)},
+		// Outside of 2026-H1
+		{RepoPath: "/src/frontend", Hash: "8888888hhhh", When: at(7, 1), Message: "feat: out of period", Additions: 1000, Files: files(1)},
+		{RepoPath: "/src/backend", Hash: "9999999iiii", When: time.Date(2025, 12, 31, 23, 0, 0, 0, time.UTC), Message: "feat: last year", Additions: 1000, Files: files(1)},
+	}
+}
+
+func Test_WriteBrag_Snapshot(t *testing.T) {
+	period, err := ParsePeriod("2026-H1")
+	if err != nil {
+		t.Fatalf("ParsePeriod() error = %v", err)
+	}
+
+	tmpl, err := ParseBragTemplate("")
+	if err != nil {
+		t.Fatalf("ParseBragTemplate() error = %v", err)
+	}
+
+	var buffer bytes.Buffer
+	if err := WriteBrag(&buffer, tmpl, NewBragData(buildConfig(t), period, bragRecords())); err != nil {
+		t.Fatalf("WriteBrag() error = %v", err)
+	}
+
+	golden := filepath.Join("testdata", "brag_2026_h1.md")
+	if *update {
+		if err := os.WriteFile(golden, buffer.Bytes(), 0o644); err != nil {
+			t.Fatalf("Failed to update %s: %v", golden, err)
+		}
+	}
+
+	expected, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("Failed to read %s, run the tests with -update to create it: %v", golden, err)
+	}
+	if !bytes.Equal(buffer.Bytes(), expected) {
+		t.Errorf("Brag document differs from %s, run the tests with -update if the change is intended:\n%s", golden, buffer.String())
+	}
+}
+
+func Test_WriteBrag_Empty(t *testing.T) {
+	period, _ := ParsePeriod("2024")
+	tmpl, _ := ParseBragTemplate("")
+
+	var buffer bytes.Buffer
+	if err := WriteBrag(&buffer, tmpl, NewBragData(buildConfig(t), period, bragRecords())); err != nil {
+		t.Fatalf("WriteBrag() error = %v", err)
+	}
+	if !strings.Contains(buffer.String(), "No commits in this period.") {
+		t.Errorf("Expected an empty report, got %q", buffer.String())
+	}
+}
+
+func Test_WriteBrag_CustomTemplate(t *testing.T) {
+	period, _ := ParsePeriod("2026-Q1")
+	tmpl, err := ParseBragTemplate(`{{range .Repos}}{{.Repo}}={{plural .Stats.Commits "commit"}};{{end}}`)
+	if err != nil {
+		t.Fatalf("ParseBragTemplate() error = %v", err)
+	}
+
+	var buffer bytes.Buffer
+	if err := WriteBrag(&buffer, tmpl, NewBragData(buildConfig(t), period, bragRecords())); err != nil {
+		t.Fatalf("WriteBrag() error = %v", err)
+	}
+	if expected := "backend=5 commits;frontend=1 commit;"; buffer.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buffer.String())
+	}
+}
+
+func Test_ParsePeriod(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		value   string
+		want    Period
+		wantErr bool
+	}{
+		{"2026", Period{"2026", date(2026, 1, 1), date(2026, 12, 31)}, false},
+		{"2026-H1", Period{"2026-H1", date(2026, 1, 1), date(2026, 6, 30)}, false},
+		{"2026-h2", Period{"2026-H2", date(2026, 7, 1), date(2026, 12, 31)}, false},
+		{"2026-Q1", Period{"2026-Q1", date(2026, 1, 1), date(2026, 3, 31)}, false},
+		{"2024-Q4", Period{"2024-Q4", date(2024, 10, 1), date(2024, 12, 31)}, false},
+		{"2024-02", Period{"February 2024", date(2024, 2, 1), date(2024, 2, 29)}, false},
+		{"2026-01-15..2026-04-30", Period{"2026-01-15 to 2026-04-30", date(2026, 1, 15), date(2026, 4, 30)}, false},
+		{"2026-H3", Period{}, true},
+		{"2026-13", Period{}, true},
+		{"2026-04-30..2026-01-15", Period{}, true},
+		{"last year", Period{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParsePeriod(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePeriod() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParsePeriod() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
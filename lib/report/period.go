@@ -0,0 +1,88 @@
+package report
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/HideyoshiNakazone/tracko/lib/config_model"
+	"github.com/HideyoshiNakazone/tracko/lib/repo"
+	"github.com/HideyoshiNakazone/tracko/lib/utils"
+)
+
+// Period is a span of days covered by a report, both ends inclusive.
+type Period struct {
+	Label string
+	From  time.Time
+	To    time.Time
+}
+
+var (
+	halfPeriod    = regexp.MustCompile(`^(\d{4})-[Hh]([12])$`)
+	quarterPeriod = regexp.MustCompile(`^(\d{4})-[Qq]([1-4])$`)
+	monthPeriod   = regexp.MustCompile(`^(\d{4})-(\d{2})$`)
+)
+
+// ParsePeriod reads a report period, one of:
+//
+//	2026                    a year
+//	2026-H1                 a half year, H1 or H2
+//	2026-Q3                 a quarter, Q1 to Q4
+//	2026-03                 a month
+//	2026-01-15..2026-04-30  a range of days
+func ParsePeriod(value string) (Period, error) {
+	from, to, ok, err := utils.ParseDays(value)
+	if err != nil {
+		return Period{}, fmt.Errorf("invalid period %q, %v", value, err)
+	}
+	if ok {
+		return Period{Label: strings.Replace(value, "..", " to ", 1), From: from, To: to}, nil
+	}
+
+	if match := halfPeriod.FindStringSubmatch(value); match != nil {
+		year, _ := strconv.Atoi(match[1])
+		half, _ := strconv.Atoi(match[2])
+		return monthsPeriod(fmt.Sprintf("%d-H%d", year, half), year, half*6-5, 6), nil
+	}
+	if match := quarterPeriod.FindStringSubmatch(value); match != nil {
+		year, _ := strconv.Atoi(match[1])
+		quarter, _ := strconv.Atoi(match[2])
+		return monthsPeriod(fmt.Sprintf("%d-Q%d", year, quarter), year, quarter*3-2, 3), nil
+	}
+	if match := monthPeriod.FindStringSubmatch(value); match != nil {
+		year, _ := strconv.Atoi(match[1])
+		month, _ := strconv.Atoi(match[2])
+		if month >= 1 && month <= 12 {
+			period := monthsPeriod(value, year, month, 1)
+			period.Label = period.From.Format("January 2006")
+			return period, nil
+		}
+	}
+
+	return Period{}, fmt.Errorf("invalid period %q, expected a year, 2026-H1, 2026-Q1, 2026-01 or FROM..TO", value)
+}
+
+func monthsPeriod(label string, year int, firstMonth int, months int) Period {
+	from := time.Date(year, time.Month(firstMonth), 1, 0, 0, 0, 0, time.UTC)
+	return Period{Label: label, From: from, To: from.AddDate(0, months, -1)}
+}
+
+// Includes reports whether a commit made at when falls in the period, on the
+// calendar day of its own UTC offset.
+func (p Period) Includes(when time.Time) bool {
+	day := when.Format(config_model.DateFormat)
+	return day >= p.From.Format(config_model.DateFormat) && day <= p.To.Format(config_model.DateFormat)
+}
+
+// Filter keeps the records made during the period.
+func (p Period) Filter(records []repo.CommitRecord) []repo.CommitRecord {
+	var filtered []repo.CommitRecord
+	for _, record := range records {
+		if p.Includes(record.When) {
+			filtered = append(filtered, record)
+		}
+	}
+	return filtered
+}
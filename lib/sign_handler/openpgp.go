@@ -0,0 +1,58 @@
+package sign_handler
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+
+	"github.com/HideyoshiNakazone/tracko/lib/internal_errors"
+)
+
+type openPGPSigner struct {
+	entity *openpgp.Entity
+}
+
+// NewOpenPGPSigner signs with the first private key of an armored key ring,
+// the key and its subkeys are decrypted with passphrase when encrypted.
+func NewOpenPGPSigner(armoredKey []byte, passphrase string) (*openPGPSigner, error) {
+	entities, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(armoredKey))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", internal_errors.ErrInvalidSigningKey, err)
+	}
+
+	var entity *openpgp.Entity
+	for _, candidate := range entities {
+		if candidate.PrivateKey != nil {
+			entity = candidate
+			break
+		}
+	}
+	if entity == nil {
+		return nil, fmt.Errorf("%w: no private key found", internal_errors.ErrInvalidSigningKey)
+	}
+
+	if entity.PrivateKey.Encrypted {
+		if err := entity.PrivateKey.Decrypt([]byte(passphrase)); err != nil {
+			return nil, fmt.Errorf("%w: %v", internal_errors.ErrInvalidSigningKey, err)
+		}
+	}
+	for _, subkey := range entity.Subkeys {
+		if subkey.PrivateKey != nil && subkey.PrivateKey.Encrypted {
+			if err := subkey.PrivateKey.Decrypt([]byte(passphrase)); err != nil {
+				return nil, fmt.Errorf("%w: %v", internal_errors.ErrInvalidSigningKey, err)
+			}
+		}
+	}
+
+	return &openPGPSigner{entity: entity}, nil
+}
+
+func (s *openPGPSigner) Sign(message io.Reader) ([]byte, error) {
+	var signature bytes.Buffer
+	if err := openpgp.ArmoredDetachSign(&signature, s.entity, message, nil); err != nil {
+		return nil, err
+	}
+	return signature.Bytes(), nil
+}
@@ -0,0 +1,44 @@
+package sign_handler
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/go-git/go-git/v6"
+
+	"github.com/HideyoshiNakazone/tracko/lib/config_model"
+	"github.com/HideyoshiNakazone/tracko/lib/internal_errors"
+)
+
+// NewSigner loads the signing key configured for a target. A nil signer is
+// returned when signing is disabled, which go-git treats as unsigned.
+func NewSigner(signing config_model.ConfigSigningModel) (git.Signer, error) {
+	if !signing.Enabled() {
+		return nil, nil
+	}
+
+	keyPath := os.ExpandEnv(signing.KeyPath())
+	data, err := os.ReadFile(keyPath)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", internal_errors.ErrInvalidSigningKey, err)
+	}
+
+	passphrase := ""
+	if signing.PassphraseEnv() != "" {
+		passphrase = os.Getenv(signing.PassphraseEnv())
+	}
+
+	var signer git.Signer
+	switch signing.Format() {
+	case config_model.SigningFormatOpenPGP:
+		signer, err = NewOpenPGPSigner(data, passphrase)
+	case config_model.SigningFormatSSH:
+		signer, err = NewSSHSigner(data, passphrase)
+	default:
+		err = fmt.Errorf("%w: unknown format %q", internal_errors.ErrInvalidSigningKey, signing.Format())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return signer, nil
+}
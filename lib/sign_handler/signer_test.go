@@ -0,0 +1,212 @@
+package sign_handler
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"encoding/pem"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/HideyoshiNakazone/tracko/lib/config_model"
+	"github.com/HideyoshiNakazone/tracko/lib/internal_errors"
+)
+
+const testMessage = "tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904\n\nMirrored contribution\n"
+
+func signWith(t *testing.T, format string, keyPath string, passphrase string) ([]byte, error) {
+	t.Helper()
+
+	passphraseEnv := ""
+	if passphrase != "" {
+		passphraseEnv = "TRACKO_TEST_SIGNING_PASSPHRASE"
+		t.Setenv(passphraseEnv, passphrase)
+	}
+
+	signing := config_model.SigningDTO{Format: format, KeyPath: keyPath, PassphraseEnv: passphraseEnv}.ToModel()
+	signer, err := NewSigner(signing)
+	if err != nil {
+		return nil, err
+	}
+	return signer.Sign(strings.NewReader(testMessage))
+}
+
+func Test_NewSigner_Disabled(t *testing.T) {
+	signer, err := NewSigner(config_model.NewDefaultSigningModel())
+	if err != nil || signer != nil {
+		t.Errorf("Expected no signer and no error, got %v, %v", signer, err)
+	}
+}
+
+func Test_NewSigner_MissingKey(t *testing.T) {
+	signing := config_model.SigningDTO{Format: config_model.SigningFormatSSH, KeyPath: "/path/to/missing/key"}.ToModel()
+	if _, err := NewSigner(signing); !errors.Is(err, internal_errors.ErrInvalidSigningKey) {
+		t.Errorf("Expected ErrInvalidSigningKey, got %v", err)
+	}
+}
+
+func Test_OpenPGPSigner(t *testing.T) {
+	tests := []struct {
+		name       string
+		passphrase string
+		given      string
+		wantErr    bool
+	}{
+		{"Unencrypted key", "", "", false},
+		{"Encrypted key", "secret", "secret", false},
+		{"Wrong passphrase", "secret", "wrong", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, cleanup, err := PrepareTestOpenPGPKey(tt.passphrase)
+			if err != nil {
+				t.Fatalf("Failed to prepare key: %v", err)
+			}
+			defer (*cleanup)()
+
+			signature, err := signWith(t, config_model.SigningFormatOpenPGP, key.Path, tt.given)
+			if tt.wantErr {
+				if !errors.Is(err, internal_errors.ErrInvalidSigningKey) {
+					t.Errorf("Expected ErrInvalidSigningKey, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Sign() error = %v", err)
+			}
+
+			keyring, err := openpgp.ReadArmoredKeyRing(strings.NewReader(key.PublicKey))
+			if err != nil {
+				t.Fatalf("Failed to read public key: %v", err)
+			}
+			if _, err := openpgp.CheckArmoredDetachedSignature(keyring, strings.NewReader(testMessage), bytes.NewReader(signature), nil); err != nil {
+				t.Errorf("Expected a valid signature, got %v", err)
+			}
+		})
+	}
+}
+
+func Test_SSHSigner(t *testing.T) {
+	tests := []struct {
+		name       string
+		keyType    string
+		passphrase string
+		given      string
+		wantErr    bool
+	}{
+		{"Ed25519 key", "ed25519", "", "", false},
+		{"Encrypted ed25519 key", "ed25519", "secret", "secret", false},
+		{"RSA key", "rsa", "", "", false},
+		{"Wrong passphrase", "ed25519", "secret", "wrong", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, cleanup, err := PrepareTestSSHKey(tt.keyType, tt.passphrase)
+			if err != nil {
+				t.Fatalf("Failed to prepare key: %v", err)
+			}
+			defer (*cleanup)()
+
+			signature, err := signWith(t, config_model.SigningFormatSSH, key.Path, tt.given)
+			if tt.wantErr {
+				if !errors.Is(err, internal_errors.ErrInvalidSigningKey) {
+					t.Errorf("Expected ErrInvalidSigningKey, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Sign() error = %v", err)
+			}
+
+			verifySSHSignature(t, key.PublicKey, signature)
+			verifySSHSignatureWithSSHKeygen(t, key.PublicKey, signature)
+		})
+	}
+}
+
+// verifySSHSignature checks an armored SSHSIG signature of testMessage
+// following the OpenSSH PROTOCOL.sshsig format.
+func verifySSHSignature(t *testing.T, authorizedKey string, armored []byte) {
+	t.Helper()
+
+	block, _ := pem.Decode(armored)
+	if block == nil || block.Type != "SSH SIGNATURE" {
+		t.Fatalf("Expected an SSH SIGNATURE block, got %q", armored)
+	}
+
+	var blob struct {
+		Magic     [6]byte
+		Version   uint32
+		PublicKey []byte
+		Namespace string
+		Reserved  string
+		Hash      string
+		Signature []byte
+	}
+	if err := ssh.Unmarshal(block.Bytes, &blob); err != nil {
+		t.Fatalf("Failed to parse signature: %v", err)
+	}
+	if string(blob.Magic[:]) != "SSHSIG" || blob.Version != 1 || blob.Namespace != "git" || blob.Hash != "sha512" {
+		t.Fatalf("Unexpected signature header %+v", blob)
+	}
+
+	expected, _, _, _, err := ssh.ParseAuthorizedKey([]byte(authorizedKey))
+	if err != nil {
+		t.Fatalf("Failed to parse public key: %v", err)
+	}
+	if !bytes.Equal(blob.PublicKey, expected.Marshal()) {
+		t.Fatalf("Expected the signature to embed the signing public key")
+	}
+
+	var signature ssh.Signature
+	if err := ssh.Unmarshal(blob.Signature, &signature); err != nil {
+		t.Fatalf("Failed to parse signature: %v", err)
+	}
+
+	digest := sha512.Sum512([]byte(testMessage))
+	signedData := ssh.Marshal(struct {
+		Magic     [6]byte
+		Namespace string
+		Reserved  string
+		Hash      string
+		Digest    []byte
+	}{blob.Magic, blob.Namespace, blob.Reserved, blob.Hash, digest[:]})
+
+	if err := expected.Verify(signedData, &signature); err != nil {
+		t.Errorf("Expected a valid signature, got %v", err)
+	}
+}
+
+// verifySSHSignatureWithSSHKeygen checks the signature the way git does when
+// ssh-keygen is available.
+func verifySSHSignatureWithSSHKeygen(t *testing.T, authorizedKey string, armored []byte) {
+	t.Helper()
+
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		return
+	}
+
+	dir := t.TempDir()
+	allowedSigners := filepath.Join(dir, "allowed_signers")
+	signaturePath := filepath.Join(dir, "message.sig")
+	if err := os.WriteFile(allowedSigners, []byte("test@example.com "+authorizedKey), 0o644); err != nil {
+		t.Fatalf("Failed to write allowed signers: %v", err)
+	}
+	if err := os.WriteFile(signaturePath, armored, 0o644); err != nil {
+		t.Fatalf("Failed to write signature: %v", err)
+	}
+
+	command := exec.Command("ssh-keygen", "-Y", "verify", "-f", allowedSigners, "-I", "test@example.com", "-n", "git", "-s", signaturePath)
+	command.Stdin = strings.NewReader(testMessage)
+	if output, err := command.CombinedOutput(); err != nil {
+		t.Errorf("ssh-keygen rejected the signature: %v: %s", err, output)
+	}
+}
@@ -0,0 +1,96 @@
+package sign_handler
+
+import (
+	"crypto/sha512"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/ssh"
+
+	"github.com/HideyoshiNakazone/tracko/lib/internal_errors"
+)
+
+// SSH signatures follow the SSHSIG format of OpenSSH, the one git verifies
+// with gpg.format=ssh.
+const (
+	sshSigMagic     = "SSHSIG"
+	sshSigVersion   = 1
+	sshSigNamespace = "git"
+	sshSigHash      = "sha512"
+)
+
+type sshSigner struct {
+	signer ssh.Signer
+}
+
+// NewSSHSigner signs with an OpenSSH private key, decrypted with passphrase
+// when encrypted.
+func NewSSHSigner(privateKey []byte, passphrase string) (*sshSigner, error) {
+	signer, err := ssh.ParsePrivateKey(privateKey)
+
+	var missing *ssh.PassphraseMissingError
+	if errors.As(err, &missing) {
+		signer, err = ssh.ParsePrivateKeyWithPassphrase(privateKey, []byte(passphrase))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", internal_errors.ErrInvalidSigningKey, err)
+	}
+
+	return &sshSigner{signer: signer}, nil
+}
+
+func (s *sshSigner) Sign(message io.Reader) ([]byte, error) {
+	hash := sha512.New()
+	if _, err := io.Copy(hash, message); err != nil {
+		return nil, err
+	}
+
+	signedData := ssh.Marshal(struct {
+		Magic     [6]byte
+		Namespace string
+		Reserved  string
+		Hash      string
+		Digest    string
+	}{
+		Magic:     [6]byte([]byte(sshSigMagic)),
+		Namespace: sshSigNamespace,
+		Hash:      sshSigHash,
+		Digest:    string(hash.Sum(nil)),
+	})
+
+	signature, err := s.sign(signedData)
+	if err != nil {
+		return nil, err
+	}
+
+	blob := ssh.Marshal(struct {
+		Magic     [6]byte
+		Version   uint32
+		PublicKey string
+		Namespace string
+		Reserved  string
+		Hash      string
+		Signature string
+	}{
+		Magic:     [6]byte([]byte(sshSigMagic)),
+		Version:   sshSigVersion,
+		PublicKey: string(s.signer.PublicKey().Marshal()),
+		Namespace: sshSigNamespace,
+		Hash:      sshSigHash,
+		Signature: string(ssh.Marshal(signature)),
+	})
+
+	return pem.EncodeToMemory(&pem.Block{Type: "SSH SIGNATURE", Bytes: blob}), nil
+}
+
+// sign uses rsa-sha2-512 for RSA keys, OpenSSH rejects SHA-1 signatures.
+func (s *sshSigner) sign(data []byte) (*ssh.Signature, error) {
+	if s.signer.PublicKey().Type() == ssh.KeyAlgoRSA {
+		if algorithmSigner, ok := s.signer.(ssh.AlgorithmSigner); ok {
+			return algorithmSigner.SignWithAlgorithm(nil, data, ssh.KeyAlgoRSASHA512)
+		}
+	}
+	return s.signer.Sign(nil, data)
+}
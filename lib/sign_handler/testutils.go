@@ -0,0 +1,134 @@
+package sign_handler
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/pem"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"golang.org/x/crypto/ssh"
+)
+
+// TestKey is a signing key written to a temporary directory, PublicKey is
+// the armored OpenPGP public key or the SSH authorized key line.
+type TestKey struct {
+	Path      string
+	PublicKey string
+}
+
+// PrepareTestOpenPGPKey writes an armored OpenPGP private key, encrypted with
+// passphrase unless it is empty.
+func PrepareTestOpenPGPKey(passphrase string) (*TestKey, *func(), error) {
+	dir, err := os.MkdirTemp("", "tracko_test_key_*")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cleanup := func() {
+		os.RemoveAll(dir)
+	}
+
+	entity, err := openpgp.NewEntity("Test User", "", "test@example.com", nil)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+
+	var public bytes.Buffer
+	if err := writeArmored(&public, openpgp.PublicKeyType, entity.Serialize); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+
+	if passphrase != "" {
+		if err := entity.EncryptPrivateKeys([]byte(passphrase), nil); err != nil {
+			cleanup()
+			return nil, nil, err
+		}
+	}
+
+	var private bytes.Buffer
+	err = writeArmored(&private, openpgp.PrivateKeyType, func(w io.Writer) error {
+		return entity.SerializePrivateWithoutSigning(w, nil)
+	})
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+
+	keyPath := filepath.Join(dir, "key.asc")
+	if err := os.WriteFile(keyPath, private.Bytes(), 0o600); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+
+	return &TestKey{Path: keyPath, PublicKey: public.String()}, &cleanup, nil
+}
+
+// PrepareTestSSHKey writes an OpenSSH private key of the given type, ed25519
+// or rsa, encrypted with passphrase unless it is empty.
+func PrepareTestSSHKey(keyType string, passphrase string) (*TestKey, *func(), error) {
+	dir, err := os.MkdirTemp("", "tracko_test_key_*")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cleanup := func() {
+		os.RemoveAll(dir)
+	}
+
+	var key crypto.Signer
+	switch keyType {
+	case "rsa":
+		key, err = rsa.GenerateKey(rand.Reader, 2048)
+	default:
+		_, key, err = ed25519.GenerateKey(rand.Reader)
+	}
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+
+	var block *pem.Block
+	if passphrase != "" {
+		block, err = ssh.MarshalPrivateKeyWithPassphrase(key, "", []byte(passphrase))
+	} else {
+		block, err = ssh.MarshalPrivateKey(key, "")
+	}
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+
+	publicKey, err := ssh.NewPublicKey(key.Public())
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+
+	keyPath := filepath.Join(dir, "id_"+keyType)
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(block), 0o600); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+
+	return &TestKey{Path: keyPath, PublicKey: string(ssh.MarshalAuthorizedKey(publicKey))}, &cleanup, nil
+}
+
+func writeArmored(w io.Writer, blockType string, serialize func(io.Writer) error) error {
+	encoder, err := armor.Encode(w, blockType, nil)
+	if err != nil {
+		return err
+	}
+	if err := serialize(encoder); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
@@ -20,13 +20,19 @@ func ReadStringSliceInto(prompt string, dest *[]string) {
 	reader := bufio.NewReader(os.Stdin)
 	input, _ := reader.ReadString('\n')
 
+	*dest = append(*dest, SplitList(input)...)
+}
+
+// SplitList splits a comma separated list, skipping empty values.
+func SplitList(input string) []string {
+	values := []string{}
 	for _, value := range strings.Split(strings.TrimSpace(input), ",") {
 		value = strings.TrimSpace(value)
 		// Skip empty values
 		if value == "" {
 			continue
 		}
-		// Trim spaces and add to the destination slice
-		*dest = append(*dest, value)
+		values = append(values, value)
 	}
+	return values
 }
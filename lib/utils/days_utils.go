@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseDays reads a span of days, both ends inclusive, written as a year like
+// 2025 or as FROM..TO with both days as YYYY-MM-DD. It reports false when
+// value is written neither way, so callers can read spellings of their own.
+func ParseDays(value string) (time.Time, time.Time, bool, error) {
+	if from, to, ok := strings.Cut(value, ".."); ok {
+		fromDate, err := time.Parse(time.DateOnly, from)
+		if err != nil {
+			return time.Time{}, time.Time{}, true, fmt.Errorf("start %q is not a YYYY-MM-DD day", from)
+		}
+		toDate, err := time.Parse(time.DateOnly, to)
+		if err != nil {
+			return time.Time{}, time.Time{}, true, fmt.Errorf("end %q is not a YYYY-MM-DD day", to)
+		}
+		if toDate.Before(fromDate) {
+			return time.Time{}, time.Time{}, true, fmt.Errorf("it ends before it starts")
+		}
+		return fromDate, toDate, true, nil
+	}
+
+	year, err := strconv.Atoi(value)
+	if err != nil || len(value) != 4 || strings.ContainsAny(value, "+-") {
+		return time.Time{}, time.Time{}, false, nil
+	}
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	return from, from.AddDate(1, 0, -1), true, nil
+}
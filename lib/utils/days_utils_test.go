@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDays(t *testing.T) {
+	tests := []struct {
+		value   string
+		from    string
+		to      string
+		ok      bool
+		wantErr bool
+	}{
+		{"2025", "2025-01-01", "2025-12-31", true, false},
+		{"2024-02-01..2024-02-29", "2024-02-01", "2024-02-29", true, false},
+		{"2024-03-01..2024-03-01", "2024-03-01", "2024-03-01", true, false},
+		{"2024-03-01..2024-02-01", "", "", true, true},
+		{"2024-13-01..2024-12-31", "", "", true, true},
+		{"2024-01-01..tomorrow", "", "", true, true},
+		{"rolling", "", "", false, false},
+		{"2025-Q1", "", "", false, false},
+		{"+202", "", "", false, false},
+		{"20250", "", "", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			from, to, ok, err := ParseDays(tt.value)
+			if ok != tt.ok || (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDays(%q) ok = %v, error = %v, want ok %v, wantErr %v", tt.value, ok, err, tt.ok, tt.wantErr)
+			}
+			if tt.from == "" {
+				return
+			}
+			if got := from.Format(time.DateOnly) + ".." + to.Format(time.DateOnly); got != tt.from+".."+tt.to {
+				t.Errorf("ParseDays(%q) = %s, want %s..%s", tt.value, got, tt.from, tt.to)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"fmt"
 	"reflect"
 	"strings"
 )
@@ -52,3 +53,58 @@ func FindFieldByNameTree(t *reflect.Type, fieldNames *[]string) (*reflect.Struct
 	}
 	return nil, false
 }
+
+// DecodeToMap converts a struct into a map keyed by its mapstructure tags.
+// Unlike mapstructure.Decode it also converts structs nested inside slices,
+// so lists of structs are written to the config file with the right keys.
+func DecodeToMap(model interface{}) (map[string]any, error) {
+	v := reflect.ValueOf(model)
+	for v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("expected a struct, got %s", v.Kind())
+	}
+	return decodeStruct(v), nil
+}
+
+func decodeStruct(v reflect.Value) map[string]any {
+	m := map[string]any{}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, options, _ := strings.Cut(field.Tag.Get("mapstructure"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+
+		fieldValue := v.Field(i)
+		if strings.Contains(options, "omitempty") && fieldValue.IsZero() {
+			continue
+		}
+		m[name] = decodeValue(fieldValue)
+	}
+	return m
+}
+
+func decodeValue(v reflect.Value) any {
+	switch v.Kind() {
+	case reflect.Struct:
+		return decodeStruct(v)
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() != reflect.Struct {
+			return v.Interface()
+		}
+		items := make([]any, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			items = append(items, decodeStruct(v.Index(i)))
+		}
+		return items
+	default:
+		return v.Interface()
+	}
+}
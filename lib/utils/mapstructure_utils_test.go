@@ -1,6 +1,9 @@
 package utils
 
-import "testing"
+import (
+	"reflect"
+	"testing"
+)
 
 func Test_CheckModelHasTag(t *testing.T) {
 	type TestStruct struct {
@@ -16,3 +19,42 @@ func Test_CheckModelHasTag(t *testing.T) {
 		t.Error("Expected Field2 to not have restricted tag with value 'true'")
 	}
 }
+
+func Test_DecodeToMap(t *testing.T) {
+	type Child struct {
+		Name string `mapstructure:"name"`
+	}
+	type Parent struct {
+		Title    string   `mapstructure:"title"`
+		Tags     []string `mapstructure:"tags"`
+		Child    Child    `mapstructure:"child"`
+		Children []Child  `mapstructure:"children"`
+		Legacy   string   `mapstructure:"legacy,omitempty"`
+		internal string
+	}
+
+	m, err := DecodeToMap(&Parent{
+		Title:    "parent",
+		Tags:     []string{"a", "b"},
+		Child:    Child{Name: "child"},
+		Children: []Child{{Name: "first"}, {Name: "second"}},
+		internal: "hidden",
+	})
+	if err != nil {
+		t.Fatalf("DecodeToMap() error = %v", err)
+	}
+
+	expected := map[string]any{
+		"title":    "parent",
+		"tags":     []string{"a", "b"},
+		"child":    map[string]any{"name": "child"},
+		"children": []any{map[string]any{"name": "first"}, map[string]any{"name": "second"}},
+	}
+	if !reflect.DeepEqual(m, expected) {
+		t.Errorf("Expected %v, got %v", expected, m)
+	}
+
+	if _, err := DecodeToMap("not a struct"); err == nil {
+		t.Error("Expected error for non struct value, got nil")
+	}
+}
@@ -0,0 +1,116 @@
+package utils
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Violation is a validation rule broken by a field, Field is the path of the
+// field by its mapstructure tags, like targets[0].author.email.
+type Violation struct {
+	Field   string
+	Message string
+}
+
+func (v Violation) String() string {
+	if v.Field == "" {
+		return v.Message
+	}
+	return fmt.Sprintf("%s: %s", v.Field, v.Message)
+}
+
+// ValidationRule checks the value of a field, it returns why the value is
+// invalid or an empty string when it is valid.
+type ValidationRule func(v reflect.Value) string
+
+// ValidateModel checks every field of model against the rules named in its
+// validate tag, like `validate:"required,email"`, and returns every
+// violation. Rules after dive apply to the elements of a slice instead of
+// the slice itself, like `validate:"required,dive,email"`. Structs, and
+// slices of structs, are checked field by field. Rules missing from rules
+// are skipped, so that a subset of them can be checked.
+func ValidateModel(model interface{}, rules map[string]ValidationRule) []Violation {
+	v := reflect.ValueOf(model)
+	for v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+	return validateStruct(v, "", rules)
+}
+
+func validateStruct(v reflect.Value, prefix string, rules map[string]ValidationRule) []Violation {
+	violations := []Violation{}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("mapstructure"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		path := name
+		if prefix != "" {
+			path = prefix + "." + name
+		}
+
+		fieldValue := v.Field(i)
+		violations = append(violations, validateTag(fieldValue, path, field.Tag.Get("validate"), rules)...)
+		violations = append(violations, validateNested(fieldValue, path, rules)...)
+	}
+	return violations
+}
+
+func validateTag(v reflect.Value, path string, tag string, rules map[string]ValidationRule) []Violation {
+	violations := []Violation{}
+	dive := false
+	for _, name := range strings.Split(tag, ",") {
+		if name == "dive" {
+			dive = true
+			continue
+		}
+		rule, ok := rules[name]
+		if !ok {
+			continue
+		}
+
+		if !dive {
+			if message := rule(v); message != "" {
+				violations = append(violations, Violation{Field: path, Message: message})
+			}
+			continue
+		}
+		if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+			continue
+		}
+		for j := 0; j < v.Len(); j++ {
+			if message := rule(v.Index(j)); message != "" {
+				violations = append(violations, Violation{Field: fmt.Sprintf("%s[%d]", path, j), Message: message})
+			}
+		}
+	}
+	return violations
+}
+
+func validateNested(v reflect.Value, path string, rules map[string]ValidationRule) []Violation {
+	switch v.Kind() {
+	case reflect.Struct:
+		return validateStruct(v, path, rules)
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() != reflect.Struct {
+			return nil
+		}
+		violations := []Violation{}
+		for j := 0; j < v.Len(); j++ {
+			violations = append(violations, validateStruct(v.Index(j), fmt.Sprintf("%s[%d]", path, j), rules)...)
+		}
+		return violations
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_ValidateModel(t *testing.T) {
+	type Child struct {
+		Name string `mapstructure:"name" validate:"required"`
+	}
+	type Parent struct {
+		Title    string   `mapstructure:"title" validate:"required,short"`
+		Tags     []string `mapstructure:"tags" validate:"required,dive,short"`
+		Child    Child    `mapstructure:"child"`
+		Children []Child  `mapstructure:"children" validate:"unknown"`
+		internal string   `validate:"required"`
+	}
+
+	rules := map[string]ValidationRule{
+		"required": func(v reflect.Value) string {
+			if v.IsZero() {
+				return "is required"
+			}
+			return ""
+		},
+		"short": func(v reflect.Value) string {
+			if len(v.String()) > 3 {
+				return "is too long"
+			}
+			return ""
+		},
+	}
+
+	violations := ValidateModel(&Parent{
+		Title:    "parent",
+		Tags:     []string{"a", "long", "b", "longer"},
+		Children: []Child{{Name: "first"}, {}},
+	}, rules)
+
+	expected := []Violation{
+		{Field: "title", Message: "is too long"},
+		{Field: "tags[1]", Message: "is too long"},
+		{Field: "tags[3]", Message: "is too long"},
+		{Field: "child.name", Message: "is required"},
+		{Field: "children[1].name", Message: "is required"},
+	}
+	if !reflect.DeepEqual(violations, expected) {
+		t.Errorf("ValidateModel() got %v, want %v", violations, expected)
+	}
+
+	if violations := ValidateModel(Parent{Title: "ok", Tags: []string{"a"}, Child: Child{Name: "c"}}, rules); len(violations) != 0 {
+		t.Errorf("Expected no violations, got %v", violations)
+	}
+}
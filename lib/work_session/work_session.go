@@ -0,0 +1,100 @@
+package work_session
+
+import (
+	"path/filepath"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/HideyoshiNakazone/tracko/lib/config_model"
+	"github.com/HideyoshiNakazone/tracko/lib/repo"
+)
+
+// Session is a stretch of continuous work, rebuilt from the commits made
+// during it. Start is lead before the first commit and End is the last commit.
+type Session struct {
+	Start   time.Time
+	End     time.Time
+	Commits []repo.CommitRecord
+}
+
+func (s Session) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// Repos returns the aliases of the repos committed to during the session, in
+// order of first commit.
+func (s Session) Repos() []string {
+	var aliases []string
+	for _, record := range s.Commits {
+		alias := filepath.Base(record.RepoPath)
+		if !slices.Contains(aliases, alias) {
+			aliases = append(aliases, alias)
+		}
+	}
+	return aliases
+}
+
+// Cluster groups records into work sessions. Records are sorted by time and a
+// new session starts whenever a commit comes more than the session gap after
+// the previous one. Sessions can span several repos.
+func Cluster(records []repo.CommitRecord, cfg config_model.ConfigSessionModel) []Session {
+	sorted := slices.Clone(records)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].When.Before(sorted[j].When)
+	})
+
+	var sessions []Session
+	for _, record := range sorted {
+		if n := len(sessions); n > 0 && record.When.Sub(sessions[n-1].End) <= cfg.Gap() {
+			sessions[n-1].End = record.When
+			sessions[n-1].Commits = append(sessions[n-1].Commits, record)
+			continue
+		}
+
+		sessions = append(sessions, Session{
+			Start:   record.When.Add(-cfg.Lead()),
+			End:     record.When,
+			Commits: []repo.CommitRecord{record},
+		})
+	}
+	return sessions
+}
+
+// ProjectSession is a work session on a single client project.
+type ProjectSession struct {
+	Session
+	Project config_model.ConfigProjectModel
+}
+
+// ClusterByProject splits records by the project their repo is mapped to in
+// the config and clusters each project on its own, so that switching between
+// projects splits the time between them. Sessions are sorted by start.
+func ClusterByProject(records []repo.CommitRecord, cfg *config_model.ConfigModel) []ProjectSession {
+	type projectKey struct{ client, project string }
+
+	var keys []projectKey
+	projects := map[projectKey]config_model.ConfigProjectModel{}
+	byProject := map[projectKey][]repo.CommitRecord{}
+	for _, record := range records {
+		project := cfg.Project(record.RepoPath)
+		key := projectKey{project.Client(), project.Project()}
+		if _, ok := projects[key]; !ok {
+			keys = append(keys, key)
+			projects[key] = project
+		}
+		byProject[key] = append(byProject[key], record)
+	}
+
+	var sessions []ProjectSession
+	for _, key := range keys {
+		for _, session := range Cluster(byProject[key], cfg.Sessions()) {
+			sessions = append(sessions, ProjectSession{Session: session, Project: projects[key]})
+		}
+	}
+
+	sort.SliceStable(sessions, func(i, j int) bool {
+		return sessions[i].Start.Before(sessions[j].Start)
+	})
+	return sessions
+}
@@ -0,0 +1,152 @@
+package work_session
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/HideyoshiNakazone/tracko/lib/config_model"
+	"github.com/HideyoshiNakazone/tracko/lib/repo"
+)
+
+func buildSessions(t *testing.T, gap time.Duration, lead time.Duration) config_model.ConfigSessionModel {
+	t.Helper()
+
+	cfg, err := config_model.NewConfigBuilder().
+		WithTrackedAuthor("Test User", []string{"test@example.com"}).
+		WithTargetRepo("target", "Test User", "test@personal.example.com").
+		WithSessions(gap, lead).
+		Build()
+	if err != nil {
+		t.Fatalf("Failed to build config: %v", err)
+	}
+	return cfg.Sessions()
+}
+
+func Test_Cluster(t *testing.T) {
+	base := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	records := []repo.CommitRecord{
+		{RepoPath: "/src/frontend", Hash: "c", When: base.Add(90 * time.Minute)},
+		{RepoPath: "/src/backend", Hash: "a", When: base},
+		{RepoPath: "/src/backend", Hash: "b", When: base.Add(45 * time.Minute)},
+		{RepoPath: "/src/backend", Hash: "d", When: base.Add(5 * time.Hour)},
+	}
+
+	tests := []struct {
+		name      string
+		gap       time.Duration
+		lead      time.Duration
+		wantHash  [][]string
+		wantStart []time.Time
+		wantRepos [][]string
+	}{
+		{
+			name:      "Default thresholds",
+			gap:       config_model.DefaultSessionGap,
+			lead:      config_model.DefaultSessionLead,
+			wantHash:  [][]string{{"a", "b", "c"}, {"d"}},
+			wantStart: []time.Time{base.Add(-30 * time.Minute), base.Add(4*time.Hour + 30*time.Minute)},
+			wantRepos: [][]string{{"backend", "frontend"}, {"backend"}},
+		},
+		{
+			name:      "Short gap",
+			gap:       45 * time.Minute,
+			lead:      0,
+			wantHash:  [][]string{{"a", "b", "c"}, {"d"}},
+			wantStart: []time.Time{base, base.Add(5 * time.Hour)},
+			wantRepos: [][]string{{"backend", "frontend"}, {"backend"}},
+		},
+		{
+			name:      "Shorter gap",
+			gap:       30 * time.Minute,
+			lead:      15 * time.Minute,
+			wantHash:  [][]string{{"a"}, {"b"}, {"c"}, {"d"}},
+			wantStart: []time.Time{base.Add(-15 * time.Minute), base.Add(30 * time.Minute), base.Add(75 * time.Minute), base.Add(4*time.Hour + 45*time.Minute)},
+			wantRepos: [][]string{{"backend"}, {"backend"}, {"frontend"}, {"backend"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sessions := Cluster(records, buildSessions(t, tt.gap, tt.lead))
+
+			var hashes [][]string
+			var starts []time.Time
+			var repos [][]string
+			for _, session := range sessions {
+				var sessionHashes []string
+				for _, record := range session.Commits {
+					sessionHashes = append(sessionHashes, record.Hash)
+				}
+				hashes = append(hashes, sessionHashes)
+				starts = append(starts, session.Start)
+				repos = append(repos, session.Repos())
+
+				if last := session.Commits[len(session.Commits)-1]; !session.End.Equal(last.When) {
+					t.Errorf("Expected session to end at %v, got %v", last.When, session.End)
+				}
+			}
+
+			if !reflect.DeepEqual(hashes, tt.wantHash) {
+				t.Errorf("Expected sessions %v, got %v", tt.wantHash, hashes)
+			}
+			if !reflect.DeepEqual(starts, tt.wantStart) {
+				t.Errorf("Expected starts %v, got %v", tt.wantStart, starts)
+			}
+			if !reflect.DeepEqual(repos, tt.wantRepos) {
+				t.Errorf("Expected repos %v, got %v", tt.wantRepos, repos)
+			}
+		})
+	}
+}
+
+func Test_Cluster_Empty(t *testing.T) {
+	if sessions := Cluster(nil, config_model.NewDefaultSessionModel()); len(sessions) != 0 {
+		t.Errorf("Expected no sessions, got %v", sessions)
+	}
+}
+
+func Test_ClusterByProject(t *testing.T) {
+	base := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	records := []repo.CommitRecord{
+		{RepoPath: "/src/backend", Hash: "a", When: base},
+		{RepoPath: "/src/frontend", Hash: "b", When: base.Add(30 * time.Minute)},
+		{RepoPath: "/src/backend", Hash: "c", When: base.Add(time.Hour)},
+		{RepoPath: "/src/blog", Hash: "d", When: base.Add(90 * time.Minute)},
+	}
+
+	cfg, err := config_model.NewConfigBuilder().
+		WithTrackedAuthor("Test User", []string{"test@example.com"}).
+		WithTargetRepo("target", "Test User", "test@personal.example.com").
+		WithSessions(2*time.Hour, 0).
+		WithProject("backend", "Acme", "Platform", "").
+		WithProject("/src/frontend", "Acme", "Platform", "").
+		Build()
+	if err != nil {
+		t.Fatalf("Failed to build config: %v", err)
+	}
+
+	sessions := ClusterByProject(records, cfg)
+
+	type summary struct {
+		Client  string
+		Project string
+		Hashes  string
+	}
+	var got []summary
+	for _, session := range sessions {
+		hashes := ""
+		for _, record := range session.Commits {
+			hashes += record.Hash
+		}
+		got = append(got, summary{session.Project.Client(), session.Project.Project(), hashes})
+	}
+
+	expected := []summary{
+		{"Acme", "Platform", "abc"},
+		{"", "blog", "d"},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected sessions %+v, got %+v", expected, got)
+	}
+}